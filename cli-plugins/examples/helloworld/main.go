@@ -82,8 +82,7 @@ func main() {
 				}
 
 				_, _ = fmt.Fprintln(dockerCLI.Out(), "Hello", who)
-				dockerCLI.ConfigFile().SetPluginConfig("helloworld", "lastwho", who)
-				return dockerCLI.ConfigFile().Save()
+				return dockerCLI.ConfigFile().UpdatePluginConfig("helloworld", "lastwho", who)
 			},
 		}
 
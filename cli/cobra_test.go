@@ -100,6 +100,26 @@ func TestCommandAliases(t *testing.T) {
 	assert.Equal(t, commandAliases(sub), "custom alias, custom alias 2")
 }
 
+func TestAliasCommands(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	sub1 := &cobra.Command{Use: "sub1", Run: func(cmd *cobra.Command, args []string) {}}
+	lsa := &cobra.Command{
+		Use:         "lsa",
+		Annotations: map[string]string{CommandAnnotationAlias: "sub1 -a"},
+		Run:         func(cmd *cobra.Command, args []string) {},
+	}
+
+	root.AddCommand(sub1)
+	assert.Assert(t, !hasAliasCommands(root))
+
+	root.AddCommand(lsa)
+	assert.Assert(t, hasAliasCommands(root))
+	assert.Assert(t, is.Len(aliasCommands(root), 1))
+	assert.Equal(t, aliasCommands(root)[0], lsa)
+	assert.Assert(t, is.Len(operationSubCommands(root), 1))
+	assert.Equal(t, operationSubCommands(root)[0], sub1)
+}
+
 func TestDecoratedName(t *testing.T) {
 	root := &cobra.Command{Use: "root"}
 	topLevelCommand := &cobra.Command{Use: "pluginTopLevelCommand"}
@@ -68,6 +68,8 @@ type DockerCli struct {
 	serverInfo         ServerInfo
 	contextStore       store.Store
 	currentContext     string
+	contextSelection   ContextSelection
+	projectContext     *ProjectContext
 	init               sync.Once
 	initErr            error
 	dockerEndpoint     docker.Endpoint
@@ -136,6 +138,7 @@ func (cli *DockerCli) ConfigFile() *configfile.ConfigFile {
 	// TODO(thaJeztah): when would this happen? Is this only in tests (where cli.Initialize() is not called first?)
 	if cli.configFile == nil {
 		cli.configFile = config.LoadDefaultConfigFile(cli.err)
+		setOAuthRefreshContext(cli.baseCtx, cli.configFile, cli.err)
 	}
 	return cli.configFile
 }
@@ -247,11 +250,14 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 		}
 	}
 
+	var err error
 	cli.options = opts
 	cli.configFile = config.LoadDefaultConfigFile(cli.err)
-	cli.currentContext = resolveContextName(cli.options, cli.configFile)
+	setOAuthRefreshContext(cli.baseCtx, cli.configFile, cli.err)
+	cli.contextSelection, cli.projectContext = resolveContext(cli.options, cli.configFile, cli.err)
+	cli.currentContext = cli.contextSelection.Name
 	cli.contextStore = &ContextStoreWithDefault{
-		Store: store.New(config.ContextStoreDir(), *cli.contextStoreConfig),
+		Store: newContextStore(*cli.contextStoreConfig, cli.configFile),
 		Resolver: func() (*DefaultContext, error) {
 			return resolveDefaultContext(cli.options, *cli.contextStoreConfig)
 		},
@@ -266,20 +272,32 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 	}
 	filterResourceAttributesEnvvar()
 
-	// early return if GODEBUG is already set or the docker context is
-	// the default context, i.e. is a virtual context where we won't override
-	// any GODEBUG values.
-	if v := os.Getenv("GODEBUG"); cli.currentContext == DefaultContextName || v != "" {
+	// early return if the docker context is the default context, i.e. is a
+	// virtual context where we won't override any configuration, environment
+	// variables, or GODEBUG values.
+	if cli.currentContext == DefaultContextName {
 		return nil
 	}
 	meta, err := cli.contextStore.GetMetadata(cli.currentContext)
-	if err == nil {
-		setGoDebug(meta)
+	if err != nil {
+		// errors are reported when connecting to the context's endpoint.
+		return nil
 	}
-
+	if err := applyContextDefaults(cli.configFile, cli.contextStore, meta); err != nil {
+		// Don't fail, so that the context can be fixed or removed.
+		_, _ = fmt.Fprintln(cli.Err(), "WARNING:", err)
+	}
+	setGoDebug(meta)
 	return nil
 }
 
+// newContextStore returns the context store, which encrypts the TLS keys of
+// contexts at rest if a key store is configured (see [ContextTLSKeyStore]).
+// SSH identity files are not encrypted, as they are passed to the ssh binary.
+func newContextStore(storeConfig store.Config, configFile *configfile.ConfigFile) *store.ContextStore {
+	return store.New(config.ContextStoreDir(), storeConfig, store.WithTLSEncryption(NewContextTLSKeyStore(configFile), dcontext.TLSKeyFile))
+}
+
 // NewAPIClientFromFlags creates a new APIClient from command line flags
 func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.ConfigFile) (client.APIClient, error) {
 	if opts.Context != "" && len(opts.Hosts) > 0 {
@@ -288,18 +306,31 @@ func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.
 
 	storeConfig := DefaultContextStoreConfig()
 	contextStore := &ContextStoreWithDefault{
-		Store: store.New(config.ContextStoreDir(), storeConfig),
+		Store: newContextStore(storeConfig, configFile),
 		Resolver: func() (*DefaultContext, error) {
 			return resolveDefaultContext(opts, storeConfig)
 		},
 	}
-	endpoint, err := resolveDockerEndpoint(contextStore, resolveContextName(opts, configFile))
+	selection, _ := resolveContext(opts, configFile, io.Discard)
+	endpoint, err := resolveDockerEndpoint(contextStore, selection.Name)
 	if err != nil {
 		return nil, fmt.Errorf("unable to resolve docker endpoint: %w", err)
 	}
 	return newAPIClientFromEndpoint(endpoint, configFile, client.WithUserAgent(UserAgent()))
 }
 
+// NewAPIClientForContext creates a new APIClient for the docker endpoint of
+// the given context. Unlike [DockerCli.Client], the client is not initialized
+// and does not negotiate the API version; it's the caller's responsibility to
+// close the client.
+func NewAPIClientForContext(s store.Reader, contextName string, configFile *configfile.ConfigFile, extraOpts ...client.Opt) (client.APIClient, error) {
+	endpoint, err := resolveDockerEndpoint(s, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve docker endpoint: %w", err)
+	}
+	return newAPIClientFromEndpoint(endpoint, configFile, append([]client.Opt{client.WithUserAgent(UserAgent())}, extraOpts...)...)
+}
+
 func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile, extraOpts ...client.Opt) (client.APIClient, error) {
 	opts, err := ep.ClientOpts()
 	if err != nil {
@@ -405,9 +436,11 @@ func (cli *DockerCli) ContextStore() store.Store {
 //
 //  1. The "--context" command-line option.
 //  2. The "DOCKER_CONTEXT" environment variable ([EnvOverrideContext]).
-//  3. The current context as configured through the in "currentContext"
+//  3. The context set in a [ProjectContextFileName] file in the current
+//     working directory, or one of its parent directories.
+//  4. The current context as configured through the in "currentContext"
 //     field in the CLI configuration file ("~/.docker/config.json").
-//  4. If no context is configured, use the "default" context.
+//  5. If no context is configured, use the "default" context.
 //
 // # Fallbacks for backward-compatibility
 //
@@ -430,30 +463,54 @@ func (cli *DockerCli) CurrentContext() string {
 	return cli.currentContext
 }
 
-// CurrentContext returns the current context name, based on flags,
-// environment variables and the cli configuration file. It does not
-// validate if the given context exists or if it's valid; errors may
-// occur when trying to use it.
+// resolveContext returns the current context, based on flags, environment
+// variables, the project's [ProjectContextFileName] file, and the cli
+// configuration file. It does not validate if the given context exists or
+// if it's valid; errors may occur when trying to use it. The project's file
+// is only looked up if the context is not selected by flags or environment
+// variables, and a warning is written to errOut if the file is invalid.
 //
 // Refer to [DockerCli.CurrentContext] above for further details.
-func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) string {
+func resolveContext(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile, errOut io.Writer) (ContextSelection, *ProjectContext) {
 	if opts != nil && opts.Context != "" {
-		return opts.Context
+		return ContextSelection{Name: opts.Context, Source: ContextSourceFlag, Origin: "--context option"}, nil
 	}
 	if opts != nil && len(opts.Hosts) > 0 {
-		return DefaultContextName
+		return ContextSelection{Name: DefaultContextName, Source: ContextSourceFlag, Origin: "--host option"}, nil
 	}
 	if os.Getenv(client.EnvOverrideHost) != "" {
-		return DefaultContextName
+		return ContextSelection{Name: DefaultContextName, Source: ContextSourceEnv, Origin: client.EnvOverrideHost + " environment variable"}, nil
 	}
 	if ctxName := os.Getenv(EnvOverrideContext); ctxName != "" {
-		return ctxName
+		return ContextSelection{Name: ctxName, Source: ContextSourceEnv, Origin: EnvOverrideContext + " environment variable"}, nil
+	}
+
+	var pc *ProjectContext
+	if cwd, err := os.Getwd(); err == nil {
+		if pc, err = LoadProjectContext(cwd); err != nil {
+			_, _ = fmt.Fprintln(errOut, "WARNING:", err)
+		}
+	}
+	if pc != nil && pc.Context != "" {
+		return ContextSelection{Name: pc.Context, Source: ContextSourceProject, Origin: pc.Filename}, pc
 	}
 	if cfg != nil && cfg.CurrentContext != "" {
 		// We don't validate if this context exists: errors may occur when trying to use it.
-		return cfg.CurrentContext
+		return ContextSelection{Name: cfg.CurrentContext, Source: ContextSourceConfig, Origin: currentContextOrigin(cfg)}, pc
 	}
-	return DefaultContextName
+	return ContextSelection{Name: DefaultContextName, Source: ContextSourceDefault}, pc
+}
+
+// currentContextOrigin returns the configuration file that sets the
+// "currentContext" option, which may be the system configuration file.
+func currentContextOrigin(cfg *configfile.ConfigFile) string {
+	filename := cfg.Filename
+	for _, l := range cfg.Layers {
+		if v, ok := l.Data["currentContext"]; ok && v == cfg.CurrentContext {
+			filename = l.Filename
+		}
+	}
+	return filename
 }
 
 // DockerEndpoint returns the current docker endpoint
@@ -532,14 +589,19 @@ func (cli *DockerCli) initialize() error {
 			cli.initErr = fmt.Errorf("unable to resolve docker endpoint: %w", cli.initErr)
 			return
 		}
+		if cli.baseCtx == nil {
+			cli.baseCtx = context.Background()
+		}
 		if cli.client == nil {
-			if cli.client, cli.initErr = newAPIClientFromEndpoint(cli.dockerEndpoint, cli.configFile, cli.clientOpts...); cli.initErr != nil {
+			if cn := cli.CurrentContext(); len(cli.dockerEndpoint.FailoverHosts) > 0 && cn != DefaultContextName {
+				cli.dockerEndpoint, cli.client, cli.initErr = cli.connectFailover(cn, cli.dockerEndpoint)
+			} else {
+				cli.client, cli.initErr = newAPIClientFromEndpoint(cli.dockerEndpoint, cli.configFile, cli.clientOpts...)
+			}
+			if cli.initErr != nil {
 				return
 			}
 		}
-		if cli.baseCtx == nil {
-			cli.baseCtx = context.Background()
-		}
 		cli.initializeFromClient()
 	})
 	return cli.initErr
@@ -0,0 +1,59 @@
+package cliconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newConfigFileCommand)
+}
+
+// newConfigFileCommand returns a cobra command for `config-file` subcommands
+func newConfigFileCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config-file COMMAND",
+		Short: "Manage the CLI configuration",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_, _ = fmt.Fprint(dockerCLI.Err(), "\n"+cmd.UsageString())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newGetCommand(dockerCLI),
+		newListCommand(dockerCLI),
+		newSetCommand(dockerCLI),
+		newUnsetCommand(dockerCLI),
+	)
+	return cmd
+}
+
+// completeKeys completes the names of configuration options, and the keys of
+// the options that are currently set.
+func completeKeys(dockerCLI command.Cli) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		keys := configfile.OptionNames()
+		if settings, err := dockerCLI.ConfigFile().Settings(); err == nil {
+			for _, s := range settings {
+				keys = append(keys, s.Key)
+			}
+		}
+		var completions []string
+		for _, k := range keys {
+			if strings.HasPrefix(k, toComplete) {
+				completions = append(completions, k)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,95 @@
+package cliconfig
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+const (
+	defaultTableFormat = "table {{.Key}}\t{{.Value}}\t{{.Layer}}\t{{.File}}"
+
+	keyHeader   = "KEY"
+	valueHeader = "VALUE"
+	layerHeader = "LAYER"
+	fileHeader  = "FILE"
+)
+
+// newFormat returns a Format for rendering using a settingContext.
+func newFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		if quiet {
+			return "{{.Key}}"
+		}
+		return defaultTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return "key: {{.Key}}"
+		}
+		return "key: {{.Key}}\nvalue: {{.Value}}\nlayer: {{.Layer}}\nfile: {{.File}}\n"
+	}
+	return formatter.Format(source)
+}
+
+// formatWrite writes the configuration settings using the context.
+func formatWrite(fmtCtx formatter.Context, settings []configfile.Setting) error {
+	settingCtx := &settingContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Key":   keyHeader,
+				"Value": valueHeader,
+				"Layer": layerHeader,
+				"File":  fileHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(settingCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, s := range settings {
+			if err := format(&settingContext{s: s}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type settingContext struct {
+	formatter.HeaderContext
+	s configfile.Setting
+}
+
+func (c *settingContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *settingContext) Key() string {
+	return c.s.Key
+}
+
+// valueReplacer escapes tabs and newlines (which are commonly used in format
+// options) so that they don't break the table output.
+var valueReplacer = strings.NewReplacer("\t", `\t`, "\n", `\n`)
+
+// Value returns the value of the setting. Values that are not a string are
+// formatted as JSON.
+func (c *settingContext) Value() string {
+	if s, ok := c.s.Value.(string); ok {
+		return valueReplacer.Replace(s)
+	}
+	v, err := json.Marshal(c.s.Value)
+	if err != nil {
+		return ""
+	}
+	return string(v)
+}
+
+func (c *settingContext) Layer() string {
+	return string(c.s.Layer)
+}
+
+func (c *settingContext) File() string {
+	return c.s.Filename
+}
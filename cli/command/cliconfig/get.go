@@ -0,0 +1,56 @@
+package cliconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+type getOptions struct {
+	key  string
+	json bool
+}
+
+// newGetCommand creates a new `docker config-file get` command
+func newGetCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts getOptions
+
+	cmd := &cobra.Command{
+		Use:   "get [OPTIONS] KEY",
+		Short: "Print the value of a configuration option",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.key = args[0]
+			return runGet(dockerCLI, opts)
+		},
+		ValidArgsFunction:     completeKeys(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.json, "json", false, "Print the value as JSON")
+	return cmd
+}
+
+func runGet(dockerCLI command.Cli, opts getOptions) error {
+	v, ok, err := dockerCLI.ConfigFile().GetOption(opts.key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("configuration option is not set: %s", opts.key)
+	}
+	if s, isString := v.(string); isString && !opts.json {
+		_, _ = fmt.Fprintln(dockerCLI.Out(), s)
+		return nil
+	}
+	out, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), string(out))
+	return nil
+}
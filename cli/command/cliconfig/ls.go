@@ -0,0 +1,47 @@
+package cliconfig
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	format string
+	quiet  bool
+}
+
+// newListCommand creates a new `docker config-file ls` command
+func newListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List configuration options, and the configuration file that sets them",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show option names")
+	return cmd
+}
+
+func runList(dockerCLI command.Cli, opts listOptions) error {
+	settings, err := dockerCLI.ConfigFile().Settings()
+	if err != nil {
+		return err
+	}
+	return formatWrite(formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newFormat(opts.format, opts.quiet),
+	}, settings)
+}
@@ -0,0 +1,55 @@
+package cliconfig
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func newTestConfigFile(t *testing.T) *configfile.ConfigFile {
+	t.Helper()
+	configFile := configfile.New("/home/user/.docker/config.json")
+	assert.NilError(t, configFile.LoadFromReader(strings.NewReader(`{
+	"psFormat": "table {{.ID}}\t{{.Names}}",
+	"features": {"hooks": "false"}
+}`)))
+	assert.NilError(t, configFile.AddLayer(configfile.LayerSystem, "/etc/docker/cli-config.json", strings.NewReader(`{
+	"credsStore": "secretservice",
+	"proxies": {"default": {"httpProxy": "http://proxy.example.com:3128"}},
+	"features": {"hooks": "true", "containerd-snapshotter": "true"}
+}`)))
+	assert.NilError(t, configFile.AddLayer(configfile.LayerProject, "/src/project/.docker/config.json", strings.NewReader(`{
+	"imagesFormat": "table {{.Repository}}",
+	"pruneFilters": ["label=com.example.project=shop"]
+}`)))
+	configFile.DetachKeys = "ctrl-x"
+	return configFile
+}
+
+func TestList(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{name: "table", golden: "ls-table.golden"},
+		{name: "quiet", args: []string{"--quiet"}, golden: "ls-quiet.golden"},
+		{name: "json", args: []string{"--format", "json"}, golden: "ls-json.golden"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetConfigFile(newTestConfigFile(t))
+			cmd := newListCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(append([]string{}, tc.args...))
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cliconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/spf13/cobra"
+)
+
+type setOptions struct {
+	key   string
+	value string
+}
+
+// newSetCommand creates a new `docker config-file set` command
+func newSetCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts setOptions
+
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a configuration option",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.key = args[0]
+			opts.value = args[1]
+			return runSet(cmd.Root(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completeKeys(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+	return cmd
+}
+
+func runSet(rootCmd *cobra.Command, dockerCLI command.Cli, opts setOptions) error {
+	if name, ok := strings.CutPrefix(opts.key, "aliases."); ok && name != "builder" {
+		// The "builder" alias is used to select the builder plugin, and is
+		// the only alias that overrides a built-in command.
+		if c, _, err := rootCmd.Find([]string{name}); err == nil && c != rootCmd && !pluginmanager.IsPluginCommand(c) {
+			return fmt.Errorf("invalid configuration option: %s: aliases cannot override built-in command %q", opts.key, c.Name())
+		}
+	}
+	if opts.key == "currentContext" && opts.value != "default" {
+		if _, err := dockerCLI.ContextStore().GetMetadata(opts.value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", opts.key, err)
+		}
+	}
+	return dockerCLI.ConfigFile().Update(func(c *configfile.ConfigFile) error {
+		return c.SetOption(opts.key, opts.value)
+	})
+}
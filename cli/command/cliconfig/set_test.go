@@ -0,0 +1,142 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package cliconfig
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func runCommand(t *testing.T, cmd *cobra.Command, args ...string) error {
+	t.Helper()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(append([]string{}, args...))
+	return cmd.Execute()
+}
+
+func TestGet(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		expected    string
+		expectedErr string
+	}{
+		{name: "string", args: []string{"psFormat"}, expected: "table {{.ID}}\t{{.Names}}\n"},
+		{name: "json", args: []string{"--json", "credsStore"}, expected: "\"secretservice\"\n"},
+		{name: "map", args: []string{"features"}, expected: "{\n    \"containerd-snapshotter\": \"true\",\n    \"hooks\": \"false\"\n}\n"},
+		{name: "dotted", args: []string{"proxies.default.httpProxy"}, expected: "http://proxy.example.com:3128\n"},
+		{name: "not set", args: []string{"nodesFormat"}, expectedErr: "configuration option is not set: nodesFormat"},
+		{name: "unknown", args: []string{"noSuchOption"}, expectedErr: "unknown configuration option: noSuchOption"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetConfigFile(newTestConfigFile(t))
+			err := runCommand(t, newGetCommand(cli), tc.args...)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
+
+func newTestUserConfigFile(t *testing.T, content string) *configfile.ConfigFile {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "config.json")
+	assert.NilError(t, os.WriteFile(filename, []byte(content), 0o600))
+	configFile := configfile.New(filename)
+	assert.NilError(t, configFile.LoadFromReader(strings.NewReader(content)))
+	assert.NilError(t, configFile.AddLayer(configfile.LayerSystem, "/etc/docker/cli-config.json", strings.NewReader(`{
+	"credsStore": "secretservice"
+}`)))
+	return configFile
+}
+
+func TestSet(t *testing.T) {
+	configFile := newTestUserConfigFile(t, `{"psFormat": "table {{.ID}}"}`)
+	contextStore := store.New(t.TempDir(), store.NewConfig(func() any { return &command.DockerContext{} }))
+	assert.NilError(t, contextStore.CreateOrUpdate(store.Metadata{Name: "my-context", Metadata: command.DockerContext{}}))
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(configFile)
+	cli.SetContextStore(contextStore)
+
+	assert.NilError(t, runCommand(t, newSetCommand(cli), "currentContext", "my-context"))
+	assert.NilError(t, runCommand(t, newSetCommand(cli), "plugins.buildx.builder", "my-builder"))
+	assert.NilError(t, runCommand(t, newSetCommand(cli), "pruneFilters", "label=foo,until=24h"))
+
+	rootCmd := &cobra.Command{Use: "docker"}
+	rootCmd.AddCommand(&cobra.Command{Use: "ps", Run: func(*cobra.Command, []string) {}}, newSetCommand(cli))
+	err := runCommand(t, rootCmd, "set", "aliases.ps", "ps -a")
+	assert.Check(t, is.Error(err, `invalid configuration option: aliases.ps: aliases cannot override built-in command "ps"`))
+	assert.NilError(t, runCommand(t, rootCmd, "set", "aliases.lsa", "ps -a"))
+	assert.Check(t, is.Equal(configFile.Aliases["lsa"], "ps -a"))
+
+	err = runCommand(t, newSetCommand(cli), "features.hooks", "maybe")
+	assert.Check(t, is.Error(err, `invalid value for features.hooks: "maybe" is not a boolean`))
+	err = runCommand(t, newSetCommand(cli), "currentContext", "no-such-context")
+	assert.Check(t, is.ErrorContains(err, "invalid value for currentContext"))
+	assert.Check(t, is.Equal(configFile.CurrentContext, "my-context"))
+
+	v, _ := configFile.PluginConfig("buildx", "builder")
+	assert.Check(t, is.Equal(v, "my-builder"))
+	assert.Check(t, is.DeepEqual(configFile.PruneFilters, []string{"label=foo", "until=24h"}))
+
+	// values inherited from the system configuration are not written to the
+	// user's configuration file.
+	data, err := os.ReadFile(configFile.Filename)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), `{
+	"auths": {},
+	"psFormat": "table {{.ID}}",
+	"pruneFilters": [
+		"label=foo",
+		"until=24h"
+	],
+	"currentContext": "my-context",
+	"plugins": {
+		"buildx": {
+			"builder": "my-builder"
+		}
+	},
+	"aliases": {
+		"lsa": "ps -a"
+	}
+}`))
+}
+
+func TestUnset(t *testing.T) {
+	configFile := newTestUserConfigFile(t, `{"psFormat": "table {{.ID}}", "features": {"hooks": "true"}}`)
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(configFile)
+
+	assert.NilError(t, runCommand(t, newUnsetCommand(cli), "features.hooks"))
+	assert.Check(t, is.Len(configFile.Features, 0))
+
+	err := runCommand(t, newUnsetCommand(cli), "features.hooks")
+	assert.Check(t, is.Error(err, "configuration option is not set: features.hooks"))
+
+	err = runCommand(t, newUnsetCommand(cli), "credsStore")
+	assert.Check(t, is.Error(err, "configuration option credsStore is set in the system configuration file (/etc/docker/cli-config.json), and cannot be removed"))
+
+	data, err := os.ReadFile(configFile.Filename)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), `{
+	"auths": {},
+	"psFormat": "table {{.ID}}"
+}`))
+}
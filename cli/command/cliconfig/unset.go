@@ -0,0 +1,62 @@
+package cliconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/spf13/cobra"
+)
+
+// newUnsetCommand creates a new `docker config-file unset` command
+func newUnsetCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset KEY",
+		Short: "Remove a configuration option",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnset(dockerCLI, args[0])
+		},
+		ValidArgsFunction:     completeKeys(dockerCLI),
+		DisableFlagsInUseLine: true,
+	}
+	return cmd
+}
+
+func runUnset(dockerCLI command.Cli, key string) error {
+	cfg := dockerCLI.ConfigFile()
+
+	// Only the user's configuration file is updated, so check if the option
+	// is inherited from the system or project configuration file.
+	var origin *configfile.Setting
+	if settings, err := cfg.Settings(); err == nil {
+		for _, s := range settings {
+			if s.Key == key || strings.HasPrefix(s.Key, key+".") {
+				origin = &s
+				break
+			}
+		}
+	}
+
+	var found bool
+	err := cfg.Update(func(c *configfile.ConfigFile) error {
+		removed, err := c.UnsetOption(key)
+		if c != cfg {
+			// c is the user's configuration file as stored on disk.
+			found = removed
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		if origin != nil && origin.Layer != configfile.LayerUser && origin.Filename != "" {
+			return fmt.Errorf("configuration option %s is set in the %s configuration file (%s), and cannot be removed", key, origin.Layer, origin.Filename)
+		}
+		return fmt.Errorf("configuration option is not set: %s", key)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 	"github.com/docker/cli/cli/command"
 	_ "github.com/docker/cli/cli/command/builder"
 	_ "github.com/docker/cli/cli/command/checkpoint"
+	_ "github.com/docker/cli/cli/command/cliconfig"
 	_ "github.com/docker/cli/cli/command/config"
 	_ "github.com/docker/cli/cli/command/container"
 	_ "github.com/docker/cli/cli/command/context"
@@ -52,6 +52,7 @@ type fakeClient struct {
 	containerRenameFunc     func(ctx context.Context, oldName, newName string) error
 	containerCommitFunc     func(ctx context.Context, container string, options client.ContainerCommitOptions) (client.ContainerCommitResult, error)
 	containerPauseFunc      func(ctx context.Context, container string, options client.ContainerPauseOptions) (client.ContainerPauseResult, error)
+	containerStatsFunc      func(ctx context.Context, container string, options client.ContainerStatsOptions) (client.ContainerStatsResult, error)
 	Version                 string
 }
 
@@ -62,6 +63,13 @@ func (f *fakeClient) ContainerList(_ context.Context, options client.ContainerLi
 	return client.ContainerListResult{}, nil
 }
 
+func (f *fakeClient) ContainerStats(ctx context.Context, containerID string, options client.ContainerStatsOptions) (client.ContainerStatsResult, error) {
+	if f.containerStatsFunc != nil {
+		return f.containerStatsFunc(ctx, containerID, options)
+	}
+	return client.ContainerStatsResult{}, nil
+}
+
 func (f *fakeClient) ContainerInspect(_ context.Context, containerID string, _ client.ContainerInspectOptions) (client.ContainerInspectResult, error) {
 	if f.inspectFunc != nil {
 		return f.inspectFunc(containerID)
@@ -83,6 +83,7 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	// TODO(thaJeztah): consider adding platform as "image create option" on containerOptions
 	flags.StringVar(&options.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"), "Set platform if server is multi-platform capable")
 	_ = flags.SetAnnotation("platform", "version", []string{"1.32"})
+	_ = flags.SetAnnotation("platform", command.FlagAnnotationDefaultPlatform, nil)
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
 
 	// TODO(thaJeztah): DEPRECATED: remove in v29.1 or v30
@@ -57,6 +57,7 @@ func newPsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.IntVarP(&options.last, "last", "n", -1, "Show n last created containers (includes all states)")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	_ = flags.SetAnnotation("filter", command.FlagAnnotationLabelFilter, nil)
 
 	return cmd
 }
@@ -1,12 +1,15 @@
 package container
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/reconnect"
 	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
@@ -19,6 +22,7 @@ type logsOptions struct {
 	timestamps bool
 	details    bool
 	tail       string
+	reconnect  bool
 
 	container string
 }
@@ -50,6 +54,7 @@ func newLogsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
 	flags.BoolVar(&opts.details, "details", false, "Show extra details provided to logs")
 	flags.StringVarP(&opts.tail, "tail", "n", "all", "Number of lines to show from the end of the logs")
+	flags.BoolVar(&opts.reconnect, "reconnect", false, "Reconnect when the connection to the daemon is lost, and resume from the last line")
 	return cmd
 }
 
@@ -58,6 +63,9 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	if err != nil {
 		return err
 	}
+	if opts.reconnect {
+		return runLogsReconnect(ctx, dockerCli, c.Container.ID, c.Container.Config.Tty, opts)
+	}
 
 	resp, err := dockerCli.Client().ContainerLogs(ctx, c.Container.ID, client.ContainerLogsOptions{
 		ShowStdout: true,
@@ -81,3 +89,118 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	}
 	return err
 }
+
+// runLogsReconnect fetches the logs of a container, and fetches the logs
+// again when the connection to the daemon is lost. Logs are requested with
+// timestamps, so that the logs can be resumed from the last line.
+func runLogsReconnect(ctx context.Context, dockerCli command.Cli, containerID string, tty bool, opts *logsOptions) error {
+	var (
+		resume   reconnect.Resume
+		backoff  reconnect.Backoff
+		since    = opts.since
+		tail     = opts.tail
+		resumeAt time.Time
+		lost     bool
+	)
+	stdout := &logWriter{out: dockerCli.Out(), resume: &resume, timestamps: opts.timestamps}
+	stderr := &logWriter{out: dockerCli.Err(), resume: &resume, timestamps: opts.timestamps}
+	for connected := false; ; connected = true {
+		resp, err := dockerCli.Client().ContainerLogs(ctx, containerID, client.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Since:      since,
+			Until:      opts.until,
+			Timestamps: true,
+			Follow:     opts.follow,
+			Tail:       tail,
+			Details:    opts.details,
+		})
+		if err == nil {
+			if lost {
+				reconnect.WriteResumed(dockerCli.Err(), resumeAt)
+				lost = false
+			}
+			backoff.Reset()
+			if tty {
+				_, err = io.Copy(stdout, resp)
+			} else {
+				_, err = stdcopy.StdCopy(stdout, stderr, resp)
+			}
+			_ = resp.Close()
+			if err == nil {
+				stdout.flush()
+				stderr.flush()
+				return nil
+			}
+		} else if !connected {
+			return err
+		}
+		if !reconnect.IsDisconnect(err) {
+			return err
+		}
+
+		// Discard incomplete lines, which are sent again after resuming.
+		stdout.buf, stderr.buf = stdout.buf[:0], stderr.buf[:0]
+		reconnect.WriteLost(dockerCli.Err(), err, backoff.Delay())
+		lost = true
+		if resumeAt = resume.Last(); !resumeAt.IsZero() {
+			since, tail = reconnect.Since(resumeAt), "all"
+		}
+		if err := backoff.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// logWriter writes the lines of logs that are prefixed with a timestamp,
+// and skips lines that were written before the logs were resumed. The
+// timestamps are removed unless timestamps is set.
+type logWriter struct {
+	out        io.Writer
+	resume     *reconnect.Resume
+	timestamps bool
+	buf        []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	var line []byte
+	for {
+		var ok bool
+		line, w.buf, ok = bytes.Cut(w.buf, []byte{'\n'})
+		if !ok {
+			break
+		}
+		if err := w.writeLine(line, true); err != nil {
+			return 0, err
+		}
+	}
+	w.buf = append(line[:0:0], line...)
+	return len(p), nil
+}
+
+// flush writes the last line if it does not end with a newline.
+func (w *logWriter) flush() {
+	if len(w.buf) > 0 {
+		_ = w.writeLine(w.buf, false)
+		w.buf = w.buf[:0]
+	}
+}
+
+func (w *logWriter) writeLine(line []byte, newline bool) error {
+	if ts, msg, ok := bytes.Cut(line, []byte{' '}); ok {
+		if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+			if w.resume.Seen(t) {
+				return nil
+			}
+			if !w.timestamps {
+				line = msg
+			}
+		}
+	}
+	if newline {
+		line = append(line, '\n')
+	}
+	_, err := w.out.Write(line)
+	return err
+}
@@ -2,7 +2,10 @@
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/api/types/container"
@@ -58,3 +61,40 @@ func TestRunLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestRunLogsReconnect(t *testing.T) {
+	var calls []client.ContainerLogsOptions
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (client.ContainerInspectResult, error) {
+			return client.ContainerInspectResult{
+				Container: container.InspectResponse{
+					ID:     "abc123",
+					Config: &container.Config{Tty: true},
+					State:  &container.State{Running: true},
+				},
+			}, nil
+		},
+		logFunc: func(_ string, opts client.ContainerLogsOptions) (client.ContainerLogsResult, error) {
+			calls = append(calls, opts)
+			if len(calls) == 1 {
+				// the connection is lost while receiving the last line.
+				return io.NopCloser(io.MultiReader(
+					strings.NewReader("2026-10-19T10:00:01Z one\n2026-10-19T10:00:02Z two\n2026-10-19T10:00:02Z thr"),
+					iotest.ErrReader(io.ErrUnexpectedEOF),
+				)), nil
+			}
+			return mockContainerLogsResult("2026-10-19T10:00:02Z two\n2026-10-19T10:00:02Z three\n2026-10-19T10:00:03Z four\n"), nil
+		},
+	})
+
+	err := runLogs(context.TODO(), cli, &logsOptions{follow: true, tail: "2", reconnect: true, container: "abc123"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "one\ntwo\nthree\nfour\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "--- connection to the daemon lost: unexpected EOF; reconnecting in 500ms\n--- reconnected; resuming from 2026-10-19T10:00:02Z\n"))
+
+	assert.Assert(t, is.Len(calls, 2))
+	assert.Check(t, calls[0].Timestamps)
+	assert.Check(t, is.Equal(calls[0].Tail, "2"))
+	assert.Check(t, is.Equal(calls[1].Since, "2026-10-19T10:00:02Z"))
+	assert.Check(t, is.Equal(calls[1].Tail, "all"))
+}
@@ -18,6 +18,7 @@
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
 	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/internal/reconnect"
 	"github.com/moby/moby/api/types/events"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
@@ -37,6 +38,11 @@ type StatsOptions struct {
 	// output such as container-IDs.
 	NoTrunc bool
 
+	// Reconnect enables reconnecting when the connection to the daemon is
+	// lost while streaming stats. The stats of containers are reset until
+	// the connection is re-established.
+	Reconnect bool
+
 	// Format is a custom template to use for presenting the stats.
 	// Refer to [flagsHelper.FormatHelp] for accepted formats.
 	Format string
@@ -88,6 +94,7 @@ func newStatsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.NoStream, "no-stream", false, "Disable streaming stats and only pull the first result")
 	flags.BoolVar(&options.NoTrunc, "no-trunc", false, "Do not truncate output")
 	flags.StringVar(&options.Format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&options.Reconnect, "reconnect", false, "Reconnect when the connection to the daemon is lost")
 	return cmd
 }
 
@@ -150,7 +157,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 			if s := NewStats(e.Actor.ID); cStats.add(s) {
 				waitFirst.Add(1)
 				log.G(ctx).Debug("collecting stats for container")
-				go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
+				go collect(ctx, s, apiClient, !options.NoStream, options.Reconnect, waitFirst)
 			}
 		})
 
@@ -186,6 +193,10 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 			close(started)
 			defer close(c)
 
+			var (
+				backoff reconnect.Backoff
+				since   = time.Now()
+			)
 			for {
 				select {
 				case <-stopped:
@@ -193,8 +204,27 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 				case <-ctx.Done():
 					return
 				case event := <-res.Messages:
+					since = time.Unix(0, event.TimeNano)
+					backoff.Reset()
 					c <- event
 				case err := <-res.Err:
+					if options.Reconnect && reconnect.IsDisconnect(err) {
+						// Resubscribe from the last event; events that
+						// are received again are handled idempotently.
+						log.G(ctx).WithError(err).Debug("reconnecting to receive container events")
+						select {
+						case <-stopped:
+							return
+						case <-ctx.Done():
+							return
+						case <-time.After(backoff.Next()):
+						}
+						res = apiClient.Events(ctx, client.EventsListOptions{
+							Since:   reconnect.Since(since),
+							Filters: f,
+						})
+						continue
+					}
 					// Prevent blocking if closeChan is full or unread
 					select {
 					case closeChan <- err:
@@ -229,7 +259,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 				log.G(ctx).WithFields(log.Fields{
 					"container": ctr.ID,
 				}).Debug("collecting stats for container")
-				go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
+				go collect(ctx, s, apiClient, !options.NoStream, options.Reconnect, waitFirst)
 			}
 		}
 
@@ -252,7 +282,7 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 				log.G(ctx).WithFields(log.Fields{
 					"container": ctr,
 				}).Debug("collecting stats for container")
-				go collect(ctx, s, apiClient, !options.NoStream, waitFirst)
+				go collect(ctx, s, apiClient, !options.NoStream, options.Reconnect, waitFirst)
 			}
 		}
 
@@ -8,6 +8,8 @@
 	"sync"
 	"time"
 
+	"github.com/containerd/log"
+	"github.com/docker/cli/internal/reconnect"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 )
@@ -65,26 +67,53 @@ func (s *stats) snapshot() []*Stats {
 	return cp
 }
 
-func collect(ctx context.Context, s *Stats, cli client.ContainerAPIClient, streamStats bool, waitFirst *sync.WaitGroup) { //nolint:gocyclo
+// collect collects the stats of a container. If autoReconnect is set, the
+// stats are requested again after the connection to the daemon is lost while
+// streaming. The stats are reset until the connection is re-established.
+func collect(ctx context.Context, s *Stats, cli client.ContainerAPIClient, streamStats bool, autoReconnect bool, waitFirst *sync.WaitGroup) {
 	var getFirst bool
-
-	defer func() {
-		// if error happens, and we get nothing of stats, release wait group whatever
+	releaseFirst := func() {
+		// if this is the first stat you get, release WaitGroup
 		if !getFirst {
 			getFirst = true
 			waitFirst.Done()
 		}
-	}()
+	}
+	// if error happens, and we get nothing of stats, release wait group whatever
+	defer releaseFirst()
 
-	response, err := cli.ContainerStats(ctx, s.Container, client.ContainerStatsOptions{
-		Stream:                streamStats,
-		IncludePreviousSample: !streamStats, // collect previous CPU value for the first result when not streaming.
-	})
-	if err != nil {
-		s.SetError(err)
-		return
+	var (
+		backoff   reconnect.Backoff
+		connected bool
+	)
+	for {
+		response, err := cli.ContainerStats(ctx, s.Container, client.ContainerStatsOptions{
+			Stream:                streamStats,
+			IncludePreviousSample: !streamStats, // collect previous CPU value for the first result when not streaming.
+		})
+		if err != nil {
+			s.SetError(err)
+		} else {
+			connected = true
+			backoff.Reset()
+			err = collectStream(ctx, s, response, streamStats, releaseFirst)
+		}
+		if !autoReconnect || !streamStats || !connected || !reconnect.IsDisconnect(err) {
+			return
+		}
+		log.G(ctx).WithError(err).Debug("reconnecting to collect stats for container")
+		s.SetErrorAndReset(err)
+		releaseFirst()
+		if err := backoff.Wait(ctx); err != nil {
+			s.SetError(err)
+			return
+		}
 	}
+}
 
+// collectStream collects the stats of the response until the stream ends,
+// and returns the error that ended it.
+func collectStream(ctx context.Context, s *Stats, response client.ContainerStatsResult, streamStats bool, releaseFirst func()) error { //nolint:gocyclo
 	u := make(chan error, 1)
 	go func() {
 		defer response.Body.Close()
@@ -95,11 +124,12 @@ func collect(ctx context.Context, s *Stats, cli client.ContainerAPIClient, strea
 			}
 			var v container.StatsResponse
 			if err := dec.Decode(&v); err != nil {
-				dec = json.NewDecoder(io.MultiReader(dec.Buffered(), response.Body))
 				u <- err
-				if err == io.EOF {
+				if !isDecodeError(err) {
+					// the stream ended, or the connection was lost.
 					break
 				}
+				dec = json.NewDecoder(io.MultiReader(dec.Buffered(), response.Body))
 				time.Sleep(100 * time.Millisecond)
 				continue
 			}
@@ -152,34 +182,34 @@ func collect(ctx context.Context, s *Stats, cli client.ContainerAPIClient, strea
 			// zero out the values if we have not received an update within
 			// the specified duration.
 			s.SetErrorAndReset(errors.New("timeout waiting for stats"))
-			// if this is the first stat you get, release WaitGroup
-			if !getFirst {
-				getFirst = true
-				waitFirst.Done()
-			}
+			releaseFirst()
 		case err := <-u:
 			s.SetError(err)
-			if errors.Is(err, io.EOF) {
-				return
-			}
 			if err != nil {
+				if !isDecodeError(err) {
+					return err
+				}
 				continue
 			}
-			// if this is the first stat you get, release WaitGroup
-			if !getFirst {
-				getFirst = true
-				waitFirst.Done()
-			}
+			releaseFirst()
 		case <-ctx.Done():
 			s.SetError(ctx.Err())
-			return
+			return ctx.Err()
 		}
 		if !streamStats {
-			return
+			return nil
 		}
 	}
 }
 
+// isDecodeError returns whether err is an error decoding the stats, after
+// which the next stats can be decoded.
+func isDecodeError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
 func calculateCPUPercentUnix(previousCPU container.CPUStats, curCPUStats container.CPUStats) float64 {
 	var (
 		cpuPercent = 0.0
@@ -1,11 +1,19 @@
 package container
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 
+	"github.com/containerd/errdefs"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestCalculateMemUsageUnixNoCache(t *testing.T) {
@@ -61,3 +69,36 @@ func inDelta(x, y, delta float64) func() (bool, string) {
 		return true, ""
 	}
 }
+
+func TestCollectReconnect(t *testing.T) {
+	for _, autoReconnect := range []bool{false, true} {
+		t.Run(fmt.Sprintf("reconnect=%t", autoReconnect), func(t *testing.T) {
+			var calls int
+			apiClient := &fakeClient{containerStatsFunc: func(context.Context, string, client.ContainerStatsOptions) (client.ContainerStatsResult, error) {
+				calls++
+				if calls > 1 {
+					return client.ContainerStatsResult{}, errdefs.ErrNotFound.WithMessage("no such container")
+				}
+				// the connection is lost after receiving the first stats.
+				return client.ContainerStatsResult{Body: io.NopCloser(io.MultiReader(
+					strings.NewReader(`{"id":"abc123","name":"/foo"}`+"\n"),
+					iotest.ErrReader(io.ErrUnexpectedEOF),
+				))}, nil
+			}}
+			s := NewStats("abc123")
+			waitFirst := &sync.WaitGroup{}
+			waitFirst.Add(1)
+			collect(t.Context(), s, apiClient, true, autoReconnect, waitFirst)
+			waitFirst.Wait()
+
+			if autoReconnect {
+				assert.Check(t, is.Equal(calls, 2))
+				assert.Check(t, errdefs.IsNotFound(s.GetError()))
+			} else {
+				assert.Check(t, is.Equal(calls, 1))
+				assert.Check(t, is.ErrorIs(s.GetError(), io.ErrUnexpectedEOF))
+			}
+			assert.Check(t, is.Equal(s.GetStatistics().Name, "/foo"))
+		})
+	}
+}
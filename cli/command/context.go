@@ -6,6 +6,7 @@
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"maps"
 
 	"github.com/docker/cli/cli/context/store"
@@ -13,7 +14,15 @@
 
 // DockerContext is a typed representation of what we put in Context metadata
 type DockerContext struct {
-	Description      string
+	Description string
+	Labels      map[string]string
+	// Config holds options of the CLI configuration file, such as
+	// "psFormat" or "proxies", that override the user's configuration when
+	// the context is used.
+	Config map[string]any
+	// Env holds environment variables, such as DOCKER_DEFAULT_PLATFORM, that
+	// are set when the context is used, unless they are already set.
+	Env              map[string]string
 	AdditionalFields map[string]any
 }
 
@@ -23,6 +32,15 @@ func (dc DockerContext) MarshalJSON() ([]byte, error) {
 	if dc.Description != "" {
 		s["Description"] = dc.Description
 	}
+	if len(dc.Labels) > 0 {
+		s["Labels"] = dc.Labels
+	}
+	if len(dc.Config) > 0 {
+		s["Config"] = dc.Config
+	}
+	if len(dc.Env) > 0 {
+		s["Env"] = dc.Env
+	}
 	if dc.AdditionalFields != nil {
 		maps.Copy(s, dc.AdditionalFields)
 	}
@@ -39,6 +57,34 @@ func (dc *DockerContext) UnmarshalJSON(payload []byte) error {
 		switch k {
 		case "Description":
 			dc.Description = v.(string)
+		case "Labels":
+			labels, ok := v.(map[string]any)
+			if !ok {
+				return errors.New("invalid context labels: must be an object")
+			}
+			dc.Labels = make(map[string]string, len(labels))
+			for lk, lv := range labels {
+				if dc.Labels[lk], ok = lv.(string); !ok {
+					return fmt.Errorf("invalid value for context label %q: must be a string", lk)
+				}
+			}
+		case "Config":
+			cfg, ok := v.(map[string]any)
+			if !ok {
+				return errors.New("invalid context configuration: must be an object")
+			}
+			dc.Config = cfg
+		case "Env":
+			env, ok := v.(map[string]any)
+			if !ok {
+				return errors.New("invalid context environment: must be an object")
+			}
+			dc.Env = make(map[string]string, len(env))
+			for ek, ev := range env {
+				if dc.Env[ek], ok = ev.(string); !ok {
+					return fmt.Errorf("invalid value for context environment variable %q: must be a string", ek)
+				}
+			}
 		default:
 			if dc.AdditionalFields == nil {
 				dc.AdditionalFields = make(map[string]any)
@@ -0,0 +1,185 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	dcontext "github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/fvbommel/sortorder"
+	"github.com/moby/moby/api/types/swarm"
+	"github.com/moby/moby/client"
+)
+
+const (
+	// defaultCheckTimeout is the default timeout for checking a context.
+	defaultCheckTimeout = 5 * time.Second
+
+	// maxConcurrentChecks is the maximum number of contexts that are checked
+	// concurrently.
+	maxConcurrentChecks = 8
+)
+
+// contextNames returns the names of all contexts matching the filter, or all
+// contexts if the filter is nil, in natural sort order.
+func contextNames(dockerCLI command.Cli, match func(store.Metadata) bool) ([]string, error) {
+	contextMap, err := dockerCLI.ContextStore().List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(contextMap))
+	for _, rawMeta := range contextMap {
+		if match != nil && !match(rawMeta) {
+			continue
+		}
+		names = append(names, rawMeta.Name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return sortorder.NaturalLess(names[i], names[j])
+	})
+	return names, nil
+}
+
+// checkContexts checks the connection to the docker endpoints of the given
+// contexts concurrently, and returns the results in the same order.
+func checkContexts(ctx context.Context, dockerCLI command.Cli, names []string, timeout time.Duration) []*formatter.ContextCheck {
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	var (
+		results = make([]*formatter.ContextCheck, len(names))
+		sem     = make(chan struct{}, maxConcurrentChecks)
+		wg      sync.WaitGroup
+	)
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			results[i] = checkContext(ctx, dockerCLI, name)
+		}()
+	}
+	wg.Wait()
+
+	current := dockerCLI.CurrentContext()
+	for _, r := range results {
+		r.Current = r.Name == current
+	}
+	return results
+}
+
+// checkContext pings the context's docker endpoint, and collects the
+// daemon's version, platform, and swarm role.
+func checkContext(ctx context.Context, dockerCLI command.Cli, name string) *formatter.ContextCheck {
+	result := &formatter.ContextCheck{Name: name}
+	s := dockerCLI.ContextStore()
+	meta, err := s.GetMetadata(name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	epMeta, err := docker.EndpointFromContext(meta)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.DockerEndpoint = epMeta.Host
+	result.ClientCertExpiry, err = clientCertExpiry(s, name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	apiClient, err := command.NewAPIClientForContext(s, name, dockerCLI.ConfigFile())
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer apiClient.Close()
+
+	// The first request establishes the connection, and negotiates the API
+	// version. The latency is measured with a second request, which reuses
+	// the connection where possible.
+	if _, err := apiClient.Ping(ctx, client.PingOptions{NegotiateAPIVersion: true}); err != nil {
+		result.Error = checkError(ctx, err).Error()
+		return result
+	}
+	start := time.Now()
+	ping, err := apiClient.Ping(ctx, client.PingOptions{})
+	if err != nil {
+		result.Error = checkError(ctx, err).Error()
+		return result
+	}
+	result.Latency = time.Since(start)
+	result.Reachable = true
+	result.SwarmRole = swarmRole(ping.SwarmStatus)
+
+	v, err := apiClient.ServerVersion(ctx, client.ServerVersionOptions{})
+	if err != nil {
+		result.Error = checkError(ctx, err).Error()
+		return result
+	}
+	result.ServerVersion = v.Version
+	result.APIVersion = v.APIVersion
+	result.OS = v.Os
+	result.Arch = v.Arch
+	return result
+}
+
+// checkError returns a more descriptive error if the check timed out.
+func checkError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.New("timed out waiting for the daemon")
+	}
+	return err
+}
+
+// swarmRole returns the swarm role of the node, based on the swarm status
+// in the daemon's ping response.
+func swarmRole(status *client.SwarmStatus) string {
+	switch {
+	case status == nil:
+		return ""
+	case status.NodeState != swarm.LocalNodeStateActive:
+		return string(status.NodeState)
+	case status.ControlAvailable:
+		return "manager"
+	default:
+		return "worker"
+	}
+}
+
+// clientCertExpiry returns the expiry time of the client certificate of the
+// context's docker endpoint, or the zero value if the context does not have
+// a client certificate.
+func clientCertExpiry(s store.Reader, name string) (time.Time, error) {
+	tlsData, err := dcontext.LoadTLSData(s, name, docker.DockerEndpoint)
+	if err != nil || tlsData == nil || len(tlsData.Cert) == 0 {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(tlsData.Cert)
+	if block == nil {
+		return time.Time{}, errors.New("invalid TLS certificate: no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid TLS certificate: %w", err)
+	}
+	return cert.NotAfter, nil
+}
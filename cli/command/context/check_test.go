@@ -0,0 +1,190 @@
+package context
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newTestDaemon returns a server that responds to the ping and version
+// endpoints of the API.
+func newTestDaemon(t *testing.T, swarmStatus string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/_ping":
+			w.Header().Set("Api-Version", "1.52")
+			w.Header().Set("Ostype", "linux")
+			if swarmStatus != "" {
+				w.Header().Set("Swarm", swarmStatus)
+			}
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/version"):
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"Version":    "29.0.0",
+				"ApiVersion": "1.52",
+				"Os":         "linux",
+				"Arch":       "arm64",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func createHostContext(t *testing.T, cli command.Cli, name, host string) {
+	t.Helper()
+	assert.NilError(t, runCreate(cli, name, createOptions{
+		endpoint: map[string]string{keyHost: host},
+	}))
+}
+
+func TestCheckContexts(t *testing.T) {
+	dockerCLI := makeFakeCli(t)
+	manager := newTestDaemon(t, "active/manager")
+	worker := newTestDaemon(t, "active/worker")
+	standalone := newTestDaemon(t, "inactive")
+	createHostContext(t, dockerCLI, "manager", "tcp://"+manager.Listener.Addr().String())
+	createHostContext(t, dockerCLI, "worker", "tcp://"+worker.Listener.Addr().String())
+	createHostContext(t, dockerCLI, "standalone", "tcp://"+standalone.Listener.Addr().String())
+	createHostContext(t, dockerCLI, "unreachable", "tcp://127.0.0.1:1")
+	dockerCLI.SetCurrentContext("worker")
+
+	checks := checkContexts(context.Background(), dockerCLI, []string{"manager", "worker", "standalone", "unreachable", "missing"}, time.Second)
+	assert.Assert(t, is.Len(checks, 5))
+
+	for i, expectedRole := range []string{"manager", "worker", "inactive"} {
+		c := checks[i]
+		assert.Check(t, c.Reachable, c.Name)
+		assert.Check(t, is.Equal(c.Error, ""))
+		assert.Check(t, c.Latency > 0)
+		assert.Check(t, is.Equal(c.ServerVersion, "29.0.0"))
+		assert.Check(t, is.Equal(c.APIVersion, "1.52"))
+		assert.Check(t, is.Equal(c.OS, "linux"))
+		assert.Check(t, is.Equal(c.Arch, "arm64"))
+		assert.Check(t, is.Equal(c.SwarmRole, expectedRole))
+		assert.Check(t, is.Equal(c.Current, c.Name == "worker"))
+	}
+
+	assert.Check(t, !checks[3].Reachable)
+	assert.Check(t, is.Equal(checks[3].DockerEndpoint, "tcp://127.0.0.1:1"))
+	assert.Check(t, is.Contains(checks[3].Error, "Cannot connect to the Docker daemon at tcp://127.0.0.1:1"))
+
+	assert.Check(t, !checks[4].Reachable)
+	assert.Check(t, is.Contains(checks[4].Error, "not found"))
+}
+
+func TestCheckContextTimeout(t *testing.T) {
+	dockerCLI := makeFakeCli(t)
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+	createHostContext(t, dockerCLI, "slow", "tcp://"+srv.Listener.Addr().String())
+
+	checks := checkContexts(context.Background(), dockerCLI, []string{"slow"}, 100*time.Millisecond)
+	assert.Check(t, !checks[0].Reachable)
+	assert.Check(t, is.Equal(checks[0].Error, "timed out waiting for the daemon"))
+}
+
+func TestRunTest(t *testing.T) {
+	dockerCLI := makeFakeCli(t)
+	srv := newTestDaemon(t, "")
+	createHostContext(t, dockerCLI, "reachable", "tcp://"+srv.Listener.Addr().String())
+	createHostContext(t, dockerCLI, "unreachable", "tcp://127.0.0.1:1")
+	dockerCLI.OutBuffer().Reset()
+
+	assert.NilError(t, runTest(context.Background(), dockerCLI, &testOptions{names: []string{"reachable"}, format: "{{.Name}} {{.Status}} {{.Platform}} {{.ServerVersion}}"}))
+	assert.Check(t, is.Equal(dockerCLI.OutBuffer().String(), "reachable reachable linux/arm64 29.0.0\n"))
+
+	dockerCLI.OutBuffer().Reset()
+	err := runTest(context.Background(), dockerCLI, &testOptions{names: []string{"reachable", "unreachable"}, quiet: true})
+	assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: 1}))
+	assert.Check(t, is.Equal(dockerCLI.OutBuffer().String(), "reachable\n"))
+
+	dockerCLI.OutBuffer().Reset()
+	err = runTest(context.Background(), dockerCLI, &testOptions{names: []string{"unreachable"}, format: "json"})
+	assert.Check(t, is.ErrorType(err, cli.StatusError{}))
+	var out map[string]any
+	assert.NilError(t, json.Unmarshal(dockerCLI.OutBuffer().Bytes(), &out))
+	assert.Check(t, is.Equal(out["Name"], "unreachable"))
+	assert.Check(t, is.Equal(out["Status"], "unreachable"))
+	assert.Check(t, is.Equal(out["Reachable"], false))
+}
+
+func TestListCheck(t *testing.T) {
+	dockerCLI := makeFakeCli(t)
+	srv := newTestDaemon(t, "")
+	createHostContext(t, dockerCLI, "reachable", "tcp://"+srv.Listener.Addr().String())
+	createHostContext(t, dockerCLI, "unreachable", "tcp://127.0.0.1:1")
+	dockerCLI.SetCurrentContext("reachable")
+	dockerCLI.OutBuffer().Reset()
+
+	assert.NilError(t, runListCheck(context.Background(), dockerCLI, &listOptions{check: true, format: "{{.Name}}{{if .Current}} *{{end}}\t{{.Status}}"}))
+	// all contexts are checked, including the default context.
+	out := dockerCLI.OutBuffer().String()
+	assert.Check(t, is.Contains(out, "default\t"))
+	assert.Check(t, is.Contains(out, "reachable *\treachable\nunreachable\tunreachable\n"))
+}
+
+func TestClientCertExpiry(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	certFile, keyFile := writeClientCert(t, notAfter)
+
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "tls", createOptions{
+		endpoint: map[string]string{keyHost: "tcp://127.0.0.1:1", keyCert: certFile, keyKey: keyFile},
+	}))
+	expiry, err := clientCertExpiry(cli.ContextStore(), "tls")
+	assert.NilError(t, err)
+	assert.Check(t, expiry.Equal(notAfter))
+
+	expiry, err = clientCertExpiry(cli.ContextStore(), "default")
+	assert.NilError(t, err)
+	assert.Check(t, expiry.IsZero())
+}
+
+func writeClientCert(t *testing.T, notAfter time.Time) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	assert.NilError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
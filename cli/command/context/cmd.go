@@ -31,6 +31,9 @@ func newContextCommand(dockerCLI command.Cli) *cobra.Command {
 		newUpdateCommand(dockerCLI),
 		newInspectCommand(dockerCLI),
 		newShowCommand(dockerCLI),
+		newTestCommand(dockerCLI),
+		newEncryptCommand(dockerCLI),
+		newProxyCommand(dockerCLI),
 	)
 	return cmd
 }
@@ -7,6 +7,7 @@
 	"bytes"
 	"errors"
 	"fmt"
+	"maps"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
@@ -14,12 +15,16 @@
 	"github.com/docker/cli/cli/command/formatter/tabwriter"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
+	cliopts "github.com/docker/cli/opts"
 	"github.com/spf13/cobra"
 )
 
 // createOptions are the options used for creating a context
 type createOptions struct {
 	description string
+	labels      []string
+	config      []string
+	env         []string
 	endpoint    map[string]string
 	from        string
 
@@ -56,6 +61,9 @@ func newCreateCommand(dockerCLI command.Cli) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&opts.description, "description", "", "Description of the context")
+	flags.Var(cliopts.NewListOptsRef(&opts.labels, cliopts.ValidateLabel), "label", `Set a context label ("key=value")`)
+	flags.Var(cliopts.NewListOptsRef(&opts.config, nil), "config", `Set a configuration option for the context ("key=value")`)
+	flags.Var(cliopts.NewListOptsRef(&opts.env, nil), "env", `Set a default environment variable for the context ("KEY=VALUE")`)
 	flags.StringToStringVar(&opts.endpoint, "docker", nil, "set the docker endpoint")
 	flags.StringVar(&opts.from, "from", "", "create context from a named context")
 	return cmd
@@ -91,12 +99,23 @@ func createNewContext(contextStore store.ReaderWriter, name string, opts createO
 	if err != nil {
 		return fmt.Errorf("unable to create docker endpoint config: %w", err)
 	}
+	cfg, err := updateConfig(nil, opts.config, nil)
+	if err != nil {
+		return err
+	}
+	env, err := updateEnv(nil, opts.env, nil)
+	if err != nil {
+		return err
+	}
 	contextMetadata := store.Metadata{
 		Endpoints: map[string]any{
 			docker.DockerEndpoint: dockerEP,
 		},
 		Metadata: command.DockerContext{
 			Description:      opts.description,
+			Labels:           labelsFromOptions(opts.labels),
+			Config:           cfg,
+			Env:              env,
 			AdditionalFields: opts.metaData,
 		},
 		Name: name,
@@ -136,14 +155,23 @@ func createFromExistingContext(s store.ReaderWriter, name string, fromContextNam
 	reader := store.Export(fromContextName, &descriptionDecorator{
 		Reader:      s,
 		description: opts.description,
+		labels:      labelsFromOptions(opts.labels),
+		config:      opts.config,
+		env:         opts.env,
 	})
 	defer reader.Close()
 	return store.Import(name, s, reader)
 }
 
+// descriptionDecorator overrides the description, and adds the labels,
+// configuration options, and environment variables to the metadata of
+// contexts.
 type descriptionDecorator struct {
 	store.Reader
 	description string
+	labels      map[string]string
+	config      []string
+	env         []string
 }
 
 func (d *descriptionDecorator) GetMetadata(name string) (store.Metadata, error) {
@@ -158,6 +186,28 @@ func (d *descriptionDecorator) GetMetadata(name string) (store.Metadata, error)
 	if d.description != "" {
 		typedContext.Description = d.description
 	}
+	if len(d.labels) > 0 {
+		typedContext.Labels = maps.Clone(typedContext.Labels)
+		if typedContext.Labels == nil {
+			typedContext.Labels = make(map[string]string, len(d.labels))
+		}
+		maps.Copy(typedContext.Labels, d.labels)
+	}
+	if typedContext.Config, err = updateConfig(typedContext.Config, d.config, nil); err != nil {
+		return c, err
+	}
+	if typedContext.Env, err = updateEnv(typedContext.Env, d.env, nil); err != nil {
+		return c, err
+	}
 	c.Metadata = typedContext
 	return c, nil
 }
+
+// labelsFromOptions converts the labels ("key=value") that are set through
+// the --label option to a map, or returns nil if no labels are set.
+func labelsFromOptions(labels []string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	return cliopts.ConvertKVStringsToMap(labels)
+}
@@ -5,14 +5,18 @@
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func makeFakeCli(t *testing.T, opts ...func(*test.FakeCli)) *test.FakeCli {
@@ -165,17 +169,27 @@ func TestCreateFromContext(t *testing.T) {
 	cases := []struct {
 		name                string
 		description         string
+		labels              []string
 		expectedDescription string
+		expectedLabels      map[string]string
 		docker              map[string]string
 	}{
 		{
 			name:                "no-override",
 			expectedDescription: "original description",
+			expectedLabels:      map[string]string{"env": "prod"},
 		},
 		{
 			name:                "override-description",
 			description:         "new description",
 			expectedDescription: "new description",
+			expectedLabels:      map[string]string{"env": "prod"},
+		},
+		{
+			name:                "override-labels",
+			labels:              []string{"env=staging", "region=eu"},
+			expectedDescription: "original description",
+			expectedLabels:      map[string]string{"env": "staging", "region": "eu"},
 		},
 	}
 
@@ -183,6 +197,7 @@ func TestCreateFromContext(t *testing.T) {
 	cli.ResetOutputBuffers()
 	assert.NilError(t, runCreate(cli, "original", createOptions{
 		description: "original description",
+		labels:      []string{"env=prod"},
 		endpoint: map[string]string{
 			keyHost: "tcp://42.42.42.42:2375",
 		},
@@ -206,6 +221,7 @@ func TestCreateFromContext(t *testing.T) {
 			err := runCreate(cli, tc.name, createOptions{
 				from:        "original",
 				description: tc.description,
+				labels:      tc.labels,
 				endpoint:    tc.docker,
 			})
 			assert.NilError(t, err)
@@ -217,6 +233,7 @@ func TestCreateFromContext(t *testing.T) {
 			dockerEndpoint, err := docker.EndpointFromContext(newContext)
 			assert.NilError(t, err)
 			assert.Equal(t, newContextTyped.Description, tc.expectedDescription)
+			assert.Check(t, is.DeepEqual(newContextTyped.Labels, tc.expectedLabels))
 			assert.Equal(t, dockerEndpoint.Host, "tcp://42.42.42.42:2375")
 		})
 	}
@@ -271,3 +288,177 @@ func TestCreateFromCurrent(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateSSHOptions(t *testing.T) {
+	dir := t.TempDir()
+	identityFile := filepath.Join(dir, "id_ed25519")
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	assert.NilError(t, os.WriteFile(identityFile, []byte("private key"), 0o600))
+	assert.NilError(t, os.WriteFile(knownHostsFile, []byte("example.com ssh-ed25519 AAAA"), 0o600))
+
+	cli := makeFakeCli(t)
+	err := runCreate(cli, "ssh", createOptions{
+		endpoint: map[string]string{
+			keyHost:          "ssh://me@example.com:2222",
+			keySSHIdentity:   identityFile,
+			keySSHKnownHosts: knownHostsFile,
+			keySSHJump:       "bastion.example.com",
+			keySSHOptions:    "ServerAliveInterval=30; Compression=yes",
+		},
+	})
+	assert.NilError(t, err)
+
+	ctxMeta, err := cli.ContextStore().GetMetadata("ssh")
+	assert.NilError(t, err)
+	ep, err := docker.EndpointFromContext(ctxMeta)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ep.SSH, &context.SSHOptions{
+		ProxyJump: "bastion.example.com",
+		Options:   []string{"ServerAliveInterval=30", "Compression=yes"},
+	}))
+	tlsData, err := context.LoadTLSData(cli.ContextStore(), "ssh", docker.DockerEndpoint)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(tlsData.SSHIdentity), "private key"))
+	assert.Check(t, is.Equal(string(tlsData.SSHKnownHosts), "example.com ssh-ed25519 AAAA"))
+
+	// the options are exported and imported with the context.
+	contextFile := filepath.Join(dir, "exported")
+	assert.NilError(t, runExport(t.Context(), cli, exportOptions{contextName: "ssh", dest: contextFile}))
+	assert.NilError(t, runImport(t.Context(), cli, importOptions{name: "ssh2", source: contextFile}))
+	ctxMeta2, err := cli.ContextStore().GetMetadata("ssh2")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ctxMeta.Endpoints, ctxMeta2.Endpoints))
+	tlsData2, err := context.LoadTLSData(cli.ContextStore(), "ssh2", docker.DockerEndpoint)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(tlsData, tlsData2))
+}
+
+func TestCreateSSHTransport(t *testing.T) {
+	cli := makeFakeCli(t)
+	for _, tc := range []struct {
+		transport string
+		expected  *context.SSHOptions
+	}{
+		{transport: "exec"},
+		{transport: "native", expected: &context.SSHOptions{Transport: context.SSHTransportNative}},
+	} {
+		t.Run(tc.transport, func(t *testing.T) {
+			err := runCreate(cli, tc.transport, createOptions{
+				endpoint: map[string]string{keyHost: "ssh://me@example.com", keySSHTransport: tc.transport},
+			})
+			assert.NilError(t, err)
+			ctxMeta, err := cli.ContextStore().GetMetadata(tc.transport)
+			assert.NilError(t, err)
+			ep, err := docker.EndpointFromContext(ctxMeta)
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(ep.SSH, tc.expected))
+		})
+	}
+}
+
+func TestCreateInvalidSSHOptions(t *testing.T) {
+	cli := makeFakeCli(t)
+	for _, tc := range []struct {
+		doc         string
+		endpoint    map[string]string
+		expectedErr string
+	}{
+		{
+			doc:         "not an ssh host",
+			endpoint:    map[string]string{keyHost: "tcp://example.com:2376", keySSHJump: "bastion"},
+			expectedErr: "ssh options can only be used for ssh:// hosts",
+		},
+		{
+			doc:         "command option",
+			endpoint:    map[string]string{keyHost: "ssh://example.com", keySSHOptions: "ProxyCommand=nc %h %p"},
+			expectedErr: `invalid ssh option "ProxyCommand=nc %h %p": ProxyCommand is not allowed`,
+		},
+		{
+			doc:         "invalid option",
+			endpoint:    map[string]string{keyHost: "ssh://example.com", keySSHOptions: "Compression"},
+			expectedErr: `invalid ssh option "Compression": must be in Key=Value format`,
+		},
+		{
+			doc:         "invalid jump host",
+			endpoint:    map[string]string{keyHost: "ssh://example.com", keySSHJump: "-oProxyCommand=x"},
+			expectedErr: "invalid jump host",
+		},
+		{
+			doc:         "invalid transport",
+			endpoint:    map[string]string{keyHost: "ssh://example.com", keySSHTransport: "openssh"},
+			expectedErr: `invalid ssh transport "openssh": must be "exec" or "native"`,
+		},
+		{
+			doc:         "missing identity file",
+			endpoint:    map[string]string{keyHost: "ssh://example.com", keySSHIdentity: filepath.Join(t.TempDir(), "missing")},
+			expectedErr: "missing",
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			err := runCreate(cli, "invalid", createOptions{endpoint: tc.endpoint})
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestCreateFailover(t *testing.T) {
+	cli := makeFakeCli(t)
+	err := runCreate(cli, "failover", createOptions{
+		endpoint: map[string]string{
+			keyHost:          "tcp://primary.example.com:2376",
+			keyFailoverHosts: "tcp://secondary.example.com:2376; tcp://tertiary.example.com:2376",
+			keyFailover:      "latency",
+		},
+	})
+	assert.NilError(t, err)
+	ctxMeta, err := cli.ContextStore().GetMetadata("failover")
+	assert.NilError(t, err)
+	ep, err := docker.EndpointFromContext(ctxMeta)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(ep.Hosts(), []string{
+		"tcp://primary.example.com:2376",
+		"tcp://secondary.example.com:2376",
+		"tcp://tertiary.example.com:2376",
+	}))
+	assert.Check(t, is.Equal(ep.FailoverStrategy, context.FailoverLatency))
+}
+
+func TestCreateInvalidFailover(t *testing.T) {
+	cli := makeFakeCli(t)
+	for _, tc := range []struct {
+		doc         string
+		endpoint    map[string]string
+		expectedErr string
+	}{
+		{
+			doc:         "invalid strategy",
+			endpoint:    map[string]string{keyHost: "tcp://example.com:2376", keyFailoverHosts: "tcp://other.example.com:2376", keyFailover: "random"},
+			expectedErr: `invalid failover strategy "random": must be "order" or "latency"`,
+		},
+		{
+			doc:         "strategy without hosts",
+			endpoint:    map[string]string{keyHost: "tcp://example.com:2376", keyFailover: "latency"},
+			expectedErr: "failover strategy can only be used with failover hosts",
+		},
+		{
+			doc:         "no host",
+			endpoint:    map[string]string{keyFailoverHosts: "tcp://other.example.com:2376"},
+			expectedErr: "failover hosts can only be used with a host",
+		},
+		{
+			doc:         "invalid host",
+			endpoint:    map[string]string{keyHost: "tcp://example.com:2376", keyFailoverHosts: "other.example.com"},
+			expectedErr: `invalid failover host "other.example.com"`,
+		},
+		{
+			doc:         "mixed schemes",
+			endpoint:    map[string]string{keyHost: "ssh://me@example.com", keyFailoverHosts: "tcp://other.example.com:2376"},
+			expectedErr: `invalid failover host "tcp://other.example.com:2376": failover hosts must use the same scheme as the host (ssh://)`,
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			err := runCreate(cli, "invalid", createOptions{endpoint: tc.endpoint})
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		})
+	}
+}
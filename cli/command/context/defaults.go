@@ -0,0 +1,98 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+// updateConfig returns the configuration of a context with the options that
+// are set through the --config option ("key=value"), and without the options
+// that are removed through the --config-rm option ("key"). It returns nil if
+// no options are left.
+func updateConfig(current map[string]any, set, unset []string) (map[string]any, error) {
+	if len(set) == 0 && len(unset) == 0 {
+		return current, nil
+	}
+	cfg := configfile.New("")
+	if len(current) > 0 {
+		data, err := json.Marshal(current)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.LoadFromReader(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("invalid configuration of context: %w", err)
+		}
+	}
+	for _, key := range unset {
+		if _, err := cfg.UnsetOption(key); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range set {
+		key, value, ok := strings.Cut(o, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid configuration option %q: must be in key=value format", o)
+		}
+		if name, _, _ := strings.Cut(key, "."); slices.Contains(configfile.OptionNames(), name) && !configfile.LayerContext.Permits(key) {
+			return nil, fmt.Errorf("configuration option %q cannot be set for a context", key)
+		}
+		if err := cfg.SetOption(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var updated map[string]any
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return nil, err
+	}
+	delete(updated, "auths")
+	if len(updated) == 0 {
+		return nil, nil
+	}
+	return updated, nil
+}
+
+// updateEnv returns the environment variables of a context with the
+// variables that are set through the --env option ("KEY=VALUE"), and without
+// the variables that are removed through the --env-rm option ("KEY"). It
+// returns nil if no variables are left.
+func updateEnv(current map[string]string, set, unset []string) (map[string]string, error) {
+	if len(set) == 0 && len(unset) == 0 {
+		return current, nil
+	}
+	env := maps.Clone(current)
+	if env == nil {
+		env = make(map[string]string, len(set))
+	}
+	for _, k := range unset {
+		delete(env, k)
+	}
+	for _, e := range set {
+		k, v, ok := strings.Cut(e, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid environment variable %q: must be in KEY=VALUE format", e)
+		}
+		if err := command.ValidateContextEnv(k); err != nil {
+			return nil, err
+		}
+		env[k] = v
+	}
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
@@ -0,0 +1,219 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/spf13/cobra"
+)
+
+type encryptOptions struct {
+	helper  string
+	decrypt bool
+}
+
+func newEncryptCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts encryptOptions
+	cmd := &cobra.Command{
+		Use:   "encrypt [OPTIONS]",
+		Short: "Encrypt the TLS keys of contexts at rest",
+		Long:  encryptDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEncrypt(dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.helper, "helper", "", `Credential helper to store the encryption key in (for example, "osxkeychain"); defaults to the configured "credsStore"`)
+	flags.BoolVar(&opts.decrypt, "decrypt", false, "Decrypt the TLS keys of contexts, and disable encryption")
+	return cmd
+}
+
+var encryptDescription = `
+Encrypt the TLS private keys of all contexts in the context store with a key
+that's held by a credential helper. The key is created in the credential helper
+if it does not exist, and the helper is stored as "contextTLSKeyStore" in the
+configuration file. TLS keys of contexts that are created or imported later are
+encrypted as well.
+
+SSH identity files ("--ssh-identity") are NOT encrypted, and remain stored
+in plain text in the context store, as they are passed to the ssh binary. The
+contexts that have an SSH identity file are listed in a warning.
+`
+
+func runEncrypt(dockerCLI command.Cli, opts encryptOptions) error {
+	cfg := dockerCLI.ConfigFile()
+	if opts.decrypt {
+		if opts.helper != "" {
+			return errors.New("conflicting options: cannot specify both --helper and --decrypt")
+		}
+		return runDecrypt(dockerCLI)
+	}
+
+	helper := cfg.ContextTLSKeyStore
+	if opts.helper != "" {
+		if helper != "" && helper != opts.helper {
+			return fmt.Errorf("cannot encrypt with a key in %q: TLS keys are already encrypted with a key in %q", opts.helper, helper)
+		}
+		helper = opts.helper
+	}
+	if helper == "" {
+		helper = cfg.CredentialsStore
+	}
+	if helper == "" {
+		return errors.New(`no credential helper configured: use the --helper option, or set "credsStore" in the configuration file`)
+	}
+
+	if err := command.NewContextTLSKeyStore(cfg).CreateKey(helper); err != nil {
+		return err
+	}
+	tlsData, err := loadContextTLSKeys(dockerCLI.ContextStore())
+	if err != nil {
+		return err
+	}
+
+	// Enable encryption before rewriting the TLS keys, so that all keys
+	// remain readable if the migration is interrupted.
+	if cfg.ContextTLSKeyStore != helper {
+		cfg.ContextTLSKeyStore = helper
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+	}
+	if err := resetContextTLSKeys(dockerCLI, tlsData); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully encrypted TLS keys of contexts with a key in credential helper %q\n", helper)
+
+	names, err := contextsWithSSHIdentity(dockerCLI.ContextStore())
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: the SSH identity file of context %q is not encrypted, and is stored in plain text in %s\n", name, dockerCLI.ContextStore().GetStorageInfo(name).TLSPath)
+	}
+	return nil
+}
+
+// contextsWithSSHIdentity returns the names of the contexts that have an SSH
+// identity file, which is not encrypted.
+func contextsWithSSHIdentity(s store.Store) ([]string, error) {
+	names, err := store.Names(s)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	var result []string
+	for _, name := range names {
+		if name == command.DefaultContextName {
+			continue
+		}
+		files, err := s.ListTLSFiles(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, epFiles := range files {
+			if slices.Contains(epFiles, context.SSHIdentityFile) {
+				result = append(result, name)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func runDecrypt(dockerCLI command.Cli) error {
+	cfg := dockerCLI.ConfigFile()
+	helper := cfg.ContextTLSKeyStore
+	if helper == "" {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "TLS keys of contexts are not encrypted")
+		return nil
+	}
+	tlsData, err := loadContextTLSKeys(dockerCLI.ContextStore())
+	if err != nil {
+		return err
+	}
+
+	// Disable encryption after rewriting the TLS keys, so that all keys
+	// remain readable if the migration is interrupted.
+	cfg.ContextTLSKeyStore = ""
+	if err := resetContextTLSKeys(dockerCLI, tlsData); err != nil {
+		cfg.ContextTLSKeyStore = helper
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Err(), "Successfully decrypted TLS keys of contexts")
+	return nil
+}
+
+// contextTLSKeys holds the TLS data of the endpoints of a context that
+// have a TLS key.
+type contextTLSKeys struct {
+	name      string
+	endpoints map[string]*store.EndpointTLSData
+}
+
+// loadContextTLSKeys loads the TLS data of all endpoints that have a TLS key.
+// TLS keys that are encrypted are decrypted.
+func loadContextTLSKeys(s store.Store) ([]contextTLSKeys, error) {
+	names, err := store.Names(s)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	var result []contextTLSKeys
+	for _, name := range names {
+		if name == command.DefaultContextName {
+			continue
+		}
+		files, err := s.ListTLSFiles(name)
+		if err != nil {
+			return nil, err
+		}
+		c := contextTLSKeys{name: name, endpoints: make(map[string]*store.EndpointTLSData)}
+		for ep, epFiles := range files {
+			if !slices.Contains(epFiles, context.TLSKeyFile) {
+				continue
+			}
+			data := &store.EndpointTLSData{Files: make(map[string][]byte, len(epFiles))}
+			for _, f := range epFiles {
+				if data.Files[f], err = s.GetTLSData(name, ep, f); err != nil {
+					return nil, fmt.Errorf("failed to read TLS data of context %q: %w", name, err)
+				}
+			}
+			c.endpoints[ep] = data
+		}
+		if len(c.endpoints) > 0 {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// resetContextTLSKeys writes the TLS data, which encrypts the TLS keys if
+// encryption is enabled, and prints the names of the contexts.
+func resetContextTLSKeys(dockerCLI command.Cli, tlsData []contextTLSKeys) error {
+	s := dockerCLI.ContextStore()
+	for _, c := range tlsData {
+		for ep, data := range c.endpoints {
+			if err := s.ResetEndpointTLSMaterial(c.name, ep, data); err != nil {
+				return fmt.Errorf("failed to write TLS data of context %q: %w", c.name, err)
+			}
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Out(), c.name)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package context
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+// fakeCredentialHelper installs a credential helper named "test" in PATH,
+// which stores a single credential in a file next to the helper.
+func fakeCredentialHelper(t *testing.T) {
+	t.Helper()
+	skip.If(t, runtime.GOOS == "windows", "credential helper is a shell script")
+	dir := t.TempDir()
+	const script = `#!/bin/sh
+case "$1" in
+store) cat > "$0.data" ;;
+get) if [ -f "$0.data" ]; then cat "$0.data"; else echo "credentials not found in native keychain"; exit 1; fi ;;
+esac
+`
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "docker-credential-test"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// makeEncryptingFakeCli returns a fake CLI with a context store that
+// encrypts TLS keys with a key in the configured credential helper.
+func makeEncryptingFakeCli(t *testing.T) *test.FakeCli {
+	t.Helper()
+	dockerCLI := makeFakeCli(t)
+	dockerCLI.ConfigFile().Filename = filepath.Join(t.TempDir(), "config.json")
+	storeConfig := store.NewConfig(
+		func() any { return &command.DockerContext{} },
+		store.EndpointTypeGetter(docker.DockerEndpoint, func() any { return &docker.EndpointMeta{} }),
+	)
+	dockerCLI.SetContextStore(&command.ContextStoreWithDefault{
+		Store:    store.New(t.TempDir(), storeConfig, store.WithTLSEncryption(command.NewContextTLSKeyStore(dockerCLI.ConfigFile()), context.TLSKeyFile)),
+		Resolver: dockerCLI.ContextStore().(*command.ContextStoreWithDefault).Resolver,
+	})
+	return dockerCLI
+}
+
+func readRawTLSFile(t *testing.T, s store.Store, contextName, fileName string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(s.GetStorageInfo(contextName).TLSPath, docker.DockerEndpoint, fileName))
+	assert.NilError(t, err)
+	return data
+}
+
+func TestEncrypt(t *testing.T) {
+	fakeCredentialHelper(t)
+	dockerCLI := makeEncryptingFakeCli(t)
+	s := dockerCLI.ContextStore()
+	assert.NilError(t, s.CreateOrUpdate(store.Metadata{
+		Name:      "tls",
+		Endpoints: map[string]any{docker.DockerEndpoint: docker.EndpointMeta{Host: "tcp://127.0.0.1:2376"}},
+	}))
+	assert.NilError(t, s.ResetEndpointTLSMaterial("tls", docker.DockerEndpoint, (&context.TLSData{
+		CA:          []byte("ca"),
+		Key:         []byte("private key"),
+		SSHIdentity: []byte("ssh key"),
+	}).ToStoreTLSData()))
+	createTestContext(t, dockerCLI, "no-tls", nil)
+
+	err := runEncrypt(dockerCLI, encryptOptions{})
+	assert.Check(t, is.Error(err, `no credential helper configured: use the --helper option, or set "credsStore" in the configuration file`))
+
+	dockerCLI.ConfigFile().CredentialsStore = "test"
+	dockerCLI.OutBuffer().Reset()
+	dockerCLI.ErrBuffer().Reset()
+	assert.NilError(t, runEncrypt(dockerCLI, encryptOptions{}))
+	assert.Check(t, is.Equal(dockerCLI.OutBuffer().String(), "tls\n"))
+	assert.Check(t, is.Equal(dockerCLI.ErrBuffer().String(), `Successfully encrypted TLS keys of contexts with a key in credential helper "test"`+"\n"+
+		`WARNING: the SSH identity file of context "tls" is not encrypted, and is stored in plain text in `+s.GetStorageInfo("tls").TLSPath+"\n"))
+	assert.Check(t, is.Equal(dockerCLI.ConfigFile().ContextTLSKeyStore, "test"))
+	configData, err := os.ReadFile(dockerCLI.ConfigFile().Filename)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(configData), `"contextTLSKeyStore": "test"`))
+
+	assert.Check(t, !bytes.Contains(readRawTLSFile(t, s, "tls", context.TLSKeyFile), []byte("private key")))
+	assert.Check(t, is.Equal(string(readRawTLSFile(t, s, "tls", context.SSHIdentityFile)), "ssh key"), "ssh identity must be usable by the ssh binary")
+	tlsData, err := context.LoadTLSData(s, "tls", docker.DockerEndpoint)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(tlsData.Key), "private key"))
+	assert.Check(t, is.Equal(string(tlsData.CA), "ca"))
+
+	err = runEncrypt(dockerCLI, encryptOptions{helper: "other"})
+	assert.Check(t, is.Error(err, `cannot encrypt with a key in "other": TLS keys are already encrypted with a key in "test"`))
+
+	// TLS keys of new contexts are encrypted.
+	assert.NilError(t, s.ResetEndpointTLSMaterial("no-tls", docker.DockerEndpoint, (&context.TLSData{Key: []byte("new key")}).ToStoreTLSData()))
+	assert.Check(t, !bytes.Contains(readRawTLSFile(t, s, "no-tls", context.TLSKeyFile), []byte("new key")))
+
+	dockerCLI.OutBuffer().Reset()
+	assert.NilError(t, runEncrypt(dockerCLI, encryptOptions{decrypt: true}))
+	assert.Check(t, is.Equal(dockerCLI.OutBuffer().String(), "no-tls\ntls\n"))
+	assert.Check(t, is.Equal(dockerCLI.ConfigFile().ContextTLSKeyStore, ""))
+	assert.Check(t, is.Equal(string(readRawTLSFile(t, s, "tls", context.TLSKeyFile)), "private key"))
+	assert.Check(t, is.Equal(string(readRawTLSFile(t, s, "no-tls", context.TLSKeyFile)), "new key"))
+}
+
+func TestEncryptDecryptConflict(t *testing.T) {
+	dockerCLI := makeFakeCli(t)
+	err := runEncrypt(dockerCLI, encryptOptions{helper: "test", decrypt: true})
+	assert.Check(t, is.Error(err, "conflicting options: cannot specify both --helper and --decrypt"))
+
+	dockerCLI.ErrBuffer().Reset()
+	assert.NilError(t, runEncrypt(dockerCLI, encryptOptions{decrypt: true}))
+	assert.Check(t, is.Equal(dockerCLI.ErrBuffer().String(), "TLS keys of contexts are not encrypted\n"))
+}
@@ -6,6 +6,7 @@
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli/command"
@@ -21,11 +22,11 @@ func TestExportImportWithFile(t *testing.T) {
 		"MyCustomMetadata": t.Name(),
 	})
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, runExport(cli, "test", contextFile))
+	assert.NilError(t, runExport(t.Context(), cli, exportOptions{contextName: "test", dest: contextFile}))
 	assert.Equal(t, cli.ErrBuffer().String(), fmt.Sprintf("Written file %q\n", contextFile))
 	cli.OutBuffer().Reset()
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, runImport(cli, "test2", contextFile))
+	assert.NilError(t, runImport(t.Context(), cli, importOptions{name: "test2", source: contextFile}))
 	context1, err := cli.ContextStore().GetMetadata("test")
 	assert.NilError(t, err)
 	context2, err := cli.ContextStore().GetMetadata("test2")
@@ -52,12 +53,12 @@ func TestExportImportPipe(t *testing.T) {
 	})
 	cli.ErrBuffer().Reset()
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runExport(cli, "test", "-"))
+	assert.NilError(t, runExport(t.Context(), cli, exportOptions{contextName: "test", dest: "-"}))
 	assert.Equal(t, cli.ErrBuffer().String(), "")
 	cli.SetIn(streams.NewIn(io.NopCloser(bytes.NewBuffer(cli.OutBuffer().Bytes()))))
 	cli.OutBuffer().Reset()
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, runImport(cli, "test2", "-"))
+	assert.NilError(t, runImport(t.Context(), cli, importOptions{name: "test2", source: "-"}))
 	context1, err := cli.ContextStore().GetMetadata("test")
 	assert.NilError(t, err)
 	context2, err := cli.ContextStore().GetMetadata("test2")
@@ -82,6 +83,69 @@ func TestExportExistingFile(t *testing.T) {
 	cli := makeFakeCli(t)
 	cli.ErrBuffer().Reset()
 	assert.NilError(t, os.WriteFile(contextFile, []byte{}, 0o644))
-	err := runExport(cli, "test", contextFile)
+	err := runExport(t.Context(), cli, exportOptions{contextName: "test", dest: contextFile})
 	assert.Assert(t, os.IsExist(err))
 }
+
+func TestExportImportEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	contextFile := filepath.Join(dir, "exported")
+	passphraseFile := filepath.Join(dir, "passphrase")
+	assert.NilError(t, os.WriteFile(passphraseFile, []byte("correct horse\n"), 0o600))
+
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(""))))
+
+	err := runExport(t.Context(), cli, exportOptions{contextName: "test", dest: contextFile, passphraseFile: passphraseFile})
+	assert.Check(t, is.Error(err, "the --passphrase-file option requires --encrypt to be set"))
+	err = runExport(t.Context(), cli, exportOptions{contextName: "test", dest: contextFile, encrypt: true})
+	assert.Check(t, is.ErrorContains(err, "use the --passphrase-file option"))
+
+	assert.NilError(t, runExport(t.Context(), cli, exportOptions{contextName: "test", dest: contextFile, encrypt: true, passphraseFile: passphraseFile}))
+
+	err = runImport(t.Context(), cli, importOptions{name: "test2", source: contextFile})
+	assert.Check(t, is.Error(err, "context is encrypted: cannot prompt for a passphrase, because the input is not a terminal: use the --passphrase-file option"))
+
+	wrongFile := filepath.Join(dir, "wrong")
+	assert.NilError(t, os.WriteFile(wrongFile, []byte("battery staple\n"), 0o600))
+	err = runImport(t.Context(), cli, importOptions{name: "test2", source: contextFile, passphraseFile: wrongFile})
+	assert.Check(t, is.Error(err, "failed to decrypt context: incorrect passphrase, or the file is corrupted"))
+
+	err = runImport(t.Context(), cli, importOptions{name: "test2", source: "-", passphraseFile: "-"})
+	assert.Check(t, is.Error(err, "conflicting options: cannot read both the context and the passphrase from STDIN"))
+
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("correct horse\n"))))
+	assert.NilError(t, runImport(t.Context(), cli, importOptions{name: "test2", source: contextFile, passphraseFile: "-"}))
+	context1, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	context2, err := cli.ContextStore().GetMetadata("test2")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(context1.Endpoints, context2.Endpoints))
+	assert.Check(t, is.DeepEqual(context1.Metadata, context2.Metadata))
+}
+
+func TestExportEncryptedShortPassphrase(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("short"))))
+	err := runExport(t.Context(), cli, exportOptions{contextName: "test", dest: "-", encrypt: true, passphraseFile: "-"})
+	assert.Check(t, is.Error(err, "passphrase must have at least 8 characters"))
+}
+
+func TestExportImportLabels(t *testing.T) {
+	contextFile := filepath.Join(t.TempDir(), "exported")
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "test", createOptions{
+		labels:   []string{"env=prod", "region=eu"},
+		endpoint: map[string]string{keyHost: "https://someswarmserver.example.com"},
+	}))
+	assert.NilError(t, runExport(t.Context(), cli, exportOptions{contextName: "test", dest: contextFile}))
+	assert.NilError(t, runImport(t.Context(), cli, importOptions{name: "test2", source: contextFile}))
+
+	c, err := cli.ContextStore().GetMetadata("test2")
+	assert.NilError(t, err)
+	dc, err := command.GetDockerContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(dc.Labels, map[string]string{"env": "prod", "region": "eu"}))
+}
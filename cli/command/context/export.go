@@ -1,6 +1,8 @@
 package context
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -12,24 +14,36 @@
 	"github.com/spf13/cobra"
 )
 
+type exportOptions struct {
+	contextName    string
+	dest           string
+	encrypt        bool
+	passphraseFile string
+}
+
 func newExportCommand(dockerCLI command.Cli) *cobra.Command {
-	return &cobra.Command{
+	var opts exportOptions
+	cmd := &cobra.Command{
 		Use:   "export [OPTIONS] CONTEXT [FILE|-]",
 		Short: "Export a context to a tar archive FILE or a tar stream on STDOUT.",
 		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			contextName := args[0]
-			var dest string
+			opts.contextName = args[0]
 			if len(args) == 2 {
-				dest = args[1]
+				opts.dest = args[1]
 			} else {
-				dest = contextName + ".dockercontext"
+				opts.dest = opts.contextName + ".dockercontext"
 			}
-			return runExport(dockerCLI, contextName, dest)
+			return runExport(cmd.Context(), dockerCLI, opts)
 		},
 		ValidArgsFunction:     completeContextNames(dockerCLI, 1, true),
 		DisableFlagsInUseLine: true,
 	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.encrypt, "encrypt", false, "Encrypt the exported context with a passphrase")
+	flags.StringVar(&opts.passphraseFile, "passphrase-file", "", `Read the passphrase from a file ("-" for STDIN) instead of prompting`)
+	return cmd
 }
 
 func writeTo(dockerCli command.Cli, reader io.Reader, dest string) error {
@@ -59,11 +73,26 @@ func writeTo(dockerCli command.Cli, reader io.Reader, dest string) error {
 }
 
 // runExport exports a Docker context.
-func runExport(dockerCLI command.Cli, contextName string, dest string) error {
-	if err := store.ValidateContextName(contextName); err != nil && contextName != command.DefaultContextName {
+func runExport(ctx context.Context, dockerCLI command.Cli, opts exportOptions) error {
+	if err := store.ValidateContextName(opts.contextName); err != nil && opts.contextName != command.DefaultContextName {
 		return err
 	}
-	reader := store.Export(contextName, dockerCLI.ContextStore())
+	if opts.passphraseFile != "" && !opts.encrypt {
+		return errors.New("the --passphrase-file option requires --encrypt to be set")
+	}
+	reader := store.Export(opts.contextName, dockerCLI.ContextStore())
 	defer reader.Close()
-	return writeTo(dockerCLI, reader, dest)
+	if !opts.encrypt {
+		return writeTo(dockerCLI, reader, opts.dest)
+	}
+
+	passphrase, err := readPassphrase(ctx, dockerCLI, opts.passphraseFile, true)
+	if err != nil {
+		return err
+	}
+	encrypted, err := store.Encrypt(reader, passphrase)
+	if err != nil {
+		return err
+	}
+	return writeTo(dockerCLI, bytes.NewReader(encrypted), opts.dest)
 }
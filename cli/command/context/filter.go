@@ -0,0 +1,40 @@
+package context
+
+import (
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/moby/moby/client"
+)
+
+// contextFilter returns a function that reports whether a context matches
+// the given filters. The "label" filter matches contexts that have a label
+// with the given key (label=key), or with the given key and value
+// (label=key=value). Contexts must match all label filters.
+func contextFilter(filters client.Filters) (func(store.Metadata) bool, error) {
+	for key := range filters {
+		if key != "label" {
+			return nil, errdefs.ErrInvalidArgument.WithMessage("invalid filter '" + key + "'")
+		}
+	}
+	labelFilters := filters["label"]
+	return func(meta store.Metadata) bool {
+		if len(labelFilters) == 0 {
+			return true
+		}
+		dockerContext, err := command.GetDockerContext(meta)
+		if err != nil {
+			return false
+		}
+		for f := range labelFilters {
+			key, value, hasValue := strings.Cut(f, "=")
+			v, ok := dockerContext.Labels[key]
+			if !ok || (hasValue && v != value) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
@@ -1,6 +1,9 @@
 package context
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -12,32 +15,47 @@
 	"github.com/spf13/cobra"
 )
 
+type importOptions struct {
+	name           string
+	source         string
+	passphraseFile string
+}
+
 func newImportCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts importOptions
 	cmd := &cobra.Command{
-		Use:   "import CONTEXT FILE|-",
+		Use:   "import [OPTIONS] CONTEXT FILE|-",
 		Short: "Import a context from a tar or zip file",
 		Args:  cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runImport(dockerCLI, args[0], args[1])
+			opts.name, opts.source = args[0], args[1]
+			return runImport(cmd.Context(), dockerCLI, opts)
 		},
 		// TODO(thaJeztah): this should also include "-"
 		ValidArgsFunction:     completion.FileNames(),
 		DisableFlagsInUseLine: true,
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.passphraseFile, "passphrase-file", "", `Read the passphrase of an encrypted context from a file ("-" for STDIN) instead of prompting`)
 	return cmd
 }
 
-// runImport imports a Docker context.
-func runImport(dockerCLI command.Cli, name string, source string) error {
-	if err := checkContextNameForCreation(dockerCLI.ContextStore(), name); err != nil {
+// runImport imports a Docker context. Encrypted contexts are decrypted with
+// a passphrase that's read from the passphrase-file, or prompted for.
+func runImport(ctx context.Context, dockerCLI command.Cli, opts importOptions) error {
+	if err := checkContextNameForCreation(dockerCLI.ContextStore(), opts.name); err != nil {
 		return err
 	}
+	if opts.source == "-" && opts.passphraseFile == "-" {
+		return errors.New("conflicting options: cannot read both the context and the passphrase from STDIN")
+	}
 
 	var reader io.Reader
-	if source == "-" {
+	if opts.source == "-" {
 		reader = dockerCLI.In()
 	} else {
-		f, err := os.Open(source)
+		f, err := os.Open(opts.source)
 		if err != nil {
 			return err
 		}
@@ -45,11 +63,23 @@ func runImport(dockerCLI command.Cli, name string, source string) error {
 		reader = f
 	}
 
-	if err := store.Import(name, dockerCLI.ContextStore(), reader); err != nil {
+	br := bufio.NewReader(reader)
+	reader = br
+	if store.IsEncrypted(br) {
+		passphrase, err := readPassphrase(ctx, dockerCLI, opts.passphraseFile, false)
+		if err != nil {
+			return fmt.Errorf("context is encrypted: %w", err)
+		}
+		if reader, err = store.Decrypt(br, passphrase); err != nil {
+			return err
+		}
+	}
+
+	if err := store.Import(opts.name, dockerCLI.ContextStore(), reader); err != nil {
 		return err
 	}
 
-	_, _ = fmt.Fprintln(dockerCLI.Out(), name)
-	_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully imported context %q\n", name)
+	_, _ = fmt.Fprintln(dockerCLI.Out(), opts.name)
+	_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully imported context %q\n", opts.name)
 	return nil
 }
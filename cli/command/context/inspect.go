@@ -8,6 +8,7 @@
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/inspect"
+	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/spf13/cobra"
@@ -54,10 +55,15 @@ func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 		if err != nil {
 			return nil, nil, err
 		}
+		failover, err := failoverState(dockerCli.ContextStore(), c)
+		if err != nil {
+			return nil, nil, err
+		}
 		return contextWithTLSListing{
 			Metadata:    c,
 			TLSMaterial: tlsListing,
 			Storage:     dockerCli.ContextStore().GetStorageInfo(ref),
+			Failover:    failover,
 		}, nil, nil
 	}
 	return inspect.Inspect(dockerCli.Out(), opts.refs, opts.format, getRefFunc)
@@ -67,4 +73,25 @@ type contextWithTLSListing struct {
 	store.Metadata
 	TLSMaterial map[string]store.EndpointFiles
 	Storage     store.StorageInfo
+	// Failover is the host that was last used for a context with failover
+	// hosts; it's omitted if the context has no failover hosts, or if it
+	// was not used yet.
+	Failover *docker.FailoverState `json:",omitempty"`
+}
+
+// failoverState returns the failover state of the context, or nil if the
+// context has no failover hosts, or if no host was selected yet.
+func failoverState(s store.Reader, c store.Metadata) (*docker.FailoverState, error) {
+	ep, err := docker.EndpointFromContext(c)
+	if err != nil || len(ep.FailoverHosts) == 0 {
+		return nil, nil //nolint:nilerr // contexts without a docker endpoint have no failover state.
+	}
+	state, err := docker.LoadFailoverState(s, c.Name)
+	if err != nil {
+		return nil, err
+	}
+	if state.Host == "" {
+		return nil, nil
+	}
+	return &state, nil
 }
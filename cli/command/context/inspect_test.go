@@ -3,8 +3,13 @@
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -23,3 +28,41 @@ func TestInspect(t *testing.T) {
 	expected = strings.Replace(expected, "<TLS_PATH>", strings.ReplaceAll(si.TLSPath, `\`, `\\`), 1)
 	assert.Equal(t, cli.OutBuffer().String(), expected)
 }
+
+func TestInspectFailover(t *testing.T) {
+	cli := makeFakeCli(t)
+	s := cli.ContextStore()
+	assert.NilError(t, s.CreateOrUpdate(store.Metadata{
+		Name:     "failover",
+		Metadata: command.DockerContext{},
+		Endpoints: map[string]any{docker.DockerEndpoint: docker.EndpointMeta{
+			Host:          "tcp://primary.example.com:2376",
+			FailoverHosts: []string{"tcp://secondary.example.com:2376"},
+		}},
+	}))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runInspect(cli, inspectOptions{refs: []string{"failover"}, format: "{{json .Failover}}"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "null\n"), "context was not used yet")
+
+	checkedAt := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	assert.NilError(t, docker.SaveFailoverState(s, "failover", docker.FailoverState{
+		Host:      "tcp://secondary.example.com:2376",
+		CheckedAt: checkedAt,
+	}))
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runInspect(cli, inspectOptions{refs: []string{"failover"}, format: "{{json .Failover}}"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `{"Host":"tcp://secondary.example.com:2376","CheckedAt":"2026-10-19T12:00:00Z"}`+"\n"))
+}
+
+func TestInspectConfigEnv(t *testing.T) {
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "edge", createOptions{
+		config:   []string{"psFormat=table {{.ID}}"},
+		env:      []string{"DOCKER_DEFAULT_PLATFORM=linux/arm64"},
+		endpoint: map[string]string{keyHost: "tcp://edge.example.com:2376"},
+	}))
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runInspect(cli, inspectOptions{refs: []string{"edge"}, format: "{{json .Metadata.Metadata}}"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `{"Config":{"psFormat":"table {{.ID}}"},"Env":{"DOCKER_DEFAULT_PLATFORM":"linux/arm64"}}`+"\n"))
+}
@@ -4,42 +4,55 @@
 package context
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/cli/context/docker"
 	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/opts"
 	"github.com/fvbommel/sortorder"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
 )
 
 type listOptions struct {
-	format string
-	quiet  bool
+	format  string
+	quiet   bool
+	filter  opts.FilterOpt
+	check   bool
+	timeout time.Duration
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
-	opts := &listOptions{}
+	options := &listOptions{filter: opts.NewFilterOpt()}
 	cmd := &cobra.Command{
 		Use:     "ls [OPTIONS]",
 		Aliases: []string{"list"},
 		Short:   "List contexts",
 		Args:    cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(dockerCLI, opts)
+			if options.check {
+				return runListCheck(cmd.Context(), dockerCLI, options)
+			}
+			return runList(dockerCLI, options)
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
 	}
 
 	flags := cmd.Flags()
-	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
-	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show context names")
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only show context names (of reachable contexts with --check)")
+	flags.VarP(&options.filter, "filter", "f", `Provide filter values (e.g. "label=env=prod")`)
+	flags.BoolVar(&options.check, "check", false, "Check the connection to each context")
+	flags.DurationVar(&options.timeout, "timeout", defaultCheckTimeout, "Timeout for checking each context (with --check)")
 	return cmd
 }
 
@@ -47,6 +60,10 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 	if opts.format == "" {
 		opts.format = formatter.TableFormatKey
 	}
+	match, err := contextFilter(opts.filter.Value())
+	if err != nil {
+		return err
+	}
 	contextMap, err := dockerCli.ContextStore().List()
 	if err != nil {
 		return err
@@ -61,6 +78,9 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 		if isCurrent {
 			curFound = true
 		}
+		if !match(rawMeta) {
+			continue
+		}
 		meta, err := command.GetDockerContext(rawMeta)
 		if err != nil {
 			// Add a stub-entry to the list, including the error-message
@@ -81,12 +101,13 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 			Name:           rawMeta.Name,
 			Current:        isCurrent,
 			Description:    meta.Description,
+			Labels:         meta.Labels,
 			DockerEndpoint: dockerEndpoint.Host,
 			Error:          errMsg,
 		}
 		contexts = append(contexts, &desc)
 	}
-	if !curFound {
+	if !curFound && len(opts.filter.Value()) == 0 {
 		// The currently specified context wasn't found. We add a stub-entry
 		// to the list, including the error-message indicating that the context
 		// wasn't found.
@@ -121,3 +142,33 @@ func format(dockerCli command.Cli, opts *listOptions, contexts []*formatter.Clie
 	}
 	return formatter.ClientContextWrite(contextCtx, contexts)
 }
+
+// runListCheck checks the connection to all contexts, and prints the results.
+func runListCheck(ctx context.Context, dockerCLI command.Cli, opts *listOptions) error {
+	match, err := contextFilter(opts.filter.Value())
+	if err != nil {
+		return err
+	}
+	names, err := contextNames(dockerCLI, match)
+	if err != nil {
+		return err
+	}
+	return writeContextChecks(dockerCLI, opts.format, opts.quiet, checkContexts(ctx, dockerCLI, names, opts.timeout))
+}
+
+// writeContextChecks prints the results of context checks. In quiet mode,
+// only the names of reachable contexts are printed.
+func writeContextChecks(dockerCLI command.Cli, format string, quiet bool, checks []*formatter.ContextCheck) error {
+	if format == "" {
+		format = formatter.TableFormatKey
+	}
+	if quiet {
+		checks = slices.DeleteFunc(slices.Clone(checks), func(c *formatter.ContextCheck) bool {
+			return !c.Reachable
+		})
+	}
+	return formatter.ContextCheckWrite(formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: formatter.NewContextCheckFormat(format, quiet),
+	}, checks)
+}
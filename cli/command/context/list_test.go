@@ -1,11 +1,15 @@
 package context
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/opts"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -80,3 +84,49 @@ func TestListError(t *testing.T) {
 	assert.NilError(t, runList(cli, &listOptions{}))
 	golden.Assert(t, cli.OutBuffer().String(), "list-with-error.golden")
 }
+
+func TestListFilterLabel(t *testing.T) {
+	cli := makeFakeCli(t)
+	for name, labels := range map[string][]string{
+		"prod-eu":    {"env=prod", "region=eu"},
+		"prod-us":    {"env=prod", "region=us"},
+		"staging-eu": {"env=staging", "region=eu"},
+		"unlabeled":  nil,
+	} {
+		assert.NilError(t, runCreate(cli, name, createOptions{
+			labels:   labels,
+			endpoint: map[string]string{keyHost: "https://someswarmserver.example.com"},
+		}))
+	}
+
+	tests := []struct {
+		filters  []string
+		expected string
+	}{
+		{filters: []string{"label=env=prod"}, expected: "prod-eu\nprod-us\n"},
+		{filters: []string{"label=env=prod", "label=region=eu"}, expected: "prod-eu\n"},
+		{filters: []string{"label=region"}, expected: "prod-eu\nprod-us\nstaging-eu\n"},
+		{filters: []string{"label=env=dev"}, expected: ""},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.filters, ","), func(t *testing.T) {
+			options := &listOptions{filter: opts.NewFilterOpt(), format: "{{.Name}}"}
+			for _, f := range tc.filters {
+				assert.NilError(t, options.filter.Set(f))
+			}
+			cli.OutBuffer().Reset()
+			assert.NilError(t, runList(cli, options))
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+
+	options := &listOptions{filter: opts.NewFilterOpt()}
+	assert.NilError(t, options.filter.Set("name=prod"))
+	assert.Check(t, is.ErrorType(runList(cli, options), errdefs.IsInvalidArgument))
+	assert.Check(t, is.Error(runList(cli, options), "invalid filter 'name'"))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runList(cli, &listOptions{format: `{{.Name}}: {{.Labels}} {{.Label "env"}}`}))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "prod-eu: env=prod,region=eu prod\n"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "unlabeled:  \n"))
+}
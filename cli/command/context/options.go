@@ -3,8 +3,11 @@
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
+	"github.com/docker/cli/cli/connhelper/ssh"
 	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
@@ -18,6 +21,13 @@
 	keyCert          = "cert"
 	keyKey           = "key"
 	keySkipTLSVerify = "skip-tls-verify"
+	keySSHIdentity   = "ssh-identity"
+	keySSHKnownHosts = "ssh-known-hosts"
+	keySSHJump       = "ssh-jump"
+	keySSHOptions    = "ssh-options"
+	keySSHTransport  = "ssh-transport"
+	keyFailoverHosts = "failover-hosts"
+	keyFailover      = "failover"
 )
 
 type configKeyDescription struct {
@@ -33,6 +43,13 @@ type configKeyDescription struct {
 		keyCert:          {},
 		keyKey:           {},
 		keySkipTLSVerify: {},
+		keySSHIdentity:   {},
+		keySSHKnownHosts: {},
+		keySSHJump:       {},
+		keySSHOptions:    {},
+		keySSHTransport:  {},
+		keyFailoverHosts: {},
+		keyFailover:      {},
 	}
 	dockerConfigKeysDescriptions = []configKeyDescription{
 		{
@@ -59,6 +76,34 @@ type configKeyDescription struct {
 			name:        keySkipTLSVerify,
 			description: "Skip TLS certificate validation",
 		},
+		{
+			name:        keySSHIdentity,
+			description: "Path to the private key for ssh:// hosts (stored in the context)",
+		},
+		{
+			name:        keySSHKnownHosts,
+			description: "Path to the known_hosts file for ssh:// hosts (stored in the context)",
+		},
+		{
+			name:        keySSHJump,
+			description: "Comma-separated jump hosts for ssh:// hosts",
+		},
+		{
+			name:        keySSHOptions,
+			description: "Semicolon-separated ssh options for ssh:// hosts (Key=Value)",
+		},
+		{
+			name:        keySSHTransport,
+			description: `SSH client for ssh:// hosts ("exec" or "native", which ignores ~/.ssh/config)`,
+		},
+		{
+			name:        keyFailoverHosts,
+			description: "Semicolon-separated Docker endpoints to connect to if the host is not reachable",
+		},
+		{
+			name:        keyFailover,
+			description: `Strategy to select a host if failover hosts are set ("order" or "latency")`,
+		},
 	}
 )
 
@@ -110,23 +155,138 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 	if err != nil {
 		return docker.Endpoint{}, err
 	}
+	sshOpts, tlsData, err := getSSHOptions(config, tlsData)
+	if err != nil {
+		return docker.Endpoint{}, err
+	}
+	failoverHosts, failoverStrategy, err := getFailoverOptions(config)
+	if err != nil {
+		return docker.Endpoint{}, err
+	}
 	ep := docker.Endpoint{
 		EndpointMeta: docker.EndpointMeta{
-			Host:          config[keyHost],
-			SkipTLSVerify: skipTLSVerify,
+			Host:             config[keyHost],
+			SkipTLSVerify:    skipTLSVerify,
+			SSH:              sshOpts,
+			FailoverHosts:    failoverHosts,
+			FailoverStrategy: failoverStrategy,
 		},
 		TLSData: tlsData,
 	}
-	// try to resolve a docker client, validating the configuration
+	if err := validateClientOpts(ep); err != nil {
+		return docker.Endpoint{}, err
+	}
+	for _, host := range failoverHosts {
+		if err := validateClientOpts(ep.WithHost(host)); err != nil {
+			return docker.Endpoint{}, fmt.Errorf("invalid failover host %q: %w", host, err)
+		}
+	}
+	return ep, nil
+}
+
+// validateClientOpts tries to resolve a docker client, validating the
+// configuration of the endpoint.
+func validateClientOpts(ep docker.Endpoint) error {
 	opts, err := ep.ClientOpts()
 	if err != nil {
-		return docker.Endpoint{}, fmt.Errorf("invalid docker endpoint options: %w", err)
+		return fmt.Errorf("invalid docker endpoint options: %w", err)
 	}
 	// FIXME(thaJeztah): this creates a new client (but discards it) only to validate the options; are the validation steps above not enough?
 	if _, err := client.New(opts...); err != nil {
-		return docker.Endpoint{}, fmt.Errorf("unable to apply docker endpoint options: %w", err)
+		return fmt.Errorf("unable to apply docker endpoint options: %w", err)
 	}
-	return ep, nil
+	return nil
+}
+
+// getSSHOptions returns the ssh options of the endpoint, and adds the ssh
+// identity and known_hosts files to its TLS data, so that they are stored in
+// the context.
+func getSSHOptions(config map[string]string, tlsData *context.TLSData) (*context.SSHOptions, *context.TLSData, error) {
+	identity, knownHosts := config[keySSHIdentity], config[keySSHKnownHosts]
+	jump, options := config[keySSHJump], config[keySSHOptions]
+	transport := config[keySSHTransport]
+	if identity == "" && knownHosts == "" && jump == "" && options == "" && transport == "" {
+		return nil, tlsData, nil
+	}
+	if !strings.HasPrefix(config[keyHost], "ssh://") {
+		return nil, nil, errors.New("ssh options can only be used for ssh:// hosts")
+	}
+
+	switch transport {
+	case "", context.SSHTransportExec:
+		// the ssh binary is the default.
+		transport = ""
+	case context.SSHTransportNative:
+	default:
+		return nil, nil, fmt.Errorf("invalid ssh transport %q: must be %q or %q", transport, context.SSHTransportExec, context.SSHTransportNative)
+	}
+
+	sshOpts := &context.SSHOptions{ProxyJump: jump, Transport: transport}
+	for _, o := range strings.Split(options, ";") {
+		if o = strings.TrimSpace(o); o != "" {
+			sshOpts.Options = append(sshOpts.Options, o)
+		}
+	}
+	if _, err := (ssh.Options{ProxyJump: sshOpts.ProxyJump, Config: sshOpts.Options}).Flags(); err != nil {
+		return nil, nil, err
+	}
+
+	if tlsData == nil {
+		tlsData = &context.TLSData{}
+	}
+	var err error
+	if identity != "" {
+		if tlsData.SSHIdentity, err = os.ReadFile(identity); err != nil {
+			return nil, nil, err
+		}
+	}
+	if knownHosts != "" {
+		if tlsData.SSHKnownHosts, err = os.ReadFile(knownHosts); err != nil {
+			return nil, nil, err
+		}
+	}
+	if jump == "" && len(sshOpts.Options) == 0 && transport == "" {
+		sshOpts = nil
+	}
+	return sshOpts, tlsData, nil
+}
+
+// getFailoverOptions returns the failover hosts of the endpoint, and the
+// strategy to select a host.
+func getFailoverOptions(config map[string]string) ([]string, string, error) {
+	var hosts []string
+	for _, h := range strings.Split(config[keyFailoverHosts], ";") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	strategy := config[keyFailover]
+	switch strategy {
+	case "", context.FailoverOrder:
+		// trying hosts in order is the default.
+		strategy = ""
+	case context.FailoverLatency:
+	default:
+		return nil, "", fmt.Errorf("invalid failover strategy %q: must be %q or %q", strategy, context.FailoverOrder, context.FailoverLatency)
+	}
+	if len(hosts) == 0 {
+		if strategy != "" {
+			return nil, "", errors.New("failover strategy can only be used with failover hosts")
+		}
+		return nil, "", nil
+	}
+	if config[keyHost] == "" {
+		return nil, "", errors.New("failover hosts can only be used with a host")
+	}
+	// Failover hosts use the TLS and SSH options of the host, which only
+	// apply to hosts with the same scheme.
+	scheme, _, _ := strings.Cut(config[keyHost], "://")
+	for _, h := range hosts {
+		if s, _, _ := strings.Cut(h, "://"); s != scheme {
+			return nil, "", fmt.Errorf("invalid failover host %q: failover hosts must use the same scheme as the host (%s://)", h, scheme)
+		}
+	}
+	return hosts, strategy, nil
 }
 
 func getDockerEndpointMetadataAndTLS(contextStore store.Reader, config map[string]string) (docker.EndpointMeta, *store.EndpointTLSData, error) {
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/prompt"
+)
+
+// minPassphraseLength is the minimum length of passphrases for encrypting
+// exported contexts.
+const minPassphraseLength = 8
+
+// readPassphrase reads the passphrase from the given file ("-" for STDIN), or
+// prompts for it if no file is given. When confirm is set, the passphrase is
+// prompted for twice, and must have at least minPassphraseLength characters.
+func readPassphrase(ctx context.Context, dockerCLI command.Cli, file string, confirm bool) ([]byte, error) {
+	var passphrase []byte
+	if file != "" {
+		var err error
+		if file == "-" {
+			passphrase, err = io.ReadAll(dockerCLI.In())
+		} else {
+			passphrase, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		// Only trim the line-ending, as other whitespace may be part of the passphrase.
+		passphrase = bytes.TrimSuffix(bytes.TrimSuffix(passphrase, []byte("\n")), []byte("\r"))
+	} else {
+		if !dockerCLI.In().IsTerminal() {
+			return nil, errors.New("cannot prompt for a passphrase, because the input is not a terminal: use the --passphrase-file option")
+		}
+		p, err := promptPassphrase(ctx, dockerCLI, "Passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if confirm {
+			p2, err := promptPassphrase(ctx, dockerCLI, "Confirm passphrase: ")
+			if err != nil {
+				return nil, err
+			}
+			if p != p2 {
+				return nil, errors.New("passphrases do not match")
+			}
+		}
+		passphrase = []byte(p)
+	}
+
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+	if confirm && len(passphrase) < minPassphraseLength {
+		return nil, fmt.Errorf("passphrase must have at least %d characters", minPassphraseLength)
+	}
+	return passphrase, nil
+}
+
+// promptPassphrase prompts for a passphrase without echoing the input. The
+// prompt is printed to STDERR, as STDOUT may be used for the exported context.
+func promptPassphrase(ctx context.Context, dockerCLI command.Cli, message string) (string, error) {
+	restoreInput, err := prompt.DisableInputEcho(dockerCLI.In())
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := restoreInput(); err != nil {
+			_, _ = fmt.Fprintln(dockerCLI.Err(), "Error: failed to restore terminal state to echo input:", err)
+		}
+	}()
+	p, err := prompt.ReadInput(ctx, dockerCLI.In(), dockerCLI.Err(), message)
+	_, _ = fmt.Fprintln(dockerCLI.Err())
+	return p, err
+}
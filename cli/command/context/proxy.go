@@ -0,0 +1,417 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// proxyShutdownTimeout is the time to wait for requests to complete
+	// when the proxy is stopped.
+	proxyShutdownTimeout = 5 * time.Second
+
+	// proxyMaxIdleConns is the number of idle connections to the daemon
+	// that are kept open for reuse.
+	proxyMaxIdleConns = 16
+)
+
+type proxyOptions struct {
+	name   string
+	listen string
+	quiet  bool
+}
+
+// newProxyCommand creates a new cobra.Command for `docker context proxy`
+func newProxyCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts proxyOptions
+	cmd := &cobra.Command{
+		Use:   "proxy [OPTIONS] [CONTEXT]",
+		Short: "Expose the Docker endpoint of a context on a local socket",
+		Long:  proxyDescription,
+		Args:  cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.name = args[0]
+			}
+			return runProxy(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completeContextNames(dockerCLI, 1, false),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.listen, "listen", "", `Address to listen on ("unix://<path>" or "tcp://<host>:<port>"); defaults to "unix://<config-dir>/run/<context>.sock"`)
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Do not log requests")
+	return cmd
+}
+
+var proxyDescription = `
+Listen on a local unix socket or TCP port, and forward the connections to the
+Docker endpoint of a context, which defaults to the current context. This
+allows tools that only connect to a local socket to use contexts that connect
+over SSH, or use TLS. Connections to the daemon are reused between requests.
+
+Anyone who can connect to the socket or port has full access to the daemon.
+The unix socket is only accessible by the current user.
+`
+
+func runProxy(ctx context.Context, dockerCLI command.Cli, opts proxyOptions) error {
+	name := opts.name
+	if name == "" {
+		name = dockerCLI.CurrentContext()
+	}
+	if _, err := dockerCLI.ContextStore().GetMetadata(name); err != nil {
+		return err
+	}
+	listen := opts.listen
+	if listen == "" {
+		listen = "unix://" + filepath.Join(config.Dir(), "run", name+".sock")
+	}
+
+	var apiClient client.APIClient
+	if name == dockerCLI.CurrentContext() {
+		apiClient = dockerCLI.Client()
+	} else {
+		var err error
+		if apiClient, err = command.NewAPIClientForContext(dockerCLI.ContextStore(), name, dockerCLI.ConfigFile()); err != nil {
+			return err
+		}
+		defer apiClient.Close()
+	}
+
+	l, cleanup, err := listenProxy(listen)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	if proto, addr, _ := strings.Cut(listen, "://"); proto == "tcp" && !isLoopback(addr) {
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: listening on %s, which allows other hosts to connect to the daemon\n", listen)
+	}
+
+	var logOut io.Writer = dockerCLI.Err()
+	if opts.quiet {
+		logOut = io.Discard
+	}
+	_, _ = fmt.Fprintf(dockerCLI.Err(), "Forwarding %s to context %q\n", listen, name)
+	err = newContextProxy(apiClient.Dialer(), logOut).serve(ctx, l)
+	_, _ = fmt.Fprintf(dockerCLI.Err(), "Stopped forwarding %s to context %q\n", listen, name)
+	return err
+}
+
+// listenProxy listens on the given address, and returns a function to remove
+// the unix socket after the listener is closed.
+func listenProxy(listen string) (net.Listener, func(), error) {
+	proto, addr, ok := strings.Cut(listen, "://")
+	if !ok || addr == "" {
+		return nil, nil, fmt.Errorf("invalid listen address %q: must be unix://<path> or tcp://<host>:<port>", listen)
+	}
+	switch proto {
+	case "tcp":
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return l, func() {}, nil
+	case "unix":
+		if err := os.MkdirAll(filepath.Dir(addr), 0o700); err != nil {
+			return nil, nil, err
+		}
+		if err := removeStaleSocket(addr); err != nil {
+			return nil, nil, err
+		}
+		l, err := listenUnix(addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return l, func() { _ = os.Remove(addr) }, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid listen address %q: must be unix://<path> or tcp://<host>:<port>", listen)
+	}
+}
+
+// listenUnix listens on a unix socket at path that's only accessible by the
+// current user. The socket is created in a new directory that's only
+// accessible by the current user, and moved to path once its permissions are
+// set, so that other users can't connect to it in the meantime.
+func listenUnix(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".docker-proxy-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "docker.sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// removeStaleSocket removes a unix socket that's left behind by a proxy that
+// was not stopped cleanly. It returns an error if the socket is in use.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("cannot listen on %s: file exists", path)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("cannot listen on %s: address already in use", path)
+	}
+	return os.Remove(path)
+}
+
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// contextProxy forwards HTTP requests to the daemon. Requests are sent over
+// a pool of connections that are reused between requests. Requests that
+// upgrade the connection, such as attaching to a container, use a dedicated
+// connection, which is forwarded as-is.
+type contextProxy struct {
+	dial      func(context.Context) (net.Conn, error)
+	logOut    io.Writer
+	transport *http.Transport
+	proxy     *httputil.ReverseProxy
+
+	mu      sync.Mutex
+	tunnels map[net.Conn]struct{}
+	wg      sync.WaitGroup
+}
+
+func newContextProxy(dial func(context.Context) (net.Conn, error), logOut io.Writer) *contextProxy {
+	p := &contextProxy{
+		dial:    dial,
+		logOut:  logOut,
+		tunnels: make(map[net.Conn]struct{}),
+	}
+	p.transport = &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			logrus.Debug("proxy: opening connection to the daemon")
+			return dial(ctx)
+		},
+		MaxIdleConns:        proxyMaxIdleConns,
+		MaxIdleConnsPerHost: proxyMaxIdleConns,
+		IdleConnTimeout:     90 * time.Second,
+		DisableCompression:  true,
+	}
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Scheme = "http"
+			r.Out.URL.Host = "docker"
+			r.Out.Host = r.In.Host
+		},
+		Transport:     p.transport,
+		FlushInterval: -1,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logrus.WithError(err).Debugf("proxy: %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = fmt.Fprintf(w, "failed to connect to the daemon: %v\n", err)
+		},
+	}
+	return p
+}
+
+// serve serves connections on the listener until the context is cancelled.
+// Requests that are in progress are given time to complete before they are
+// aborted.
+func (p *contextProxy) serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 30 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return context.WithoutCancel(ctx) },
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), proxyShutdownTimeout)
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			_ = srv.Close()
+		}
+		cancel()
+		<-errCh
+	}
+	p.closeTunnels()
+	p.transport.CloseIdleConnections()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (p *contextProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	if r.Header.Get("Upgrade") != "" {
+		if err := p.tunnel(w, r); err != nil {
+			logrus.WithError(err).Debugf("proxy: %s %s", r.Method, r.URL.RequestURI())
+			p.logf("%s %s failed: %v", r.Method, r.URL.RequestURI(), err)
+			return
+		}
+		p.logf("%s %s upgraded %s", r.Method, r.URL.RequestURI(), time.Since(start).Round(time.Millisecond))
+		return
+	}
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	p.proxy.ServeHTTP(sw, r)
+	p.logf("%s %s %d %s", r.Method, r.URL.RequestURI(), sw.status, time.Since(start).Round(time.Millisecond))
+}
+
+func (p *contextProxy) logf(format string, args ...any) {
+	_, _ = fmt.Fprintf(p.logOut, time.Now().Format(time.RFC3339)+" "+format+"\n", args...)
+}
+
+// tunnel forwards a request that upgrades the connection over a dedicated
+// connection to the daemon, and copies the streams in both directions until
+// the daemon closes the connection.
+func (p *contextProxy) tunnel(w http.ResponseWriter, r *http.Request) error {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection cannot be upgraded", http.StatusInternalServerError)
+		return errors.New("connection cannot be upgraded")
+	}
+	backend, err := p.dial(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = fmt.Fprintf(w, "failed to connect to the daemon: %v\n", err)
+		return err
+	}
+	if err := r.Write(backend); err != nil {
+		_ = backend.Close()
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return err
+	}
+	conn, buf, err := hj.Hijack()
+	if err != nil {
+		_ = backend.Close()
+		return err
+	}
+	if !p.addTunnel(conn, backend) {
+		return errors.New("proxy is shutting down")
+	}
+	defer p.removeTunnel(conn, backend)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Forward data from the client, including data that was buffered
+		// while reading the request.
+		_, _ = io.Copy(backend, io.MultiReader(buf.Reader, conn))
+		closeWrite(backend)
+	}()
+	_, _ = io.Copy(conn, backend)
+	closeWrite(conn)
+	// The daemon closed the connection; don't wait for the client to close
+	// its side of the connection, as it's not closed for TTYs.
+	_ = conn.Close()
+	_ = backend.Close()
+	<-done
+	return nil
+}
+
+func (p *contextProxy) addTunnel(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tunnels == nil {
+		for _, c := range conns {
+			_ = c.Close()
+		}
+		return false
+	}
+	for _, c := range conns {
+		p.tunnels[c] = struct{}{}
+	}
+	p.wg.Add(1)
+	return true
+}
+
+func (p *contextProxy) removeTunnel(conns ...net.Conn) {
+	p.mu.Lock()
+	for _, c := range conns {
+		delete(p.tunnels, c)
+	}
+	p.mu.Unlock()
+	p.wg.Done()
+}
+
+// closeTunnels closes the connections of upgraded requests, and waits for
+// the tunnels to stop.
+func (p *contextProxy) closeTunnels() {
+	p.mu.Lock()
+	for c := range p.tunnels {
+		_ = c.Close()
+	}
+	p.tunnels = nil
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func closeWrite(conn net.Conn) {
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+	}
+}
+
+// statusWriter records the status code of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap allows [http.ResponseController] to flush the response.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,180 @@
+package context
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/poll"
+)
+
+func TestProxy(t *testing.T) {
+	var conns atomic.Int32
+	daemon := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK " + r.URL.Path))
+	}))
+	daemon.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	daemon.Start()
+	t.Cleanup(daemon.Close)
+
+	dockerCLI := makeFakeCli(t)
+	createHostContext(t, dockerCLI, "remote", "tcp://"+daemon.Listener.Addr().String())
+
+	socket := filepath.Join(t.TempDir(), "remote.sock")
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runProxy(ctx, dockerCLI, proxyOptions{name: "remote", listen: "unix://" + socket})
+	}()
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if _, err := os.Stat(socket); err != nil {
+			return poll.Continue("waiting for socket: %v", err)
+		}
+		return poll.Success()
+	})
+	fi, err := os.Stat(socket)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0o600)))
+
+	c := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+	for _, p := range []string{"/_ping", "/containers/json", "/images/json"} {
+		resp, err := c.Get("http://docker" + p)
+		assert.NilError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(resp.StatusCode, http.StatusOK))
+		assert.Check(t, is.Equal(string(body), "OK "+p))
+	}
+	assert.Check(t, is.Equal(conns.Load(), int32(1)), "expected connections to the daemon to be reused")
+
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.NilError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the proxy to stop")
+	}
+	_, err = os.Stat(socket)
+	assert.Check(t, os.IsNotExist(err), "expected the socket to be removed")
+
+	stderr := dockerCLI.ErrBuffer().String()
+	assert.Check(t, is.Contains(stderr, `Forwarding unix://`+socket+` to context "remote"`))
+	assert.Check(t, is.Contains(stderr, "GET /containers/json 200"))
+}
+
+func TestProxyUpgrade(t *testing.T) {
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = buf.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		_ = buf.Flush()
+		// echo until the client closes its side of the connection.
+		_, _ = io.Copy(conn, buf)
+		_, _ = conn.Write([]byte("bye"))
+	}))
+	t.Cleanup(daemon.Close)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	dial := func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", daemon.Listener.Addr().String())
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- newContextProxy(dial, io.Discard).serve(ctx, l)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	assert.NilError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("POST /containers/foo/attach HTTP/1.1\r\nHost: docker\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n"))
+	assert.NilError(t, err)
+
+	rd := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(rd, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(resp.StatusCode, http.StatusSwitchingProtocols))
+
+	_, err = conn.Write([]byte("hello"))
+	assert.NilError(t, err)
+	assert.NilError(t, conn.(*net.TCPConn).CloseWrite())
+	out, err := io.ReadAll(rd)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(out), "hellobye"))
+
+	cancel()
+	assert.NilError(t, <-errCh)
+}
+
+func TestListenProxy(t *testing.T) {
+	t.Run("invalid address", func(t *testing.T) {
+		for _, addr := range []string{"", "/tmp/docker.sock", "unix://", "npipe:////./pipe/docker"} {
+			_, _, err := listenProxy(addr)
+			assert.Check(t, is.ErrorContains(err, "must be unix://<path> or tcp://<host>:<port>"), addr)
+		}
+	})
+	t.Run("stale socket", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "run", "stale.sock")
+		l, _, err := listenProxy("unix://" + socket)
+		assert.NilError(t, err)
+		_, _, err = listenProxy("unix://" + socket)
+		assert.Check(t, is.ErrorContains(err, "address already in use"))
+
+		// simulate a proxy that was not stopped cleanly.
+		l.(*net.UnixListener).SetUnlinkOnClose(false)
+		assert.NilError(t, l.Close())
+		l, cleanup, err := listenProxy("unix://" + socket)
+		assert.NilError(t, err)
+		assert.NilError(t, l.Close())
+		cleanup()
+
+		// the temporary directory of the socket is removed.
+		entries, err := os.ReadDir(filepath.Dir(socket))
+		assert.NilError(t, err)
+		assert.Check(t, is.Len(entries, 0))
+	})
+	t.Run("regular file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		assert.NilError(t, os.WriteFile(file, nil, 0o600))
+		_, _, err := listenProxy("unix://" + file)
+		assert.Check(t, is.ErrorContains(err, "file exists"))
+	})
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, expected := range map[string]bool{
+		"127.0.0.1:2375": true,
+		"[::1]:2375":     true,
+		"localhost:2375": true,
+		"0.0.0.0:2375":   false,
+		":2375":          false,
+		"10.0.0.1:2375":  false,
+	} {
+		assert.Check(t, is.Equal(isLoopback(addr), expected), addr)
+	}
+}
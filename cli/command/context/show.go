@@ -5,25 +5,84 @@
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/templates"
 	"github.com/spf13/cobra"
 )
 
+type showOptions struct {
+	format string
+}
+
+// contextSelector is implemented by CLIs that keep track of what selected
+// the current context.
+type contextSelector interface {
+	ContextSelection() command.ContextSelection
+	ProjectContext() *command.ProjectContext
+}
+
+// showContext is the information that's printed by "docker context show".
+type showContext struct {
+	Name     string
+	Source   command.ContextSource `json:",omitempty"`
+	Origin   string                `json:",omitempty"`
+	Platform string                `json:",omitempty"`
+	Labels   []string              `json:",omitempty"`
+}
+
 // newShowCommand creates a new cobra.Command for `docker context sow`
 func newShowCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts showOptions
 	cmd := &cobra.Command{
-		Use:   "show",
+		Use:   "show [OPTIONS]",
 		Short: "Print the name of the current context",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			runShow(dockerCLI)
-			return nil
+			return runShow(dockerCLI, opts)
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
 	}
+	cmd.Flags().StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
 	return cmd
 }
 
-func runShow(dockerCli command.Cli) {
-	fmt.Fprintln(dockerCli.Out(), dockerCli.CurrentContext())
+func runShow(dockerCli command.Cli, opts showOptions) error {
+	current := showContext{Name: dockerCli.CurrentContext()}
+	if s, ok := dockerCli.(contextSelector); ok {
+		sel := s.ContextSelection()
+		current.Source, current.Origin = sel.Source, sel.Origin
+		if pc := s.ProjectContext(); pc != nil {
+			current.Platform, current.Labels = pc.Platform, pc.Labels
+		}
+	}
+
+	if opts.format == "" {
+		if dockerCli.Out().IsTerminal() && current.Origin != "" {
+			// Only print the name when not attached to a terminal, as the
+			// output is commonly used in scripts.
+			_, _ = fmt.Fprintf(dockerCli.Out(), "%s (set by %s)\n", current.Name, current.Origin)
+			return nil
+		}
+		_, _ = fmt.Fprintln(dockerCli.Out(), current.Name)
+		return nil
+	}
+
+	format := opts.format
+	if format == formatter.JSONFormatKey {
+		format = formatter.JSONFormat
+	}
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return cli.StatusError{
+			StatusCode: 64,
+			Status:     "template parsing error: " + err.Error(),
+		}
+	}
+	if err := tmpl.Execute(dockerCli.Out(), current); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out())
+	return nil
 }
@@ -3,6 +3,10 @@
 import (
 	"testing"
 
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -12,6 +16,63 @@ func TestShow(t *testing.T) {
 	cli.SetCurrentContext("current")
 
 	cli.OutBuffer().Reset()
-	runShow(cli)
+	assert.NilError(t, runShow(cli, showOptions{}))
 	golden.Assert(t, cli.OutBuffer().String(), "show.golden")
 }
+
+// fakeSelectorCli is a [test.FakeCli] that keeps track of what selected the
+// current context.
+type fakeSelectorCli struct {
+	*test.FakeCli
+	selection      command.ContextSelection
+	projectContext *command.ProjectContext
+}
+
+func (c *fakeSelectorCli) ContextSelection() command.ContextSelection {
+	return c.selection
+}
+
+func (c *fakeSelectorCli) ProjectContext() *command.ProjectContext {
+	return c.projectContext
+}
+
+func TestShowSelection(t *testing.T) {
+	fakeCli := makeFakeCli(t)
+	fakeCli.SetCurrentContext("current")
+	cli := &fakeSelectorCli{
+		FakeCli:   fakeCli,
+		selection: command.ContextSelection{Name: "current", Source: command.ContextSourceProject, Origin: "/src/project/.docker-context"},
+		projectContext: &command.ProjectContext{
+			Context:  "current",
+			Platform: "linux/arm64",
+			Labels:   []string{"com.example.project=web"},
+			Filename: "/src/project/.docker-context",
+		},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		format     string
+		isTerminal bool
+		expected   string
+	}{
+		{name: "no terminal", expected: "current\n"},
+		{name: "terminal", isTerminal: true, expected: "current (set by /src/project/.docker-context)\n"},
+		{name: "template", format: "{{.Source}}", isTerminal: true, expected: "project\n"},
+		{
+			name:     "json",
+			format:   "json",
+			expected: `{"Name":"current","Source":"project","Origin":"/src/project/.docker-context","Platform":"linux/arm64","Labels":["com.example.project=web"]}` + "\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeCli.OutBuffer().Reset()
+			fakeCli.Out().SetIsTerminal(tc.isTerminal)
+			assert.NilError(t, runShow(cli, showOptions{format: tc.format}))
+			assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), tc.expected))
+		})
+	}
+
+	err := runShow(cli, showOptions{format: "{{.Name"})
+	assert.Check(t, is.ErrorContains(err, "template parsing error"))
+}
@@ -0,0 +1,65 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package context
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type testOptions struct {
+	format  string
+	quiet   bool
+	timeout time.Duration
+	names   []string
+}
+
+// newTestCommand creates a new cobra.Command for `docker context test`
+func newTestCommand(dockerCLI command.Cli) *cobra.Command {
+	opts := &testOptions{}
+	cmd := &cobra.Command{
+		Use:   "test [OPTIONS] [CONTEXT...]",
+		Short: "Check the connection to one or more contexts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runTest(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     completeContextNames(dockerCLI, -1, false),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show the names of reachable contexts")
+	flags.DurationVar(&opts.timeout, "timeout", defaultCheckTimeout, "Timeout for checking each context")
+	return cmd
+}
+
+// runTest checks the connection to the given contexts, or all contexts if no
+// context is given. It returns an error if a context is not reachable.
+func runTest(ctx context.Context, dockerCLI command.Cli, opts *testOptions) error {
+	names := opts.names
+	if len(names) == 0 {
+		var err error
+		if names, err = contextNames(dockerCLI, nil); err != nil {
+			return err
+		}
+	}
+
+	checks := checkContexts(ctx, dockerCLI, names, opts.timeout)
+	if err := writeContextChecks(dockerCLI, opts.format, opts.quiet, checks); err != nil {
+		return err
+	}
+	for _, c := range checks {
+		if !c.Reachable {
+			return cli.StatusError{StatusCode: 1}
+		}
+	}
+	return nil
+}
@@ -3,18 +3,26 @@
 import (
 	"bytes"
 	"fmt"
+	"maps"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter/tabwriter"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
+	cliopts "github.com/docker/cli/opts"
 	"github.com/spf13/cobra"
 )
 
 // updateOptions are the options used to update a context.
 type updateOptions struct {
 	description string
+	labels      []string
+	labelsRm    []string
+	config      []string
+	configRm    []string
+	env         []string
+	envRm       []string
 	endpoint    map[string]string
 }
 
@@ -46,6 +54,12 @@ func newUpdateCommand(dockerCLI command.Cli) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&opts.description, "description", "", "Description of the context")
+	flags.Var(cliopts.NewListOptsRef(&opts.labels, cliopts.ValidateLabel), "label", `Add or update a context label ("key=value")`)
+	flags.Var(cliopts.NewListOptsRef(&opts.labelsRm, nil), "label-rm", "Remove a context label if exists")
+	flags.Var(cliopts.NewListOptsRef(&opts.config, nil), "config", `Add or update a configuration option for the context ("key=value")`)
+	flags.Var(cliopts.NewListOptsRef(&opts.configRm, nil), "config-rm", "Remove a configuration option of the context if exists")
+	flags.Var(cliopts.NewListOptsRef(&opts.env, nil), "env", `Add or update a default environment variable for the context ("KEY=VALUE")`)
+	flags.Var(cliopts.NewListOptsRef(&opts.envRm, nil), "env-rm", "Remove a default environment variable of the context if exists")
 	flags.StringToStringVar(&opts.endpoint, "docker", nil, "set the docker endpoint")
 	return cmd
 }
@@ -67,6 +81,26 @@ func runUpdate(dockerCLI command.Cli, name string, opts updateOptions) error {
 	if opts.description != "" {
 		dockerContext.Description = opts.description
 	}
+	if len(opts.labels) > 0 || len(opts.labelsRm) > 0 {
+		labels := maps.Clone(dockerContext.Labels)
+		if labels == nil {
+			labels = make(map[string]string, len(opts.labels))
+		}
+		for _, k := range opts.labelsRm {
+			delete(labels, k)
+		}
+		maps.Copy(labels, labelsFromOptions(opts.labels))
+		if len(labels) == 0 {
+			labels = nil
+		}
+		dockerContext.Labels = labels
+	}
+	if dockerContext.Config, err = updateConfig(dockerContext.Config, opts.config, opts.configRm); err != nil {
+		return err
+	}
+	if dockerContext.Env, err = updateEnv(dockerContext.Env, opts.env, opts.envRm); err != nil {
+		return err
+	}
 
 	c.Metadata = dockerContext
 
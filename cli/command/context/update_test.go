@@ -60,3 +60,99 @@ func TestUpdateInvalidDockerHost(t *testing.T) {
 	})
 	assert.ErrorContains(t, err, "unable to parse docker host")
 }
+
+func TestUpdateLabels(t *testing.T) {
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "test", createOptions{
+		labels:   []string{"env=staging", "region=eu"},
+		endpoint: map[string]string{},
+	}))
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		labels:   []string{"env=prod", "team=infra"},
+		labelsRm: []string{"region"},
+	}))
+	c, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	dc, err := command.GetDockerContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(dc.Labels, map[string]string{"env": "prod", "team": "infra"}))
+
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{labelsRm: []string{"env", "team"}}))
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	dc, err = command.GetDockerContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(dc.Labels))
+}
+
+func TestUpdateConfigEnv(t *testing.T) {
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "test", createOptions{
+		config:   []string{"psFormat=table {{.ID}}", "proxies.default.httpProxy=http://proxy.example.com:3128"},
+		env:      []string{"DOCKER_DEFAULT_PLATFORM=linux/amd64"},
+		endpoint: map[string]string{},
+	}))
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		config:   []string{"imagesFormat=table {{.Repository}}"},
+		configRm: []string{"proxies"},
+		env:      []string{"DOCKER_DEFAULT_PLATFORM=linux/arm64", "HTTPS_PROXY=http://proxy.example.com:3128"},
+	}))
+	c, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	dc, err := command.GetDockerContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(dc.Config, map[string]any{"psFormat": "table {{.ID}}", "imagesFormat": "table {{.Repository}}"}))
+	assert.Check(t, is.DeepEqual(dc.Env, map[string]string{"DOCKER_DEFAULT_PLATFORM": "linux/arm64", "HTTPS_PROXY": "http://proxy.example.com:3128"}))
+
+	assert.NilError(t, runUpdate(cli, "test", updateOptions{
+		configRm: []string{"psFormat", "imagesFormat"},
+		envRm:    []string{"DOCKER_DEFAULT_PLATFORM", "HTTPS_PROXY"},
+	}))
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	dc, err = command.GetDockerContext(c)
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(dc.Config))
+	assert.Check(t, is.Nil(dc.Env))
+}
+
+func TestUpdateInvalidConfigEnv(t *testing.T) {
+	cli := makeFakeCli(t)
+	assert.NilError(t, runCreate(cli, "test", createOptions{endpoint: map[string]string{}}))
+	for _, tc := range []struct {
+		doc         string
+		opts        updateOptions
+		expectedErr string
+	}{
+		{
+			doc:         "not permitted",
+			opts:        updateOptions{config: []string{"credsStore=evil"}},
+			expectedErr: `configuration option "credsStore" cannot be set for a context`,
+		},
+		{
+			doc:         "unknown option",
+			opts:        updateOptions{config: []string{"noSuchFormat=table"}},
+			expectedErr: "unknown configuration option: noSuchFormat",
+		},
+		{
+			doc:         "missing value",
+			opts:        updateOptions{config: []string{"psFormat"}},
+			expectedErr: `invalid configuration option "psFormat": must be in key=value format`,
+		},
+		{
+			doc:         "invalid env",
+			opts:        updateOptions{env: []string{"DOCKER_HOST=tcp://evil.example.com:2375"}},
+			expectedErr: `invalid environment variable "DOCKER_HOST": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`,
+		},
+		{
+			doc:         "missing env value",
+			opts:        updateOptions{env: []string{"DOCKER_DEFAULT_PLATFORM"}},
+			expectedErr: `invalid environment variable "DOCKER_DEFAULT_PLATFORM": must be in KEY=VALUE format`,
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			err := runUpdate(cli, "test", tc.opts)
+			assert.Check(t, is.Error(err, tc.expectedErr))
+		})
+	}
+}
@@ -1,30 +1,82 @@
 package context
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/opts"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
 )
 
 func newUseCommand(dockerCLI command.Cli) *cobra.Command {
+	filter := opts.NewFilterOpt()
 	cmd := &cobra.Command{
-		Use:   "use CONTEXT",
+		Use:   "use [OPTIONS] CONTEXT",
 		Short: "Set the current docker context",
-		Args:  cobra.ExactArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(filter.Value()) > 0 {
+				return cli.RequiresMaxArgs(1)(cmd, args)
+			}
+			return cli.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if len(filter.Value()) > 0 {
+				var err error
+				if name, err = selectContext(dockerCLI, name, filter.Value()); err != nil {
+					return err
+				}
+			}
 			return runUse(dockerCLI, name)
 		},
 		ValidArgsFunction:     completeContextNames(dockerCLI, 1, false),
 		DisableFlagsInUseLine: true,
 	}
+	cmd.Flags().VarP(&filter, "filter", "f", `Select the context matching the filter (e.g. "label=env=prod")`)
 	return cmd
 }
 
+// selectContext returns the context matching the filters. If a name is
+// given, the context with that name is returned if it matches the filters.
+// Otherwise, exactly one context must match the filters.
+func selectContext(dockerCLI command.Cli, name string, filters client.Filters) (string, error) {
+	match, err := contextFilter(filters)
+	if err != nil {
+		return "", err
+	}
+	if name != "" {
+		meta, err := dockerCLI.ContextStore().GetMetadata(name)
+		if err != nil {
+			return "", err
+		}
+		if !match(meta) {
+			return "", fmt.Errorf("context %q does not match the filter", name)
+		}
+		return name, nil
+	}
+	names, err := contextNames(dockerCLI, match)
+	if err != nil {
+		return "", err
+	}
+	switch len(names) {
+	case 0:
+		return "", errors.New("no context matches the filter")
+	case 1:
+		return names[0], nil
+	default:
+		return "", fmt.Errorf("multiple contexts match the filter (%s): specify the context to use", strings.Join(names, ", "))
+	}
+}
+
 // runUse set the current Docker context
 func runUse(dockerCLI command.Cli, name string) error {
 	// configValue uses an empty string for "default"
@@ -54,5 +106,11 @@ func runUse(dockerCLI command.Cli, name string) error {
 		_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s environment variable overrides the active context. "+
 			"To use %[2]q, either set the global --context flag, or unset %[1]s environment variable.\n", client.EnvOverrideHost, name)
 	}
+	if s, ok := dockerCLI.(contextSelector); ok {
+		if pc := s.ProjectContext(); pc != nil && pc.Context != "" && pc.Context != name {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %s selects the %q context in this directory. "+
+				"To use %q, either set the global --context flag, or update the file.\n", pc.Filename, pc.Context, name)
+		}
+	}
 	return nil
 }
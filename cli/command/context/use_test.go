@@ -152,3 +152,65 @@ func TestUseHostOverrideEmpty(t *testing.T) {
 	apiclient := cli.Client()
 	assert.Equal(t, apiclient.DaemonHost(), socketPath)
 }
+
+func TestUseProjectContextOverride(t *testing.T) {
+	configDir := t.TempDir()
+	configFilePath := filepath.Join(configDir, "config.json")
+	testCfg := configfile.New(configFilePath)
+	fakeCli := makeFakeCli(t, withCliConfig(testCfg))
+	err := runCreate(fakeCli, "test", createOptions{
+		endpoint: map[string]string{},
+	})
+	assert.NilError(t, err)
+	cli := &fakeSelectorCli{
+		FakeCli:        fakeCli,
+		projectContext: &command.ProjectContext{Context: "project-context", Filename: "/src/project/.docker-context"},
+	}
+
+	fakeCli.ResetOutputBuffers()
+	err = runUse(cli, "test")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Contains(
+		fakeCli.ErrBuffer().String(),
+		`Warning: /src/project/.docker-context selects the "project-context" context in this directory.`,
+	))
+	assert.Assert(t, is.Contains(fakeCli.ErrBuffer().String(), `Current context is now "test"`))
+}
+
+func TestUseFilter(t *testing.T) {
+	configDir := t.TempDir()
+	cli := makeFakeCli(t, withCliConfig(configfile.New(filepath.Join(configDir, "config.json"))))
+	for name, labels := range map[string][]string{
+		"prod-eu":    {"env=prod", "region=eu"},
+		"prod-us":    {"env=prod", "region=us"},
+		"staging-eu": {"env=staging", "region=eu"},
+	} {
+		assert.NilError(t, runCreate(cli, name, createOptions{labels: labels, endpoint: map[string]string{}}))
+	}
+
+	useWithFilter := func(filter string, args ...string) error {
+		cmd := newUseCommand(cli)
+		assert.NilError(t, cmd.Flags().Set("filter", filter))
+		if err := cmd.Args(cmd, args); err != nil {
+			return err
+		}
+		return cmd.RunE(cmd, args)
+	}
+
+	assert.NilError(t, useWithFilter("label=env=staging"))
+	assert.Check(t, is.Equal(cli.ConfigFile().CurrentContext, "staging-eu"))
+
+	assert.NilError(t, useWithFilter("label=env=prod", "prod-us"))
+	assert.Check(t, is.Equal(cli.ConfigFile().CurrentContext, "prod-us"))
+
+	err := useWithFilter("label=env=prod")
+	assert.Check(t, is.Error(err, "multiple contexts match the filter (prod-eu, prod-us): specify the context to use"))
+	err = useWithFilter("label=env=dev")
+	assert.Check(t, is.Error(err, "no context matches the filter"))
+	err = useWithFilter("label=env=prod", "staging-eu")
+	assert.Check(t, is.Error(err, `context "staging-eu" does not match the filter`))
+	assert.Check(t, is.Equal(cli.ConfigFile().CurrentContext, "prod-us"))
+
+	cmd := newUseCommand(cli)
+	assert.Check(t, is.ErrorContains(cmd.Args(cmd, nil), "requires 1 argument"))
+}
@@ -27,3 +27,41 @@ func TestDockerContextMetadataKeepAdditionalFields(t *testing.T) {
 	assert.Equal(t, c2.AdditionalFields["foo"], "bar")
 	assert.Equal(t, c2.Description, "test")
 }
+
+func TestDockerContextMetadataLabels(t *testing.T) {
+	c := DockerContext{
+		Description: "test",
+		Labels:      map[string]string{"env": "prod", "region": "eu"},
+	}
+	jsonBytes, err := json.Marshal(c)
+	assert.NilError(t, err)
+	const expected = `{"Description":"test","Labels":{"env":"prod","region":"eu"}}`
+	assert.Equal(t, string(jsonBytes), expected)
+
+	var c2 DockerContext
+	assert.NilError(t, json.Unmarshal(jsonBytes, &c2))
+	assert.DeepEqual(t, c2, c)
+
+	err = json.Unmarshal([]byte(`{"Labels":{"env":1}}`), &c2)
+	assert.Error(t, err, `invalid value for context label "env": must be a string`)
+}
+
+func TestDockerContextMetadataConfigEnv(t *testing.T) {
+	c := DockerContext{
+		Config: map[string]any{"psFormat": "table {{.ID}}"},
+		Env:    map[string]string{"DOCKER_DEFAULT_PLATFORM": "linux/arm64"},
+	}
+	jsonBytes, err := json.Marshal(c)
+	assert.NilError(t, err)
+	const expected = `{"Config":{"psFormat":"table {{.ID}}"},"Env":{"DOCKER_DEFAULT_PLATFORM":"linux/arm64"}}`
+	assert.Equal(t, string(jsonBytes), expected)
+
+	var c2 DockerContext
+	assert.NilError(t, json.Unmarshal(jsonBytes, &c2))
+	assert.DeepEqual(t, c2, c)
+
+	err = json.Unmarshal([]byte(`{"Config":"psFormat"}`), &c2)
+	assert.Error(t, err, "invalid context configuration: must be an object")
+	err = json.Unmarshal([]byte(`{"Env":{"DOCKER_DEFAULT_PLATFORM":true}}`), &c2)
+	assert.Error(t, err, `invalid value for context environment variable "DOCKER_DEFAULT_PLATFORM": must be a string`)
+}
@@ -0,0 +1,71 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/sirupsen/logrus"
+)
+
+// contextEnvAllowed are the environment variables that can be set by a
+// context. Other variables could select a different context, daemon, or
+// configuration, hold credentials, or change which binaries are run, and
+// are not permitted, as contexts can be imported from other users.
+var contextEnvAllowed = map[string]bool{
+	EnvDefaultPlatform: true,
+	"HTTP_PROXY":       true,
+	"http_proxy":       true,
+	"HTTPS_PROXY":      true,
+	"https_proxy":      true,
+	"NO_PROXY":         true,
+	"no_proxy":         true,
+}
+
+// ValidateContextEnv validates the name of an environment variable that's set
+// by a context. Only DOCKER_DEFAULT_PLATFORM and the proxy variables can be
+// set.
+func ValidateContextEnv(name string) error {
+	if !contextEnvAllowed[name] {
+		return fmt.Errorf("invalid environment variable %q: only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context", name)
+	}
+	return nil
+}
+
+// applyContextDefaults applies the configuration and environment variables
+// of the context. The configuration is added as a [configfile.LayerContext]
+// layer, so that it's not written to the user's configuration file, and
+// environment variables are only set if they are not set already.
+func applyContextDefaults(configFile *configfile.ConfigFile, s store.Store, meta store.Metadata) error {
+	dc, err := GetDockerContext(meta)
+	if err != nil {
+		return err
+	}
+	if len(dc.Config) > 0 {
+		data, err := json.Marshal(dc.Config)
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(s.GetStorageInfo(meta.Name).MetadataPath, "meta.json")
+		if err := configFile.AddLayer(configfile.LayerContext, filename, bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("invalid configuration of context %q: %w", meta.Name, err)
+		}
+	}
+	for k, v := range dc.Env {
+		if err := ValidateContextEnv(k); err != nil {
+			logrus.Debugf("context %q: ignoring environment variable: %v", meta.Name, err)
+			continue
+		}
+		if os.Getenv(k) == "" {
+			_ = os.Setenv(k, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/cli/cli/flags"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestValidateContextEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		expectedErr string
+	}{
+		{name: "DOCKER_DEFAULT_PLATFORM"},
+		{name: "HTTPS_PROXY"},
+		{name: "no_proxy"},
+		{name: "PATH", expectedErr: `invalid environment variable "PATH": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "DOCKER_", expectedErr: `invalid environment variable "DOCKER_": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "BUILDX_BUILDER", expectedErr: `invalid environment variable "BUILDX_BUILDER": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "COMPOSE_PROFILES", expectedErr: `invalid environment variable "COMPOSE_PROFILES": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "DOCKER_HOST", expectedErr: `invalid environment variable "DOCKER_HOST": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "DOCKER_CONFIG", expectedErr: `invalid environment variable "DOCKER_CONFIG": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "DOCKER_AUTH_CONFIG", expectedErr: `invalid environment variable "DOCKER_AUTH_CONFIG": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "DOCKER_CLI_PLUGIN_SOCKET", expectedErr: `invalid environment variable "DOCKER_CLI_PLUGIN_SOCKET": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+		{name: "DOCKER_CLI_HOOKS", expectedErr: `invalid environment variable "DOCKER_CLI_HOOKS": only DOCKER_DEFAULT_PLATFORM, HTTP_PROXY, HTTPS_PROXY, and NO_PROXY can be set for a context`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateContextEnv(tc.name)
+			if tc.expectedErr == "" {
+				assert.Check(t, err)
+			} else {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+			}
+		})
+	}
+}
+
+func TestInitializeContextDefaults(t *testing.T) {
+	config.SetDir(t.TempDir())
+	t.Setenv(client.EnvOverrideHost, "")
+	t.Setenv(EnvOverrideContext, "")
+	t.Setenv(EnvDefaultPlatform, "")
+	t.Setenv("HTTPS_PROXY", "http://my-proxy.example.com:3128")
+	t.Setenv("BUILDX_BUILDER", "my-builder")
+	t.Setenv("PATH", os.Getenv("PATH"))
+
+	s := store.New(config.ContextStoreDir(), DefaultContextStoreConfig())
+	assert.NilError(t, s.CreateOrUpdate(store.Metadata{
+		Name: "edge",
+		Metadata: DockerContext{
+			Config: map[string]any{
+				"psFormat": "table {{.ID}}\t{{.Names}}",
+				"proxies":  map[string]any{"default": map[string]any{"httpProxy": "http://edge-proxy.example.com:3128"}},
+			},
+			Env: map[string]string{
+				EnvDefaultPlatform: "linux/arm64",
+				"HTTPS_PROXY":      "http://edge-proxy.example.com:3128",
+				"BUILDX_BUILDER":   "edge-builder",
+				"PATH":             "/invalid",
+			},
+		},
+		Endpoints: map[string]any{docker.DockerEndpoint: docker.EndpointMeta{Host: "tcp://edge.example.com:2376"}},
+	}))
+
+	cli, err := NewDockerCli()
+	assert.NilError(t, err)
+	apiClient, err := client.New()
+	assert.NilError(t, err)
+	opts := flags.NewClientOptions()
+	opts.Context = "edge"
+	assert.NilError(t, cli.Initialize(opts, WithAPIClient(apiClient)))
+
+	assert.Check(t, is.Equal(cli.ConfigFile().PsFormat, "table {{.ID}}\t{{.Names}}"))
+	assert.Check(t, is.Equal(cli.ConfigFile().Proxies["default"].HTTPProxy, "http://edge-proxy.example.com:3128"))
+	assert.Check(t, is.Equal(os.Getenv(EnvDefaultPlatform), "linux/arm64"))
+	assert.Check(t, is.Equal(os.Getenv("HTTPS_PROXY"), "http://my-proxy.example.com:3128"), "environment variables that are set must not be overridden")
+	assert.Check(t, is.Equal(os.Getenv("BUILDX_BUILDER"), "my-builder"), "environment variables that are not permitted must not be set")
+	assert.Check(t, os.Getenv("PATH") != "/invalid")
+
+	// the context's configuration is not saved in the user's configuration.
+	assert.NilError(t, cli.ConfigFile().Save())
+	data, err := os.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	assert.Check(t, !strings.Contains(string(data), "psFormat"))
+}
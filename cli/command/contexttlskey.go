@@ -0,0 +1,119 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/docker/cli/cli/config/configfile"
+	cliconfigcredentials "github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+)
+
+const (
+	// contextTLSKeyServerURL and contextTLSKeyUsername identify the key to
+	// encrypt TLS data of contexts in the credential helper.
+	contextTLSKeyServerURL = cliconfigcredentials.ContextTLSKeyServerAddress
+	contextTLSKeyUsername  = "docker-context"
+	contextTLSKeySize      = 32
+)
+
+// ContextTLSKeyStore is a [store.KeyStore] that holds the key to encrypt
+// the TLS data of contexts at rest in the credential helper that's configured
+// as "contextTLSKeyStore" in the configuration file. TLS data is not
+// encrypted if no credential helper is configured.
+type ContextTLSKeyStore struct {
+	configFile *configfile.ConfigFile
+
+	mu     sync.Mutex
+	helper string
+	key    []byte
+}
+
+// NewContextTLSKeyStore returns a key store that uses the credential helper
+// that's configured in the given configuration file.
+func NewContextTLSKeyStore(configFile *configfile.ConfigFile) *ContextTLSKeyStore {
+	return &ContextTLSKeyStore{configFile: configFile}
+}
+
+var _ store.KeyStore = &ContextTLSKeyStore{}
+
+// GetKey implements [store.KeyStore]. It returns the key from the configured
+// credential helper, or a nil key if no credential helper is configured.
+func (ks *ContextTLSKeyStore) GetKey() ([]byte, error) {
+	helper := ks.configFile.ContextTLSKeyStore
+	if helper == "" {
+		return nil, nil
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key != nil && ks.helper == helper {
+		return ks.key, nil
+	}
+	key, err := getContextTLSKey(helper)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("the key to encrypt TLS data of contexts was not found in credential helper %q", helper)
+	}
+	ks.helper, ks.key = helper, key
+	return key, nil
+}
+
+// CreateKey creates a key in the given credential helper, unless the helper
+// already holds a key. It does not change the configuration file.
+func (ks *ContextTLSKeyStore) CreateKey(helper string) error {
+	key, err := getContextTLSKey(helper)
+	if err != nil || key != nil {
+		return err
+	}
+	key = make([]byte, contextTLSKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	err = client.Store(newContextTLSKeyProgram(helper), &credentials.Credentials{
+		ServerURL: contextTLSKeyServerURL,
+		Username:  contextTLSKeyUsername,
+		Secret:    base64.StdEncoding.EncodeToString(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store the key to encrypt TLS data of contexts in credential helper %q: %w", helper, err)
+	}
+
+	// Read back the key to verify it was stored.
+	stored, err := getContextTLSKey(helper)
+	if err != nil {
+		return err
+	}
+	if string(stored) != string(key) {
+		return fmt.Errorf("failed to store the key to encrypt TLS data of contexts in credential helper %q: stored key does not match", helper)
+	}
+	return nil
+}
+
+// getContextTLSKey returns the key from the credential helper, or nil if the
+// helper does not hold a key.
+func getContextTLSKey(helper string) ([]byte, error) {
+	creds, err := client.Get(newContextTLSKeyProgram(helper), contextTLSKeyServerURL)
+	if err != nil {
+		if credentials.IsErrCredentialsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the key to encrypt TLS data of contexts from credential helper %q: %w", helper, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(creds.Secret)
+	if err != nil || len(key) != contextTLSKeySize {
+		return nil, fmt.Errorf("invalid key to encrypt TLS data of contexts in credential helper %q", helper)
+	}
+	return key, nil
+}
+
+// newContextTLSKeyProgram is a variable for unit testing.
+var newContextTLSKeyProgram = func(helper string) client.ProgramFunc {
+	return client.NewShellProgramFunc("docker-credential-" + helper)
+}
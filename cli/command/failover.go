@@ -0,0 +1,139 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package command
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	dcontext "github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+)
+
+// connectFailover selects the host of an endpoint that has failover hosts,
+// and returns the endpoint for the selected host, and a client to connect
+// to it. If none of the hosts is reachable, it connects to the first host.
+//
+// The selected host is remembered for the next invocation, so that the
+// last healthy host is tried first.
+func (cli *DockerCli) connectFailover(contextName string, ep docker.Endpoint) (docker.Endpoint, client.APIClient, error) {
+	state, err := docker.LoadFailoverState(cli.contextStore, contextName)
+	if err != nil {
+		logrus.WithError(err).Debugf("context %q: failed to load failover state", contextName)
+	}
+
+	hosts := ep.Hosts()
+	var (
+		selected  docker.Endpoint
+		apiClient client.APIClient
+	)
+	if ep.FailoverStrategy == dcontext.FailoverLatency {
+		selected, apiClient = cli.connectLowestLatency(contextName, ep, hosts)
+	} else {
+		if i := slices.Index(hosts, state.Host); i > 0 {
+			hosts = slices.Concat([]string{state.Host}, hosts[:i], hosts[i+1:])
+		}
+		selected, apiClient = cli.connectFirstReachable(contextName, ep, hosts)
+	}
+	if apiClient == nil {
+		logrus.Debugf("context %q: no host is reachable, using host %s", contextName, ep.Host)
+		apiClient, err = newAPIClientFromEndpoint(ep, cli.configFile, cli.clientOpts...)
+		return ep, apiClient, err
+	}
+
+	logrus.Debugf("context %q: using host %s", contextName, selected.Host)
+	if selected.Host != state.Host {
+		err := docker.SaveFailoverState(cli.contextStore, contextName, docker.FailoverState{
+			Host:      selected.Host,
+			CheckedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			logrus.WithError(err).Debugf("context %q: failed to save failover state", contextName)
+		}
+	}
+	return selected, apiClient, nil
+}
+
+// connectFirstReachable returns the endpoint and client of the first host
+// that's reachable, or a nil client if none of the hosts is reachable.
+func (cli *DockerCli) connectFirstReachable(contextName string, ep docker.Endpoint, hosts []string) (docker.Endpoint, client.APIClient) {
+	for _, host := range hosts {
+		hostEP := ep.WithHost(host)
+		apiClient, _, err := cli.pingHost(hostEP)
+		if err != nil {
+			logrus.WithError(err).Debugf("context %q: host %s is not reachable", contextName, host)
+			continue
+		}
+		return hostEP, apiClient
+	}
+	return docker.Endpoint{}, nil
+}
+
+// connectLowestLatency pings all hosts concurrently, and returns the endpoint
+// and client of the reachable host with the lowest latency, or a nil client
+// if none of the hosts is reachable.
+func (cli *DockerCli) connectLowestLatency(contextName string, ep docker.Endpoint, hosts []string) (docker.Endpoint, client.APIClient) {
+	type result struct {
+		ep        docker.Endpoint
+		apiClient client.APIClient
+		latency   time.Duration
+		err       error
+	}
+	results := make([]result, len(hosts))
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := result{ep: ep.WithHost(host)}
+			r.apiClient, r.latency, r.err = cli.pingHost(r.ep)
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+
+	best := -1
+	for i, r := range results {
+		if r.err != nil {
+			logrus.WithError(r.err).Debugf("context %q: host %s is not reachable", contextName, r.ep.Host)
+			continue
+		}
+		logrus.Debugf("context %q: host %s responded in %s", contextName, r.ep.Host, r.latency)
+		if best < 0 || r.latency < results[best].latency {
+			best = i
+		}
+	}
+	for i, r := range results {
+		if i != best && r.apiClient != nil {
+			_ = r.apiClient.Close()
+		}
+	}
+	if best < 0 {
+		return docker.Endpoint{}, nil
+	}
+	return results[best].ep, results[best].apiClient
+}
+
+// pingHost creates a client for the endpoint and pings the daemon. It
+// returns the client and the latency of the ping if the daemon is reachable,
+// and closes the client otherwise.
+func (cli *DockerCli) pingHost(ep docker.Endpoint) (client.APIClient, time.Duration, error) {
+	apiClient, err := newAPIClientFromEndpoint(ep, cli.configFile, cli.clientOpts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ctx, cancel := context.WithTimeout(cli.baseCtx, cli.getInitTimeout())
+	defer cancel()
+
+	start := time.Now()
+	if _, err := apiClient.Ping(ctx, client.PingOptions{}); err != nil {
+		_ = apiClient.Close()
+		return nil, 0, err
+	}
+	return apiClient, time.Since(start), nil
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	dcontext "github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// unreachableHost is a host on which no daemon is listening.
+const unreachableHost = "tcp://127.0.0.1:1"
+
+// newFailoverTestDaemon starts a daemon that responds to pings after the
+// given delay, and returns its host.
+func newFailoverTestDaemon(t *testing.T, delay time.Duration) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Api-Version", "1.52")
+		_, _ = w.Write([]byte("OK"))
+	}))
+	t.Cleanup(srv.Close)
+	return strings.Replace(srv.URL, "http://", "tcp://", 1)
+}
+
+func newFailoverTestCli(t *testing.T, s store.Store, ep docker.EndpointMeta) *DockerCli {
+	t.Helper()
+	assert.NilError(t, s.CreateOrUpdate(store.Metadata{
+		Name:      "failover",
+		Metadata:  DockerContext{},
+		Endpoints: map[string]any{docker.DockerEndpoint: ep},
+	}))
+	return &DockerCli{
+		configFile:     &configfile.ConfigFile{},
+		contextStore:   s,
+		currentContext: "failover",
+		initTimeout:    time.Second,
+	}
+}
+
+func TestInitializeFailover(t *testing.T) {
+	s := store.New(t.TempDir(), DefaultContextStoreConfig())
+	host1 := newFailoverTestDaemon(t, 0)
+	host2 := newFailoverTestDaemon(t, 0)
+
+	// the first reachable host is used, and remembered.
+	cli := newFailoverTestCli(t, s, docker.EndpointMeta{
+		Host:          unreachableHost,
+		FailoverHosts: []string{host1, host2},
+	})
+	assert.NilError(t, cli.initialize())
+	assert.Check(t, is.Equal(cli.DockerEndpoint().Host, host1))
+	assert.Check(t, is.DeepEqual(cli.DockerEndpoint().FailoverHosts, []string{host1, host2}))
+	state, err := docker.LoadFailoverState(s, "failover")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(state.Host, host1))
+	assert.Check(t, !state.CheckedAt.IsZero())
+
+	// the last healthy host is tried first.
+	cli = newFailoverTestCli(t, s, docker.EndpointMeta{
+		Host:          host2,
+		FailoverHosts: []string{host1},
+	})
+	assert.NilError(t, cli.initialize())
+	assert.Check(t, is.Equal(cli.DockerEndpoint().Host, host1))
+}
+
+func TestInitializeFailoverUnreachable(t *testing.T) {
+	s := store.New(t.TempDir(), DefaultContextStoreConfig())
+	cli := newFailoverTestCli(t, s, docker.EndpointMeta{
+		Host:          unreachableHost,
+		FailoverHosts: []string{"tcp://127.0.0.1:2"},
+	})
+	assert.NilError(t, cli.initialize())
+	assert.Check(t, is.Equal(cli.DockerEndpoint().Host, unreachableHost))
+	state, err := docker.LoadFailoverState(s, "failover")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(state.Host, ""), "unreachable hosts must not be remembered")
+}
+
+func TestInitializeFailoverLatency(t *testing.T) {
+	s := store.New(t.TempDir(), DefaultContextStoreConfig())
+	slow := newFailoverTestDaemon(t, 200*time.Millisecond)
+	fast := newFailoverTestDaemon(t, 0)
+
+	cli := newFailoverTestCli(t, s, docker.EndpointMeta{
+		Host:             slow,
+		FailoverHosts:    []string{unreachableHost, fast},
+		FailoverStrategy: dcontext.FailoverLatency,
+	})
+	assert.NilError(t, cli.initialize())
+	assert.Check(t, is.Equal(cli.DockerEndpoint().Host, fast))
+	state, err := docker.LoadFailoverState(s, "failover")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(state.Host, fast))
+}
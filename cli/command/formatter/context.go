@@ -1,5 +1,15 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package formatter
 
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
 const (
 	// ClientContextTableFormat is the default client context format.
 	ClientContextTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.Description}}\t{{.DockerEndpoint}}\t{{.Error}}"
@@ -25,6 +35,7 @@ func NewClientContextFormat(source string, quiet bool) Format {
 type ClientContext struct {
 	Name           string
 	Description    string
+	Labels         map[string]string
 	DockerEndpoint string
 	Current        bool
 	Error          string
@@ -54,6 +65,7 @@ func newClientContextContext() *clientContextContext {
 		"Name":           NameHeader,
 		"Description":    DescriptionHeader,
 		"DockerEndpoint": dockerEndpointHeader,
+		"Labels":         LabelsHeader,
 		"Error":          ErrorHeader,
 	}
 	return &ctx
@@ -79,8 +91,202 @@ func (c *clientContextContext) DockerEndpoint() string {
 	return c.c.DockerEndpoint
 }
 
+// Labels returns the labels of the context as a comma-separated list of
+// "key=value" pairs, sorted by key.
+func (c *clientContextContext) Labels() string {
+	labels := make([]string, 0, len(c.c.Labels))
+	for _, k := range slices.Sorted(maps.Keys(c.c.Labels)) {
+		labels = append(labels, k+"="+c.c.Labels[k])
+	}
+	return strings.Join(labels, ",")
+}
+
+// Label returns the value of the context's label with the given name.
+func (c *clientContextContext) Label(name string) string {
+	return c.c.Labels[name]
+}
+
 // Error returns the truncated error (if any) that occurred when loading the context.
 func (c *clientContextContext) Error() string {
 	// TODO(thaJeztah) add "--no-trunc" option to context ls and set default to 30 cols to match "docker service ps"
 	return Ellipsis(c.c.Error, maxErrLength)
 }
+
+const (
+	// ContextCheckTableFormat is the default format for the results of
+	// context checks.
+	ContextCheckTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.Status}}\t{{.Latency}}\t{{.ServerVersion}}\t{{.APIVersion}}\t{{.Platform}}\t{{.SwarmRole}}\t{{.ClientCertExpiry}}\t{{.Error}}"
+
+	contextStatusHeader    = "STATUS"
+	latencyHeader          = "LATENCY"
+	serverVersionHeader    = "SERVER VERSION"
+	contextAPIHeader       = "API VERSION"
+	contextPlatformHeader  = "OS/ARCH"
+	swarmRoleHeader        = "SWARM"
+	clientCertExpiryHeader = "CLIENT CERT EXPIRY"
+	contextReachable       = "reachable"
+	contextUnreachable     = "unreachable"
+	certExpiryDateFormat   = "2006-01-02"
+	certExpiredAnnotation  = " (expired)"
+	contextCheckNameFormat = "{{.Name}}"
+)
+
+// NewContextCheckFormat returns a Format for rendering the results of
+// context checks.
+func NewContextCheckFormat(source string, quiet bool) Format {
+	if quiet {
+		return contextCheckNameFormat
+	}
+	if source == TableFormatKey {
+		return ContextCheckTableFormat
+	}
+	return Format(source)
+}
+
+// ContextCheck is the result of checking the connection to a context's
+// docker endpoint.
+type ContextCheck struct {
+	Name           string
+	Current        bool
+	DockerEndpoint string
+	Reachable      bool
+	Latency        time.Duration
+	ServerVersion  string
+	APIVersion     string
+	OS             string
+	Arch           string
+	SwarmRole      string
+	// ClientCertExpiry is the expiry time of the context's client
+	// certificate, or the zero value if the context has no client
+	// certificate.
+	ClientCertExpiry time.Time
+	Error            string
+}
+
+// ContextCheckWrite writes the formatted results of context checks using
+// the Context.
+func ContextCheckWrite(ctx Context, checks []*ContextCheck) error {
+	render := func(format func(subContext SubContext) error) error {
+		for _, c := range checks {
+			if err := format(&contextCheckContext{c: c, now: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newContextCheckContext(), render)
+}
+
+type contextCheckContext struct {
+	HeaderContext
+	c   *ContextCheck
+	now time.Time
+}
+
+func newContextCheckContext() *contextCheckContext {
+	ctx := contextCheckContext{}
+	ctx.Header = SubHeaderContext{
+		"Name":             NameHeader,
+		"DockerEndpoint":   dockerEndpointHeader,
+		"Status":           contextStatusHeader,
+		"Latency":          latencyHeader,
+		"ServerVersion":    serverVersionHeader,
+		"APIVersion":       contextAPIHeader,
+		"Platform":         contextPlatformHeader,
+		"SwarmRole":        swarmRoleHeader,
+		"ClientCertExpiry": clientCertExpiryHeader,
+		"Error":            ErrorHeader,
+	}
+	return &ctx
+}
+
+func (c *contextCheckContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+func (c *contextCheckContext) Name() string {
+	return c.c.Name
+}
+
+func (c *contextCheckContext) Current() bool {
+	return c.c.Current
+}
+
+func (c *contextCheckContext) DockerEndpoint() string {
+	return c.c.DockerEndpoint
+}
+
+func (c *contextCheckContext) Reachable() bool {
+	return c.c.Reachable
+}
+
+// Status returns whether the context's docker endpoint is reachable.
+func (c *contextCheckContext) Status() string {
+	if c.c.Reachable {
+		return contextReachable
+	}
+	return contextUnreachable
+}
+
+// Latency returns the round-trip time of a request to the docker endpoint,
+// or an empty string if the endpoint is not reachable.
+func (c *contextCheckContext) Latency() string {
+	if !c.c.Reachable {
+		return ""
+	}
+	if c.c.Latency < time.Millisecond {
+		return c.c.Latency.Round(time.Microsecond).String()
+	}
+	return c.c.Latency.Round(time.Millisecond).String()
+}
+
+func (c *contextCheckContext) ServerVersion() string {
+	return c.c.ServerVersion
+}
+
+func (c *contextCheckContext) APIVersion() string {
+	return c.c.APIVersion
+}
+
+func (c *contextCheckContext) OS() string {
+	return c.c.OS
+}
+
+func (c *contextCheckContext) Arch() string {
+	return c.c.Arch
+}
+
+// Platform returns the operating system and architecture of the daemon in
+// "os/arch" format.
+func (c *contextCheckContext) Platform() string {
+	if c.c.OS == "" {
+		return ""
+	}
+	if c.c.Arch == "" {
+		return c.c.OS
+	}
+	return c.c.OS + "/" + c.c.Arch
+}
+
+func (c *contextCheckContext) SwarmRole() string {
+	return c.c.SwarmRole
+}
+
+// ClientCertExpiry returns the expiry date of the context's client
+// certificate, and whether it has expired.
+func (c *contextCheckContext) ClientCertExpiry() string {
+	if c.c.ClientCertExpiry.IsZero() {
+		return ""
+	}
+	expiry := c.c.ClientCertExpiry.UTC().Format(certExpiryDateFormat)
+	if c.now.After(c.c.ClientCertExpiry) {
+		expiry += certExpiredAnnotation
+	}
+	return expiry
+}
+
+// Error returns the truncated error (if any) that occurred when checking the
+// context.
+func (c *contextCheckContext) Error() string {
+	return Ellipsis(c.c.Error, maxErrLength)
+}
@@ -0,0 +1,32 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestContextCheckFormat(t *testing.T) {
+	out := &strings.Builder{}
+	err := ContextCheckWrite(Context{
+		Output: out,
+		Format: NewContextCheckFormat(TableFormatKey, false),
+	}, []*ContextCheck{
+		{
+			Name: "prod", Current: true, Reachable: true, Latency: 12345 * time.Microsecond,
+			ServerVersion: "29.0.0", APIVersion: "1.52", OS: "linux", Arch: "amd64", SwarmRole: "manager",
+			ClientCertExpiry: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Name: "old", ClientCertExpiry: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Error: "connection refused",
+		},
+	})
+	assert.NilError(t, err)
+	expected := "NAME      STATUS        LATENCY   SERVER VERSION   API VERSION   OS/ARCH       SWARM     CLIENT CERT EXPIRY     ERROR\n" +
+		"prod *    reachable     12ms      29.0.0           1.52          linux/amd64   manager   2030-01-02             \n" +
+		"old       unreachable                                                                    2020-01-02 (expired)   connection refused\n"
+	assert.Check(t, is.Equal(out.String(), expected))
+}
@@ -36,6 +36,11 @@ type DiskUsageContext struct {
 	Context
 	Verbose bool
 
+	// GroupByType and GroupByLabel write the subtotals of the objects,
+	// grouped by their type, and/or by the value of the given label.
+	GroupByType  bool
+	GroupByLabel string
+
 	ImageDiskUsage      client.ImagesDiskUsage
 	BuildCacheDiskUsage client.BuildCacheDiskUsage
 	ContainerDiskUsage  client.ContainersDiskUsage
@@ -83,6 +88,9 @@ func NewDiskUsageFormat(source string, verbose bool) Format {
 }
 
 func (ctx *DiskUsageContext) Write() (err error) {
+	if ctx.GroupByType || ctx.GroupByLabel != "" {
+		return ctx.groupWrite()
+	}
 	if ctx.Verbose {
 		return ctx.verboseWrite()
 	}
@@ -0,0 +1,229 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package formatter
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+)
+
+// diskUsageTypes are the names of the types of objects, in the order in which
+// groups are written.
+var diskUsageTypes = []string{"Images", "Containers", "Local Volumes", "Build Cache"}
+
+const (
+	diskUsageImages = iota
+	diskUsageContainers
+	diskUsageVolumes
+	diskUsageBuildCache
+)
+
+// NewDiskUsageGroupFormat returns a format for rendering the subtotals of a
+// DiskUsageContext that's grouped by type and/or by the given label.
+func NewDiskUsageGroupFormat(source string, byType bool, label string) Format {
+	switch source {
+	case TableFormatKey:
+		var columns []string
+		if label != "" {
+			columns = append(columns, "{{.LabelValue}}")
+		}
+		if byType {
+			columns = append(columns, "{{.Type}}")
+		}
+		columns = append(columns, "{{.TotalCount}}", "{{.Active}}", "{{.Size}}", "{{.Reclaimable}}")
+		return Format("table " + strings.Join(columns, "\t"))
+	case RawFormatKey:
+		var format string
+		if label != "" {
+			format += "label: {{.LabelValue}}\n"
+		}
+		if byType {
+			format += "type: {{.Type}}\n"
+		}
+		format += `total: {{.TotalCount}}
+active: {{.Active}}
+size: {{.Size}}
+reclaimable: {{.Reclaimable}}
+`
+		return Format(format)
+	default:
+		return Format(source)
+	}
+}
+
+type diskUsageGroupKey struct {
+	label     string
+	unlabeled bool
+	typ       int
+}
+
+// groupWrite writes the subtotals of the objects, grouped by type and/or
+// label. Objects that don't have the label are grouped together, and are
+// written after the other groups.
+func (ctx *DiskUsageContext) groupWrite() error {
+	groups := map[diskUsageGroupKey]*diskUsageGroupContext{}
+	add := func(typ int, labels map[string]string, size int64, active bool, reclaimable int64) {
+		key := diskUsageGroupKey{typ: -1}
+		if ctx.GroupByType {
+			key.typ = typ
+		}
+		if ctx.GroupByLabel != "" {
+			v, ok := labels[ctx.GroupByLabel]
+			key.label, key.unlabeled = v, !ok
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = newDiskUsageGroupContext(ctx.GroupByLabel)
+			g.key = key
+			groups[key] = g
+		}
+		g.totalCount++
+		if active {
+			g.activeCount++
+		}
+		g.totalSize += size
+		g.reclaimable += reclaimable
+	}
+
+	for _, i := range ctx.ImageDiskUsage.Items {
+		// Only the unique size of an image can be attributed to a group;
+		// layers that are shared with other images are not counted.
+		var size int64
+		if i.Size != -1 && i.SharedSize != -1 {
+			size = i.Size - i.SharedSize
+		}
+		if i.Containers > 0 {
+			add(diskUsageImages, i.Labels, size, true, 0)
+		} else {
+			add(diskUsageImages, i.Labels, size, false, size)
+		}
+	}
+	for _, c := range ctx.ContainerDiskUsage.Items {
+		switch c.State {
+		case container.StateRunning, container.StatePaused, container.StateRestarting:
+			add(diskUsageContainers, c.Labels, c.SizeRw, true, 0)
+		default:
+			add(diskUsageContainers, c.Labels, c.SizeRw, false, c.SizeRw)
+		}
+	}
+	for _, v := range ctx.VolumeDiskUsage.Items {
+		var size int64
+		var active bool
+		if v.UsageData != nil {
+			size = max(v.UsageData.Size, 0)
+			active = v.UsageData.RefCount > 0
+		}
+		if active {
+			add(diskUsageVolumes, v.Labels, size, true, 0)
+		} else {
+			add(diskUsageVolumes, v.Labels, size, false, size)
+		}
+	}
+	for _, b := range ctx.BuildCacheDiskUsage.Items {
+		// Build cache records don't have labels. Like the totals of the
+		// daemon, the size of shared records is not counted.
+		var size int64
+		if !b.Shared {
+			size = b.Size
+		}
+		if b.InUse {
+			add(diskUsageBuildCache, nil, size, true, 0)
+		} else {
+			add(diskUsageBuildCache, nil, size, false, size)
+		}
+	}
+
+	sorted := slices.SortedFunc(maps.Values(groups), func(a, b *diskUsageGroupContext) int {
+		if a.key.unlabeled != b.key.unlabeled {
+			if a.key.unlabeled {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(strings.Compare(a.key.label, b.key.label), cmp.Compare(a.key.typ, b.key.typ))
+	})
+
+	return ctx.Context.Write(newDiskUsageGroupContext(ctx.GroupByLabel), func(format func(subContext SubContext) error) error {
+		for _, g := range sorted {
+			if err := format(g); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type diskUsageGroupContext struct {
+	HeaderContext
+	key         diskUsageGroupKey
+	labelKey    string
+	totalCount  int64
+	activeCount int64
+	totalSize   int64
+	reclaimable int64
+}
+
+func newDiskUsageGroupContext(labelKey string) *diskUsageGroupContext {
+	return &diskUsageGroupContext{
+		HeaderContext: HeaderContext{
+			Header: SubHeaderContext{
+				"LabelValue":  strings.ToUpper(labelKey),
+				"Type":        typeHeader,
+				"TotalCount":  totalHeader,
+				"Active":      activeHeader,
+				"Size":        SizeHeader,
+				"Reclaimable": reclaimableHeader,
+			},
+		},
+		labelKey: labelKey,
+	}
+}
+
+func (c *diskUsageGroupContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+// LabelValue returns the value of the label of the group, or "<none>" for objects
+// that don't have the label. It's empty if objects are not grouped by label.
+func (c *diskUsageGroupContext) LabelValue() string {
+	if c.labelKey != "" && c.key.unlabeled {
+		return "<none>"
+	}
+	return c.key.label
+}
+
+// Type returns the type of the objects in the group. It's empty if objects
+// are not grouped by type.
+func (c *diskUsageGroupContext) Type() string {
+	if c.key.typ < 0 {
+		return ""
+	}
+	return diskUsageTypes[c.key.typ]
+}
+
+func (c *diskUsageGroupContext) TotalCount() string {
+	return strconv.FormatInt(c.totalCount, 10)
+}
+
+func (c *diskUsageGroupContext) Active() string {
+	return strconv.FormatInt(c.activeCount, 10)
+}
+
+func (c *diskUsageGroupContext) Size() string {
+	return units.HumanSize(float64(c.totalSize))
+}
+
+func (c *diskUsageGroupContext) Reclaimable() string {
+	if c.totalSize > 0 {
+		return fmt.Sprintf("%s (%v%%)", units.HumanSize(float64(c.reclaimable)), (c.reclaimable*100)/c.totalSize)
+	}
+	return units.HumanSize(float64(c.reclaimable))
+}
@@ -4,6 +4,8 @@
 	"bytes"
 	"testing"
 
+	"github.com/moby/moby/api/types/volume"
+	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/golden"
 )
@@ -55,6 +57,33 @@ func TestDiskUsageContextFormatWrite(t *testing.T) {
 			DiskUsageContext{Verbose: true, Context: Context{Format: NewDiskUsageFormat("{{json .}}", true)}},
 			`{"Images":[],"Containers":[],"Volumes":[],"BuildCache":[]}`,
 		},
+		// Grouped by label and type
+		{
+			DiskUsageContext{
+				Context:      Context{Format: NewDiskUsageGroupFormat("table", true, "com.example.team")},
+				GroupByType:  true,
+				GroupByLabel: "com.example.team",
+			},
+			`COM.EXAMPLE.TEAM   TYPE      TOTAL     ACTIVE    SIZE      RECLAIMABLE
+`,
+		},
+		{
+			DiskUsageContext{
+				Context:     Context{Format: NewDiskUsageGroupFormat("raw", true, "")},
+				GroupByType: true,
+				VolumeDiskUsage: client.VolumesDiskUsage{Items: []volume.Volume{
+					{Name: "vol1", UsageData: &volume.UsageData{Size: 2000, RefCount: 1}},
+					{Name: "vol2", UsageData: &volume.UsageData{Size: 500}},
+				}},
+			},
+			`type: Local Volumes
+total: 2
+active: 1
+size: 2.5kB
+reclaimable: 500B (20%)
+
+`,
+		},
 		// Errors
 		{
 			DiskUsageContext{
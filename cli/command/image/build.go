@@ -155,6 +155,7 @@ func newBuildCommand(dockerCLI command.Cli) *cobra.Command {
 
 	flags.StringVar(&options.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"), "Set platform if server is multi-platform capable")
 	flags.SetAnnotation("platform", "version", []string{"1.38"})
+	_ = flags.SetAnnotation("platform", command.FlagAnnotationDefaultPlatform, nil)
 
 	flags.BoolVar(&options.squash, "squash", false, "Squash newly built layers into a single new layer")
 	flags.SetAnnotation("squash", "experimental", nil)
@@ -52,6 +52,7 @@ func newImportCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVarP(&options.message, "message", "m", "", "Set commit message for imported image")
 	flags.StringVar(&options.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"), "Set platform if server is multi-platform capable")
 	_ = flags.SetAnnotation("platform", "version", []string{"1.32"})
+	_ = flags.SetAnnotation("platform", command.FlagAnnotationDefaultPlatform, nil)
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
 
 	return cmd
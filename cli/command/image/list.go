@@ -70,6 +70,7 @@ func newImagesCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.showDigests, "digests", false, "Show digests")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	_ = flags.SetAnnotation("filter", command.FlagAnnotationLabelFilter, nil)
 
 	flags.BoolVar(&options.tree, "tree", false, "List multi-platform images as a tree (EXPERIMENTAL)")
 	flags.SetAnnotation("tree", "version", []string{"1.47"})
@@ -60,6 +60,7 @@ func newPullCommand(dockerCLI command.Cli) *cobra.Command {
 
 	flags.StringVar(&opts.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"), "Set platform if server is multi-platform capable")
 	_ = flags.SetAnnotation("platform", "version", []string{"1.32"})
+	_ = flags.SetAnnotation("platform", command.FlagAnnotationDefaultPlatform, nil)
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
 
 	return cmd
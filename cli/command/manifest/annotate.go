@@ -4,7 +4,6 @@
 package manifest
 
 import (
-	"context"
 	"fmt"
 	"path/filepath"
 	"slices"
@@ -15,7 +14,7 @@
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/manifest/store"
 	"github.com/docker/cli/internal/registryclient"
-	"github.com/moby/moby/api/types/registry"
+	"github.com/docker/cli/opts"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +27,9 @@ type annotateOptions struct {
 	arch       string
 	osFeatures []string
 	osVersion  string
+
+	artifactType string
+	annotations  *opts.MapOpts
 }
 
 // manifestStoreProvider is used in tests to provide a dummy store.
@@ -48,27 +50,6 @@ func newManifestStore(dockerCLI command.Cli) store.Store {
 	return store.NewStore(filepath.Join(config.Dir(), "manifests"))
 }
 
-// authConfigKey is the key used to store credentials for Docker Hub. It is
-// a copy of [registry.IndexServer].
-//
-// [registry.IndexServer]: https://pkg.go.dev/github.com/docker/docker@v28.3.3+incompatible/registry#IndexServer
-const authConfigKey = "https://index.docker.io/v1/"
-
-// getAuthConfigKey special-cases using the full index address of the official
-// index as the AuthConfig key, and uses the (host)name[:port] for private indexes.
-//
-// It is similar to [registry.GetAuthConfigKey], but does not require on
-// [registrytypes.IndexInfo] as intermediate.
-//
-// [registry.GetAuthConfigKey]: https://pkg.go.dev/github.com/docker/docker@v28.3.3+incompatible/registry#GetAuthConfigKey
-// [registrytypes.IndexInfo]: https://pkg.go.dev/github.com/docker/docker@v28.3.3+incompatible/api/types/registry#IndexInfo
-func getAuthConfigKey(domainName string) string {
-	if domainName == "docker.io" || domainName == "index.docker.io" {
-		return authConfigKey
-	}
-	return domainName
-}
-
 // newRegistryClient returns a client for communicating with a Docker distribution
 // registry
 func newRegistryClient(dockerCLI command.Cli, allowInsecure bool) registryclient.RegistryClient {
@@ -76,28 +57,13 @@ func newRegistryClient(dockerCLI command.Cli, allowInsecure bool) registryclient
 		// manifestStoreProvider is used in tests to provide a dummy store.
 		return msp.RegistryClient(allowInsecure)
 	}
-	cfg := dockerCLI.ConfigFile()
-	resolver := func(ctx context.Context, domainName string) registry.AuthConfig {
-		configKey := getAuthConfigKey(domainName)
-		a, _ := cfg.GetAuthConfig(configKey)
-		return registry.AuthConfig{
-			Username:      a.Username,
-			Password:      a.Password,
-			ServerAddress: a.ServerAddress,
-
-			// TODO(thaJeztah): Are these expected to be included?
-			Auth:          a.Auth,
-			IdentityToken: a.IdentityToken,
-			RegistryToken: a.RegistryToken,
-		}
-	}
 	// FIXME(thaJeztah): this should use the userAgent as configured on the dockerCLI.
-	return registryclient.NewRegistryClient(resolver, command.UserAgent(), allowInsecure)
+	return registryclient.NewRegistryClient(command.RegistryAuthConfigResolver(dockerCLI), command.UserAgent(), allowInsecure)
 }
 
 // NewAnnotateCommand creates a new `docker manifest annotate` command
 func newAnnotateCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts annotateOptions
+	opts := annotateOptions{annotations: opts.NewMapOpts(nil, opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
@@ -118,6 +84,8 @@ func newAnnotateCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&opts.osVersion, "os-version", "", "Set operating system version")
 	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
 	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.StringVar(&opts.artifactType, "artifact-type", "", "Set artifact type")
+	flags.Var(opts.annotations, "annotation", "Add an annotation to the image manifest descriptor")
 
 	return cmd
 }
@@ -141,6 +109,16 @@ func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
 		return err
 	}
 
+	if opts.artifactType != "" || len(opts.annotations.GetAll()) > 0 {
+		meta, err := manifestStore.GetMetadata(targetRef)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+		if !meta.OCI {
+			return fmt.Errorf("--artifact-type and --annotation require an OCI image index; use 'docker manifest create --amend --oci' to convert %s", opts.target)
+		}
+	}
+
 	// Update the mf
 	if imageManifest.Descriptor.Platform == nil {
 		imageManifest.Descriptor.Platform = new(ocispec.Platform)
@@ -160,6 +138,15 @@ func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
 	if opts.osVersion != "" {
 		imageManifest.Descriptor.Platform.OSVersion = opts.osVersion
 	}
+	if opts.artifactType != "" {
+		imageManifest.Descriptor.ArtifactType = opts.artifactType
+	}
+	for k, v := range opts.annotations.GetAll() {
+		if imageManifest.Descriptor.Annotations == nil {
+			imageManifest.Descriptor.Annotations = make(map[string]string)
+		}
+		imageManifest.Descriptor.Annotations[k] = v
+	}
 
 	if !isValidOSArch(imageManifest.Descriptor.Platform.OS, imageManifest.Descriptor.Platform.Architecture) {
 		return fmt.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", opts.os, opts.arch)
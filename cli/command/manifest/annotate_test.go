@@ -79,3 +79,37 @@ func TestManifestAnnotate(t *testing.T) {
 	expected := golden.Get(t, "inspect-annotate.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestManifestAnnotateOCI(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	listRef := ref(t, "list:v1")
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(listRef, namedRef, fullImageManifest(t, namedRef)))
+
+	cmd := newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"--annotation", "com.example.key=value", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "--artifact-type and --annotation require an OCI image index")
+
+	assert.NilError(t, manifestStore.SaveMetadata(listRef, store.ListMetadata{OCI: true}))
+	cmd = newAnnotateCommand(cli)
+	cmd.SetArgs([]string{
+		"--artifact-type", "application/vnd.example.artifact",
+		"--annotation", "org.opencontainers.image.title=alpine",
+		"--annotation", "com.example.key=value",
+		"example.com/list:v1", "example.com/alpine:3.0",
+	})
+	assert.NilError(t, cmd.Execute())
+
+	imageManifest, err := manifestStore.Get(listRef, namedRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(imageManifest.Descriptor.ArtifactType, "application/vnd.example.artifact"))
+	assert.Check(t, is.DeepEqual(imageManifest.Descriptor.Annotations, map[string]string{
+		"org.opencontainers.image.title": "alpine",
+		"com.example.key":                "value",
+	}))
+}
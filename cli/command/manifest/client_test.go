@@ -2,19 +2,23 @@
 
 import (
 	"context"
+	"time"
 
 	"github.com/distribution/reference"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/distribution"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type fakeRegistryClient struct {
 	getManifestFunc     func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	getListMetadataFunc func(ctx context.Context, ref reference.Named) (registryclient.ListMetadata, error)
 	mountBlobFunc       func(ctx context.Context, source reference.Canonical, target reference.Named) error
 	putManifestFunc     func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
+	getReferrersFunc    func(ctx context.Context, ref reference.Named, artifactType string) ([]ocispec.Descriptor, error)
 }
 
 func (c *fakeRegistryClient) GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
@@ -31,6 +35,18 @@ func (c *fakeRegistryClient) GetManifestList(ctx context.Context, ref reference.
 	return nil, nil
 }
 
+func (c *fakeRegistryClient) GetManifestListWithMetadata(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, registryclient.ListMetadata, error) {
+	list, err := c.GetManifestList(ctx, ref)
+	if err != nil {
+		return nil, registryclient.ListMetadata{}, err
+	}
+	var meta registryclient.ListMetadata
+	if c.getListMetadataFunc != nil {
+		meta, err = c.getListMetadataFunc(ctx, ref)
+	}
+	return list, meta, err
+}
+
 func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
 	if c.mountBlobFunc != nil {
 		return c.mountBlobFunc(ctx, source, target)
@@ -45,4 +61,31 @@ func (c *fakeRegistryClient) PutManifest(ctx context.Context, ref reference.Name
 	return digest.Digest(""), nil
 }
 
+func (c *fakeRegistryClient) GetReferrers(ctx context.Context, ref reference.Named, artifactType string) ([]ocispec.Descriptor, error) {
+	if c.getReferrersFunc != nil {
+		return c.getReferrersFunc(ctx, ref, artifactType)
+	}
+	return nil, nil
+}
+
+func (*fakeRegistryClient) ListTags(context.Context, reference.Named) ([]string, error) {
+	return nil, nil
+}
+
+func (*fakeRegistryClient) ListRepositories(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func (*fakeRegistryClient) GetManifestDigest(context.Context, reference.Named) (digest.Digest, error) {
+	return "", nil
+}
+
+func (*fakeRegistryClient) GetImageCreated(context.Context, reference.Named) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (*fakeRegistryClient) DeleteManifest(context.Context, reference.Canonical) error {
+	return nil
+}
+
 var _ registryclient.RegistryClient = &fakeRegistryClient{}
@@ -34,6 +34,8 @@ func newManifestCommand(dockerCLI command.Cli) *cobra.Command {
 		newAnnotateCommand(dockerCLI),
 		newPushListCommand(dockerCLI),
 		newRmManifestListCommand(dockerCLI),
+		newListCommand(dockerCLI),
+		newReferrersCommand(dockerCLI),
 	)
 	return cmd
 }
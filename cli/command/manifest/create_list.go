@@ -8,22 +8,28 @@
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
 	"github.com/spf13/cobra"
 )
 
 type createOpts struct {
-	amend    bool
-	insecure bool
+	amend        bool
+	insecure     bool
+	oci          bool
+	ociChanged   bool
+	artifactType string
+	annotations  *opts.MapOpts
 }
 
 func newCreateListCommand(dockerCLI command.Cli) *cobra.Command {
-	opts := createOpts{}
+	opts := createOpts{annotations: opts.NewMapOpts(nil, opts.ValidateLabel)}
 
 	cmd := &cobra.Command{
 		Use:   "create MANIFEST_LIST MANIFEST [MANIFEST...]",
 		Short: "Create a local manifest list for annotating and pushing to a registry",
 		Args:  cli.RequiresMinArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ociChanged = cmd.Flags().Changed("oci")
 			return createManifestList(cmd.Context(), dockerCLI, args, opts)
 		},
 		DisableFlagsInUseLine: true,
@@ -32,6 +38,9 @@ func newCreateListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
 	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.oci, "oci", false, "Create an OCI image index instead of a Docker manifest list")
+	flags.StringVar(&opts.artifactType, "artifact-type", "", "Set the artifact type of the OCI image index")
+	flags.Var(opts.annotations, "annotation", "Add an annotation to the OCI image index")
 	return cmd
 }
 
@@ -53,6 +62,30 @@ func createManifestList(ctx context.Context, dockerCLI command.Cli, args []strin
 		return errors.New("refusing to amend an existing manifest list with no --amend flag")
 	}
 
+	meta, err := manifestStore.GetMetadata(targetRef)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+	// An amended list keeps its format, unless --oci or --oci=false is set.
+	if opts.ociChanged {
+		meta.OCI = opts.oci
+	}
+	if !meta.OCI && (opts.artifactType != "" || len(opts.annotations.GetAll()) > 0) {
+		return errors.New("--artifact-type and --annotation require an OCI image index (--oci)")
+	}
+	if !meta.OCI && (meta.ArtifactType != "" || len(meta.Annotations) > 0) {
+		return fmt.Errorf("manifest list %s has an artifact type or annotations, which require an OCI image index (--oci)", targetRef)
+	}
+	if opts.artifactType != "" {
+		meta.ArtifactType = opts.artifactType
+	}
+	for k, v := range opts.annotations.GetAll() {
+		if meta.Annotations == nil {
+			meta.Annotations = make(map[string]string)
+		}
+		meta.Annotations[k] = v
+	}
+
 	// Now create the local manifest list transaction by looking up the manifest schemas
 	// for the constituent images:
 	manifests := args[1:]
@@ -71,6 +104,9 @@ func createManifestList(ctx context.Context, dockerCLI command.Cli, args []strin
 			return err
 		}
 	}
+	if err := manifestStore.SaveMetadata(targetRef, meta); err != nil {
+		return err
+	}
 	_, _ = fmt.Fprintln(dockerCLI.Out(), "Created manifest list", targetRef.String())
 	return nil
 }
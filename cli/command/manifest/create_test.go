@@ -117,3 +117,92 @@ func TestManifestCreateNoManifest(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err, "No such image: example.com/alpine:3.0")
 }
+
+// create an OCI image index with annotations, and inspect it
+func TestManifestCreateOCIIndex(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return fullImageManifest(t, ref), nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{
+		"--oci",
+		"--artifact-type", "application/vnd.example.artifact",
+		"--annotation", "org.opencontainers.image.source=https://github.com/docker/cli",
+		"--annotation", "org.opencontainers.image.version=1.0",
+		"example.com/list:v1", "example.com/alpine:3.0",
+	})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	meta, err := manifestStore.GetMetadata(ref(t, "list:v1"))
+	assert.NilError(t, err)
+	assert.Check(t, meta.OCI)
+	assert.Check(t, is.Equal(meta.ArtifactType, "application/vnd.example.artifact"))
+	assert.Check(t, is.Len(meta.Annotations, 2))
+
+	// make a new cli to clear the buffers
+	cli = test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	inspectCmd := newInspectCommand(cli)
+	inspectCmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, inspectCmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-oci-index.golden")
+}
+
+// attempt to annotate a Docker manifest list
+func TestManifestCreateAnnotationWithoutOCI(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(ref(t, "list:v1"), namedRef, fullImageManifest(t, namedRef)))
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "--annotation", "foo=bar", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Error(t, cmd.Execute(), "--artifact-type and --annotation require an OCI image index (--oci)")
+}
+
+// switch an OCI image index back to a Docker manifest list
+func TestManifestCreateAmendToDockerList(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	listRef := ref(t, "list:v1")
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(listRef, namedRef, fullImageManifest(t, namedRef)))
+	assert.NilError(t, manifestStore.SaveMetadata(listRef, store.ListMetadata{OCI: true}))
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	meta, err := manifestStore.GetMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, meta.OCI, "amending should keep the format of the list")
+
+	cmd = newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "--oci=false", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	meta, err = manifestStore.GetMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, !meta.OCI)
+
+	assert.NilError(t, manifestStore.SaveMetadata(listRef, store.ListMetadata{OCI: true, Annotations: map[string]string{"foo": "bar"}}))
+	cmd = newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "--oci=false", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "has an artifact type or annotations, which require an OCI image index")
+}
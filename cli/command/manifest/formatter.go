@@ -0,0 +1,226 @@
+package manifest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	defaultManifestListTableFormat = "table {{.Name}}\t{{.Manifests}}\t{{.Platforms}}\t{{.CreatedSince}}"
+
+	manifestsHeader   = "MANIFESTS"
+	entriesHeader     = "ENTRIES"
+	platformsHeader   = "PLATFORMS"
+	annotationsHeader = "ANNOTATIONS"
+)
+
+// newListFormat returns a Format for rendering using a manifestListContext.
+func newListFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return `{{.Name}}`
+		}
+		return defaultManifestListTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}
+manifests: {{.Manifests}}
+platforms: {{.Platforms}}
+created_at: {{.CreatedAt}}
+`
+	}
+	return formatter.Format(source)
+}
+
+// formatListWrite writes the context
+func formatListWrite(fmtCtx formatter.Context, lists []store.ManifestList) error {
+	listCtx := &manifestListContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Name":         formatter.NameHeader,
+				"Manifests":    manifestsHeader,
+				"Entries":      entriesHeader,
+				"Platforms":    platformsHeader,
+				"Annotations":  annotationsHeader,
+				"CreatedSince": formatter.CreatedSinceHeader,
+				"CreatedAt":    formatter.CreatedAtHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(listCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, l := range lists {
+			if err := format(&manifestListContext{l: l}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type manifestListContext struct {
+	formatter.HeaderContext
+	l store.ManifestList
+}
+
+func (c *manifestListContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *manifestListContext) Name() string {
+	return listName(c.l)
+}
+
+// Manifests returns the number of manifests in the list.
+func (c *manifestListContext) Manifests() int {
+	return len(c.l.Manifests)
+}
+
+// Entries returns the references of the manifests in the list.
+func (c *manifestListContext) Entries() string {
+	entries := make([]string, 0, len(c.l.Manifests))
+	for _, m := range c.l.Manifests {
+		if m.Ref != nil {
+			entries = append(entries, m.Ref.String())
+		}
+	}
+	return strings.Join(entries, ", ")
+}
+
+// Platforms returns the platforms of the manifests in the list.
+func (c *manifestListContext) Platforms() string {
+	pfs := make([]string, 0, len(c.l.Manifests))
+	for _, m := range c.l.Manifests {
+		if m.Descriptor.Platform != nil {
+			pfs = append(pfs, platforms.FormatAll(*m.Descriptor.Platform))
+		}
+	}
+	return strings.Join(pfs, ", ")
+}
+
+// Annotations returns the annotations of the manifests in the list.
+func (c *manifestListContext) Annotations() string {
+	var annotations []string
+	for _, m := range c.l.Manifests {
+		for k, v := range m.Descriptor.Annotations {
+			annotations = append(annotations, k+"="+v)
+		}
+	}
+	sort.Strings(annotations)
+	return strings.Join(annotations, ", ")
+}
+
+func (c *manifestListContext) CreatedSince() string {
+	if c.l.Created.IsZero() {
+		return ""
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(c.l.Created)) + " ago"
+}
+
+func (c *manifestListContext) CreatedAt() string {
+	return c.l.Created.String()
+}
+
+// listName returns the reference of the manifest list, or the name of its
+// directory in local storage if the reference was not recorded.
+func listName(l store.ManifestList) string {
+	if l.Ref != "" {
+		return l.Ref
+	}
+	return l.Name
+}
+
+const (
+	defaultReferrerTableFormat = "table {{.ArtifactType}}\t{{.Digest}}\t{{.Size}}\t{{.Annotations}}"
+
+	artifactTypeHeader = "ARTIFACT TYPE"
+	digestHeader       = "DIGEST"
+	mediaTypeHeader    = "MEDIA TYPE"
+)
+
+// newReferrerFormat returns a Format for rendering using a referrerContext.
+func newReferrerFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultReferrerTableFormat
+	case formatter.RawFormatKey:
+		return `artifact_type: {{.ArtifactType}}
+digest: {{.Digest}}
+size: {{.Size}}
+annotations: {{.Annotations}}
+`
+	}
+	return formatter.Format(source)
+}
+
+// formatReferrersWrite writes the context
+func formatReferrersWrite(fmtCtx formatter.Context, referrers []ocispec.Descriptor) error {
+	refCtx := &referrerContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"ArtifactType": artifactTypeHeader,
+				"MediaType":    mediaTypeHeader,
+				"Digest":       digestHeader,
+				"Size":         formatter.SizeHeader,
+				"Annotations":  annotationsHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(refCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, r := range referrers {
+			if err := format(&referrerContext{d: r}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type referrerContext struct {
+	formatter.HeaderContext
+	d ocispec.Descriptor
+}
+
+func (c *referrerContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *referrerContext) ArtifactType() string {
+	return c.d.ArtifactType
+}
+
+func (c *referrerContext) MediaType() string {
+	return c.d.MediaType
+}
+
+func (c *referrerContext) Digest() string {
+	return c.d.Digest.String()
+}
+
+func (c *referrerContext) Size() string {
+	return units.HumanSizeWithPrecision(float64(c.d.Size), 3)
+}
+
+func (c *referrerContext) Annotations() string {
+	return joinAnnotations(c.d.Annotations)
+}
+
+// joinAnnotations returns the annotations as a sorted, comma-separated list
+// of key=value pairs.
+func joinAnnotations(annotations map[string]string) string {
+	joined := make([]string, 0, len(annotations))
+	for k, v := range annotations {
+		joined = append(joined, k+"="+v)
+	}
+	sort.Strings(joined)
+	return strings.Join(joined, ", ")
+}
@@ -9,6 +9,7 @@
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/manifest/store"
 	"github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/spf13/cobra"
@@ -69,8 +70,16 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	}
 
 	// Try a local manifest list first
-	localManifestList, err := newManifestStore(dockerCli).GetList(namedRef)
+	manifestStore := newManifestStore(dockerCli)
+	localManifestList, err := manifestStore.GetList(namedRef)
 	if err == nil {
+		meta, err := manifestStore.GetMetadata(namedRef)
+		if err != nil {
+			return err
+		}
+		if meta.OCI && !opts.verbose {
+			return printOCIIndex(dockerCli, namedRef, localManifestList, meta)
+		}
 		return printManifestList(dockerCli, namedRef, localManifestList, opts)
 	}
 
@@ -82,10 +91,18 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	}
 
 	// Finally try a remote manifest list
-	manifestList, err := registryClient.GetManifestList(ctx, namedRef)
+	manifestList, meta, err := registryClient.GetManifestListWithMetadata(ctx, namedRef)
 	if err != nil {
 		return err
 	}
+	if meta.OCI && !opts.verbose {
+		return printOCIIndex(dockerCli, namedRef, manifestList, store.ListMetadata{
+			Ref:          namedRef.String(),
+			OCI:          true,
+			ArtifactType: meta.ArtifactType,
+			Annotations:  meta.Annotations,
+		})
+	}
 	return printManifestList(dockerCli, namedRef, manifestList, opts)
 }
 
@@ -141,3 +158,12 @@ func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []t
 	_, _ = dockerCli.Out().Write(append(jsonBytes, '\n'))
 	return nil
 }
+
+func printOCIIndex(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, meta store.ListMetadata) error {
+	idx, err := buildOCIIndex(list, meta, namedRef)
+	if err != nil {
+		return fmt.Errorf("failed to assemble OCI image index: %w", err)
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), string(idx.canonical))
+	return nil
+}
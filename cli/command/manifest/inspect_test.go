@@ -9,6 +9,7 @@
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/manifest/store"
 	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema2"
@@ -135,3 +136,34 @@ func TestInspectcommandRemoteManifest(t *testing.T) {
 	expected := golden.Get(t, "inspect-manifest.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestInspectCommandRemoteOCIIndex(t *testing.T) {
+	refStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(refStore)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (types.ImageManifest, error) {
+			return types.ImageManifest{}, errors.New(ref.String() + " is a manifest list")
+		},
+		getManifestListFunc: func(_ context.Context, _ reference.Named) ([]types.ImageManifest, error) {
+			imageManifest := fullImageManifest(t, ref(t, "alpine:3.0"))
+			imageManifest.Descriptor.ArtifactType = "application/vnd.example.artifact"
+			imageManifest.Descriptor.Annotations = map[string]string{"org.opencontainers.image.title": "alpine"}
+			return []types.ImageManifest{imageManifest}, nil
+		},
+		getListMetadataFunc: func(_ context.Context, _ reference.Named) (registryclient.ListMetadata, error) {
+			return registryclient.ListMetadata{
+				OCI:          true,
+				ArtifactType: "application/vnd.example.artifact",
+				Annotations:  map[string]string{"org.opencontainers.image.version": "1.0"},
+			}, nil
+		},
+	})
+
+	cmd := newInspectCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-remote-oci-index.golden")
+}
@@ -0,0 +1,57 @@
+package manifest
+
+import (
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/fvbommel/sortorder"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet  bool
+	format string
+}
+
+func newListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List manifest lists in local storage",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display manifest list names")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runList(dockerCLI command.Cli, opts listOptions) error {
+	lists, err := newManifestStore(dockerCLI).List()
+	if err != nil {
+		return err
+	}
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
+
+	sort.Slice(lists, func(i, j int) bool {
+		return sortorder.NaturalLess(listName(lists[i]), listName(lists[j]))
+	})
+	listCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newListFormat(opts.format, opts.quiet),
+	}
+	return formatListWrite(listCtx, lists)
+}
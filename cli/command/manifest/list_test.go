@@ -0,0 +1,69 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestListManifestLists(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	list1 := ref(t, "first:1")
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(list1, namedRef, fullImageManifest(t, namedRef)))
+	namedRef = ref(t, "alpine:3.1")
+	imageManifest := fullImageManifest(t, namedRef)
+	imageManifest.Descriptor.Platform.Architecture = "arm64"
+	imageManifest.Descriptor.Platform.Variant = "v8"
+	imageManifest.Descriptor.Annotations = map[string]string{"org.opencontainers.image.version": "3.1"}
+	assert.NilError(t, manifestStore.Save(list1, namedRef, imageManifest))
+
+	list2 := ref(t, "second:2")
+	namedRef = ref(t, "alpine:3.2")
+	assert.NilError(t, manifestStore.Save(list2, namedRef, fullImageManifest(t, namedRef)))
+
+	testCases := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "table",
+			args:   []string{"--format", "table {{.Name}}\t{{.Manifests}}\t{{.Platforms}}"},
+			golden: "list.golden",
+		},
+		{
+			name:   "quiet",
+			args:   []string{"--quiet"},
+			golden: "list-quiet.golden",
+		},
+		{
+			name:   "format",
+			args:   []string{"--format", "{{.Name}}: {{.Entries}} ({{.Annotations}})"},
+			golden: "list-format.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetManifestStore(manifestStore)
+			cmd := newListCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestListManifestListsEmpty(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store.NewStore(t.TempDir()))
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Equal(t, cli.OutBuffer().String(), "NAME      MANIFESTS   PLATFORMS   CREATED\n")
+}
@@ -9,12 +9,15 @@
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/manifest/store"
 	"github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -36,7 +39,7 @@ type manifestBlob struct {
 
 type pushRequest struct {
 	targetRef     reference.Named
-	list          *manifestlist.DeserializedManifestList
+	list          distribution.Manifest
 	mountRequests []mountRequest
 	manifestBlobs []manifestBlob
 	insecure      bool
@@ -68,15 +71,20 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOpts) error {
 		return err
 	}
 
-	manifests, err := newManifestStore(dockerCli).GetList(targetRef)
+	manifestStore := newManifestStore(dockerCli)
+	manifests, err := manifestStore.GetList(targetRef)
 	if err != nil {
 		return err
 	}
 	if len(manifests) == 0 {
 		return fmt.Errorf("%s not found", targetRef)
 	}
+	meta, err := manifestStore.GetMetadata(targetRef)
+	if err != nil {
+		return err
+	}
 
-	req, err := buildPushRequest(manifests, targetRef, opts.insecure)
+	req, err := buildPushRequest(manifests, meta, targetRef, opts.insecure)
 	if err != nil {
 		return err
 	}
@@ -90,11 +98,15 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOpts) error {
 	return nil
 }
 
-func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named, insecure bool) (pushRequest, error) {
+func buildPushRequest(manifests []types.ImageManifest, meta store.ListMetadata, targetRef reference.Named, insecure bool) (pushRequest, error) {
 	req := pushRequest{targetRef: targetRef, insecure: insecure}
 
 	var err error
-	req.list, err = buildManifestList(manifests, targetRef)
+	if meta.OCI {
+		req.list, err = buildOCIIndex(manifests, meta, targetRef)
+	} else {
+		req.list, err = buildManifestList(manifests, targetRef)
+	}
 	if err != nil {
 		return req, err
 	}
@@ -130,6 +142,12 @@ func buildManifestList(manifests []types.ImageManifest, targetRef reference.Name
 			imageManifest.Descriptor.Platform.OS == "" {
 			return nil, fmt.Errorf("manifest %s must have an OS and Architecture to be pushed to a registry", imageManifest.Ref)
 		}
+		if imageManifest.Descriptor.ArtifactType != "" {
+			return nil, fmt.Errorf("manifest %s has an artifact type, which requires an OCI image index (--oci)", imageManifest.Ref)
+		}
+		if len(imageManifest.Descriptor.Annotations) > 0 {
+			return nil, fmt.Errorf("manifest %s has annotations, which require an OCI image index (--oci)", imageManifest.Ref)
+		}
 		descriptor, err := buildManifestDescriptor(targetRepo, imageManifest)
 		if err != nil {
 			return nil, err
@@ -149,9 +167,10 @@ func buildManifestDescriptor(targetRepo reference.Named, imageManifest types.Ima
 
 	manifest := manifestlist.ManifestDescriptor{
 		Descriptor: distribution.Descriptor{
-			Digest:    imageManifest.Descriptor.Digest,
-			Size:      imageManifest.Descriptor.Size,
-			MediaType: imageManifest.Descriptor.MediaType,
+			Digest:      imageManifest.Descriptor.Digest,
+			Size:        imageManifest.Descriptor.Size,
+			MediaType:   imageManifest.Descriptor.MediaType,
+			Annotations: imageManifest.Descriptor.Annotations,
 		},
 	}
 
@@ -167,6 +186,79 @@ func buildManifestDescriptor(targetRepo reference.Named, imageManifest types.Ima
 	return manifest, nil
 }
 
+// ociIndex is an OCI image index that can be pushed to a registry.
+type ociIndex struct {
+	ocispec.Index
+
+	// canonical is the canonical byte representation of the index.
+	canonical []byte
+}
+
+// References returns the descriptors of the manifests in the index.
+func (i *ociIndex) References() []distribution.Descriptor {
+	refs := make([]distribution.Descriptor, 0, len(i.Manifests))
+	for _, m := range i.Manifests {
+		refs = append(refs, distribution.Descriptor{
+			MediaType:   m.MediaType,
+			Size:        m.Size,
+			Digest:      m.Digest,
+			URLs:        m.URLs,
+			Annotations: m.Annotations,
+			Platform:    m.Platform,
+		})
+	}
+	return refs
+}
+
+// Payload returns the media type and the canonical content of the index.
+func (i *ociIndex) Payload() (string, []byte, error) {
+	return ocispec.MediaTypeImageIndex, i.canonical, nil
+}
+
+// MarshalJSON returns the canonical content of the index.
+func (i *ociIndex) MarshalJSON() ([]byte, error) {
+	return i.canonical, nil
+}
+
+func buildOCIIndex(manifests []types.ImageManifest, meta store.ListMetadata, targetRef reference.Named) (*ociIndex, error) {
+	targetRepoHostname := reference.Domain(reference.TrimNamed(targetRef))
+	descriptors := make([]ocispec.Descriptor, 0, len(manifests))
+	for _, imageManifest := range manifests {
+		if manifestRepoHostname := reference.Domain(reference.TrimNamed(imageManifest.Ref)); manifestRepoHostname != targetRepoHostname {
+			return nil, fmt.Errorf("cannot use source images from a different registry than the target image: %s != %s", manifestRepoHostname, targetRepoHostname)
+		}
+		if err := imageManifest.Descriptor.Digest.Validate(); err != nil {
+			return nil, fmt.Errorf("digest parse of image %q failed: %w", imageManifest.Ref, err)
+		}
+		descriptor := ocispec.Descriptor{
+			MediaType:    imageManifest.Descriptor.MediaType,
+			Digest:       imageManifest.Descriptor.Digest,
+			Size:         imageManifest.Descriptor.Size,
+			ArtifactType: imageManifest.Descriptor.ArtifactType,
+			Annotations:  imageManifest.Descriptor.Annotations,
+		}
+		// Artifacts don't require a platform, which is optional in an OCI
+		// image index, so only include it if it's set.
+		if p := imageManifest.Descriptor.Platform; p != nil && (p.OS != "" || p.Architecture != "") {
+			descriptor.Platform = p
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+
+	idx := ocispec.Index{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageIndex,
+		ArtifactType: meta.ArtifactType,
+		Manifests:    descriptors,
+		Annotations:  meta.Annotations,
+	}
+	canonical, err := json.MarshalIndent(idx, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	return &ociIndex{Index: idx, canonical: canonical}, nil
+}
+
 func buildBlobRequestList(imageManifest types.ImageManifest, repoName reference.Named) ([]manifestBlob, error) {
 	blobs := imageManifest.Blobs()
 	blobReqs := make([]manifestBlob, 0, len(blobs))
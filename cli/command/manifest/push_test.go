@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"testing"
@@ -10,7 +11,11 @@
 	"github.com/docker/cli/cli/manifest/store"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func newFakeRegistryClient() *fakeRegistryClient {
@@ -68,3 +73,87 @@ func TestManifestPush(t *testing.T) {
 	err = cmd.Execute()
 	assert.NilError(t, err)
 }
+
+func TestManifestPushOCIIndex(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	var pushed distribution.Manifest
+	registry := newFakeRegistryClient()
+	registry.putManifestFunc = func(_ context.Context, _ reference.Named, mf distribution.Manifest) (digest.Digest, error) {
+		pushed = mf
+		return "", nil
+	}
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetRegistryClient(registry)
+
+	listRef := ref(t, "list:v1")
+	namedRef := ref(t, "alpine:3.0")
+	imageManifest := fullImageManifest(t, namedRef)
+	imageManifest.Descriptor.Annotations = map[string]string{"com.example.key": "value"}
+	assert.NilError(t, manifestStore.Save(listRef, namedRef, imageManifest))
+	assert.NilError(t, manifestStore.SaveMetadata(listRef, store.ListMetadata{
+		OCI:         true,
+		Annotations: map[string]string{"org.opencontainers.image.version": "1.0"},
+	}))
+
+	cmd := newPushListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Assert(t, pushed != nil)
+	mediaType, payload, err := pushed.Payload()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(mediaType, ocispec.MediaTypeImageIndex))
+
+	var idx ocispec.Index
+	assert.NilError(t, json.Unmarshal(payload, &idx))
+	assert.Check(t, is.Equal(idx.MediaType, ocispec.MediaTypeImageIndex))
+	assert.Check(t, is.DeepEqual(idx.Annotations, map[string]string{"org.opencontainers.image.version": "1.0"}))
+	assert.Assert(t, is.Len(idx.Manifests, 1))
+	assert.Check(t, is.DeepEqual(idx.Manifests[0].Annotations, map[string]string{"com.example.key": "value"}))
+	assert.Check(t, is.Equal(idx.Manifests[0].Platform.Architecture, "amd64"))
+}
+
+func TestManifestPushDescriptorWithoutOCI(t *testing.T) {
+	for _, tc := range []struct {
+		doc         string
+		modify      func(*manifesttypes.ImageManifest)
+		expectedErr string
+	}{
+		{
+			doc: "artifact type",
+			modify: func(m *manifesttypes.ImageManifest) {
+				m.Descriptor.ArtifactType = "application/vnd.example.artifact"
+			},
+			expectedErr: "has an artifact type, which requires an OCI image index (--oci)",
+		},
+		{
+			doc: "annotations",
+			modify: func(m *manifesttypes.ImageManifest) {
+				m.Descriptor.Annotations = map[string]string{"com.example.key": "value"}
+			},
+			expectedErr: "has annotations, which require an OCI image index (--oci)",
+		},
+	} {
+		t.Run(tc.doc, func(t *testing.T) {
+			manifestStore := store.NewStore(t.TempDir())
+
+			cli := test.NewFakeCli(nil)
+			cli.SetManifestStore(manifestStore)
+			cli.SetRegistryClient(newFakeRegistryClient())
+
+			namedRef := ref(t, "alpine:3.0")
+			imageManifest := fullImageManifest(t, namedRef)
+			tc.modify(&imageManifest)
+			assert.NilError(t, manifestStore.Save(ref(t, "list:v1"), namedRef, imageManifest))
+
+			cmd := newPushListCommand(cli)
+			cmd.SetArgs([]string{"example.com/list:v1"})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package manifest
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type referrersOptions struct {
+	ref          string
+	artifactType string
+	format       string
+	insecure     bool
+}
+
+func newReferrersCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts referrersOptions
+
+	cmd := &cobra.Command{
+		Use:   "referrers [OPTIONS] IMAGE",
+		Short: "List the artifacts that refer to an image, such as signatures and SBOMs",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ref = args[0]
+			return runReferrers(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.artifactType, "artifact-type", "", "Only show referrers with the given artifact type")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runReferrers(ctx context.Context, dockerCLI command.Cli, opts referrersOptions) error {
+	namedRef, err := normalizeReference(opts.ref)
+	if err != nil {
+		return err
+	}
+
+	referrers, err := newRegistryClient(dockerCLI, opts.insecure).GetReferrers(ctx, namedRef, opts.artifactType)
+	if err != nil {
+		return err
+	}
+
+	if opts.format == "" {
+		opts.format = formatter.TableFormatKey
+	}
+	refCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newReferrerFormat(opts.format),
+	}
+	return formatReferrersWrite(refCtx, referrers)
+}
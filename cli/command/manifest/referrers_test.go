@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+var testReferrers = []ocispec.Descriptor{
+	{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/spdx+json",
+		Digest:       "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+		Size:         1024,
+		Annotations: map[string]string{
+			"org.opencontainers.image.created": "2025-01-01T00:00:00Z",
+			"com.example.tool":                 "syft",
+		},
+	},
+	{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json",
+		Digest:       "sha256:3c5f5e9a4e1b2c1c0f7a4f0e1a5e6bd1c33f4ab2d5f1f9c6f1a1e3a2b4c5d6e7",
+		Size:         512,
+	},
+}
+
+func TestManifestReferrers(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "table",
+			args:   []string{"example.com/alpine:3.0"},
+			golden: "referrers.golden",
+		},
+		{
+			name:   "json",
+			args:   []string{"--format", "json", "example.com/alpine:3.0"},
+			golden: "referrers-json.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{
+				getReferrersFunc: func(_ context.Context, ref reference.Named, artifactType string) ([]ocispec.Descriptor, error) {
+					assert.Check(t, is.Equal(ref.String(), "example.com/alpine:3.0"))
+					assert.Check(t, is.Equal(artifactType, ""))
+					return testReferrers, nil
+				},
+			})
+			cmd := newReferrersCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestManifestReferrersArtifactType(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getReferrersFunc: func(_ context.Context, _ reference.Named, artifactType string) ([]ocispec.Descriptor, error) {
+			assert.Check(t, is.Equal(artifactType, "application/spdx+json"))
+			return testReferrers[:1], nil
+		},
+	})
+	cmd := newReferrersCommand(cli)
+	cmd.SetArgs([]string{"--artifact-type", "application/spdx+json", "--format", "{{.Digest}}", "example.com/alpine:3.0"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270\n"))
+}
+
+func TestManifestReferrersError(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getReferrersFunc: func(context.Context, reference.Named, string) ([]ocispec.Descriptor, error) {
+			return nil, errors.New("something went wrong")
+		},
+	})
+	cmd := newReferrersCommand(cli)
+	cmd.SetArgs([]string{"example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Error(t, cmd.Execute(), "something went wrong")
+}
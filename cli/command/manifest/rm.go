@@ -10,17 +10,36 @@
 	"github.com/spf13/cobra"
 )
 
+type rmOptions struct {
+	all bool
+}
+
 func newRmManifestListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts rmOptions
+
 	cmd := &cobra.Command{
-		Use:   "rm MANIFEST_LIST [MANIFEST_LIST...]",
+		Use:   "rm [OPTIONS] MANIFEST_LIST [MANIFEST_LIST...]",
 		Short: "Delete one or more manifest lists from local storage",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				if len(args) > 0 {
+					return errors.New("conflicting options: cannot specify manifest lists with --all")
+				}
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				return newManifestStore(dockerCLI).RemoveAll()
+			}
 			return runRemove(cmd.Context(), newManifestStore(dockerCLI), args)
 		},
 		DisableFlagsInUseLine: true,
 	}
 
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.all, "all", "a", false, "Delete all manifest lists from local storage")
 	return cmd
 }
 
@@ -7,6 +7,7 @@
 	"github.com/docker/cli/cli/manifest/store"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 // create two manifest lists and remove them both
@@ -63,3 +64,34 @@ func TestRmManifestNotCreated(t *testing.T) {
 	_, err = cli.ManifestStore().GetList(list2)
 	assert.Error(t, err, "No such manifest: example.com/second:2")
 }
+
+func TestRmAllManifests(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(ref(t, "first:1"), namedRef, fullImageManifest(t, namedRef)))
+	assert.NilError(t, manifestStore.Save(ref(t, "second:2"), namedRef, fullImageManifest(t, namedRef)))
+
+	cmd := newRmManifestListCommand(cli)
+	cmd.SetArgs([]string{"--all"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	lists, err := cli.ManifestStore().List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(lists, 0))
+}
+
+func TestRmAllManifestsWithArgs(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store.NewStore(t.TempDir()))
+
+	cmd := newRmManifestListCommand(cli)
+	cmd.SetArgs([]string{"--all", "example.com/first:1"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Error(t, cmd.Execute(), "conflicting options: cannot specify manifest lists with --all")
+}
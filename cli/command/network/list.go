@@ -41,6 +41,7 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Do not truncate the output")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", `Provide filter values (e.g. "driver=bridge")`)
+	_ = flags.SetAnnotation("filter", command.FlagAnnotationLabelFilter, nil)
 
 	return cmd
 }
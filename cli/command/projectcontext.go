@@ -0,0 +1,153 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/store"
+)
+
+const (
+	// ProjectContextFileName is the name of the file that selects the context
+	// for a project. It's looked up in the current working directory and its
+	// parent directories.
+	ProjectContextFileName = ".docker-context"
+
+	// EnvDefaultPlatform is the name of the environment variable that sets
+	// the default platform for commands that pull or build images, and
+	// create containers.
+	EnvDefaultPlatform = "DOCKER_DEFAULT_PLATFORM"
+
+	// FlagAnnotationDefaultPlatform is the annotation of "--platform" flags
+	// that default to [EnvDefaultPlatform]. It allows the default platform to
+	// be set when the CLI is initialized, after the flags are defined.
+	FlagAnnotationDefaultPlatform = "default-platform"
+
+	// FlagAnnotationLabelFilter is the annotation of "--filter" flags of
+	// commands that list objects, which are filtered by the labels of the
+	// project (see [ProjectContext.Labels]).
+	FlagAnnotationLabelFilter = "label-filter"
+)
+
+// ProjectContext is the content of a project's [ProjectContextFileName] file.
+// The file contains either the name of the context, or a JSON object with the
+// following fields.
+type ProjectContext struct {
+	// Context is the name of the context to use.
+	Context string `json:"context,omitempty"`
+
+	// Platform is the default platform, which is used if
+	// [EnvDefaultPlatform] is not set.
+	Platform string `json:"platform,omitempty"`
+
+	// Labels are label filters ("key" or "key=value") that are added to
+	// the filters of commands that list containers, images, volumes, and
+	// networks, to only show the project's objects.
+	Labels []string `json:"labels,omitempty"`
+
+	// Filename is the path of the file.
+	Filename string `json:"-"`
+}
+
+// ContextSource describes what selected the current context.
+type ContextSource string
+
+const (
+	// ContextSourceDefault is used if no context is configured.
+	ContextSourceDefault ContextSource = "default"
+	// ContextSourceFlag is used if the context is selected by the "--context"
+	// or "--host" option.
+	ContextSourceFlag ContextSource = "flag"
+	// ContextSourceEnv is used if the context is selected by the
+	// DOCKER_CONTEXT or DOCKER_HOST environment variable.
+	ContextSourceEnv ContextSource = "env"
+	// ContextSourceProject is used if the context is selected by a
+	// [ProjectContextFileName] file.
+	ContextSourceProject ContextSource = "project"
+	// ContextSourceConfig is used if the context is selected by the
+	// "currentContext" option in the CLI configuration file.
+	ContextSourceConfig ContextSource = "config"
+)
+
+// ContextSelection describes the current context, and what selected it.
+type ContextSelection struct {
+	// Name is the name of the context.
+	Name string
+	// Source is what selected the context.
+	Source ContextSource
+	// Origin is the option, environment variable, or file that selected the
+	// context. It's empty for [ContextSourceDefault].
+	Origin string
+}
+
+// LoadProjectContext looks up the [ProjectContextFileName] file in dir and its
+// parent directories, and loads the first one that's found. It returns nil if
+// no file is found, and an error if the file is invalid. Refer to
+// [config.FindProjectFile] for the files and directories that are considered.
+func LoadProjectContext(dir string) (*ProjectContext, error) {
+	filename := config.FindProjectFile(dir, ProjectContextFileName)
+	if filename == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pc, err := parseProjectContext(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project context file %s: %w", filename, err)
+	}
+	pc.Filename = filename
+	return pc, nil
+}
+
+func parseProjectContext(data []byte) (*ProjectContext, error) {
+	var pc ProjectContext
+	if data = bytes.TrimSpace(data); bytes.HasPrefix(data, []byte("{")) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&pc); err != nil {
+			return nil, err
+		}
+	} else {
+		pc.Context = string(data)
+	}
+
+	if pc.Context != "" && pc.Context != DefaultContextName {
+		if err := store.ValidateContextName(pc.Context); err != nil {
+			return nil, err
+		}
+	}
+	if pc.Platform != "" {
+		if _, err := platforms.Parse(pc.Platform); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range pc.Labels {
+		if k, _, _ := strings.Cut(l, "="); k == "" {
+			return nil, fmt.Errorf("invalid label filter %q", l)
+		}
+	}
+	return &pc, nil
+}
+
+// ProjectContext returns the project's [ProjectContextFileName] file, or nil
+// if the current working directory is not in a project. The file is not used
+// if the context is selected using the "--context" or "--host" options, or
+// the DOCKER_CONTEXT or DOCKER_HOST environment variables.
+func (cli *DockerCli) ProjectContext() *ProjectContext {
+	return cli.projectContext
+}
+
+// ContextSelection returns the current context, and what selected it.
+func (cli *DockerCli) ContextSelection() ContextSelection {
+	return cli.contextSelection
+}
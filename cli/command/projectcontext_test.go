@@ -0,0 +1,218 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package command
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/flags"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLoadProjectContext(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		content     string
+		expected    ProjectContext
+		expectedErr string
+	}{
+		{name: "name", content: "my-context\n", expected: ProjectContext{Context: "my-context"}},
+		{name: "default", content: "default", expected: ProjectContext{Context: "default"}},
+		{
+			name:     "json",
+			content:  `{"context": "my-context", "platform": "linux/arm64", "labels": ["com.example.project=web"]}`,
+			expected: ProjectContext{Context: "my-context", Platform: "linux/arm64", Labels: []string{"com.example.project=web"}},
+		},
+		{name: "platform only", content: `{"platform": "linux/amd64"}`, expected: ProjectContext{Platform: "linux/amd64"}},
+		{name: "invalid name", content: "my context", expectedErr: `context name "my context" is invalid`},
+		{name: "unknown field", content: `{"contxt": "my-context"}`, expectedErr: `unknown field "contxt"`},
+		{name: "invalid platform", content: `{"platform": "linux/arm64/v8/x"}`, expectedErr: "invalid project context file"},
+		{name: "invalid label", content: `{"labels": ["=web"]}`, expectedErr: `invalid label filter "=web"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, ProjectContextFileName)
+			assert.NilError(t, os.WriteFile(filename, []byte(tc.content), 0o644))
+			subDir := filepath.Join(dir, "a", "b")
+			assert.NilError(t, os.MkdirAll(subDir, 0o755))
+
+			pc, err := LoadProjectContext(subDir)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			tc.expected.Filename = filename
+			assert.Check(t, is.DeepEqual(*pc, tc.expected))
+		})
+	}
+}
+
+func TestLoadProjectContextNotFound(t *testing.T) {
+	dir := t.TempDir()
+	// directories with the same name are ignored.
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, ProjectContextFileName), 0o755))
+	pc, err := LoadProjectContext(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Nil(pc))
+}
+
+func TestResolveContext(t *testing.T) {
+	projectDir := t.TempDir()
+	projectFile := filepath.Join(projectDir, ProjectContextFileName)
+	assert.NilError(t, os.WriteFile(projectFile, []byte("project-context"), 0o644))
+	cfg := configfile.New("/home/user/.docker/config.json")
+	cfg.CurrentContext = "config-context"
+
+	for _, tc := range []struct {
+		name     string
+		opts     *flags.ClientOptions
+		env      map[string]string
+		dir      string
+		cfg      *configfile.ConfigFile
+		expected ContextSelection
+	}{
+		{
+			name:     "default",
+			dir:      t.TempDir(),
+			expected: ContextSelection{Name: DefaultContextName, Source: ContextSourceDefault},
+		},
+		{
+			name:     "config",
+			dir:      t.TempDir(),
+			cfg:      cfg,
+			expected: ContextSelection{Name: "config-context", Source: ContextSourceConfig, Origin: cfg.Filename},
+		},
+		{
+			name:     "project",
+			dir:      projectDir,
+			cfg:      cfg,
+			expected: ContextSelection{Name: "project-context", Source: ContextSourceProject, Origin: projectFile},
+		},
+		{
+			name:     "DOCKER_CONTEXT",
+			dir:      projectDir,
+			cfg:      cfg,
+			env:      map[string]string{EnvOverrideContext: "env-context"},
+			expected: ContextSelection{Name: "env-context", Source: ContextSourceEnv, Origin: "DOCKER_CONTEXT environment variable"},
+		},
+		{
+			name:     "DOCKER_HOST",
+			dir:      projectDir,
+			cfg:      cfg,
+			env:      map[string]string{client.EnvOverrideHost: "tcp://127.0.0.1:2375", EnvOverrideContext: "env-context"},
+			expected: ContextSelection{Name: DefaultContextName, Source: ContextSourceEnv, Origin: "DOCKER_HOST environment variable"},
+		},
+		{
+			name:     "--context",
+			dir:      projectDir,
+			cfg:      cfg,
+			opts:     &flags.ClientOptions{Context: "flag-context"},
+			env:      map[string]string{EnvOverrideContext: "env-context"},
+			expected: ContextSelection{Name: "flag-context", Source: ContextSourceFlag, Origin: "--context option"},
+		},
+		{
+			name:     "--host",
+			dir:      projectDir,
+			opts:     &flags.ClientOptions{Hosts: []string{"tcp://127.0.0.1:2375"}},
+			expected: ContextSelection{Name: DefaultContextName, Source: ContextSourceFlag, Origin: "--host option"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(client.EnvOverrideHost, "")
+			t.Setenv(EnvOverrideContext, "")
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			t.Chdir(tc.dir)
+
+			sel, pc := resolveContext(tc.opts, tc.cfg, io.Discard)
+			assert.Check(t, is.DeepEqual(sel, tc.expected))
+			// the project's file is not used if the context is selected
+			// by flags or environment variables.
+			assert.Check(t, is.Equal(pc != nil, tc.expected.Source == ContextSourceProject))
+		})
+	}
+}
+
+func TestResolveContextInvalidProjectFile(t *testing.T) {
+	projectDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(projectDir, ProjectContextFileName), []byte("-invalid"), 0o644))
+	t.Chdir(projectDir)
+	t.Setenv(client.EnvOverrideHost, "")
+	t.Setenv(EnvOverrideContext, "")
+	cfg := configfile.New("/home/user/.docker/config.json")
+	cfg.CurrentContext = "config-context"
+
+	var errOut bytes.Buffer
+	sel, pc := resolveContext(nil, cfg, &errOut)
+	assert.Check(t, is.Nil(pc))
+	assert.Check(t, is.Equal(sel.Name, "config-context"))
+	assert.Check(t, is.Contains(errOut.String(), "WARNING: invalid project context file"))
+
+	// the file is not parsed if the context is selected by flags.
+	errOut.Reset()
+	sel, _ = resolveContext(&flags.ClientOptions{Context: "flag-context"}, cfg, &errOut)
+	assert.Check(t, is.Equal(sel.Name, "flag-context"))
+	assert.Check(t, is.Equal(errOut.String(), ""))
+}
+
+func TestResolveContextStopsAtRepositoryRoot(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ProjectContextFileName), []byte("outer-context"), 0o644))
+	repoDir := filepath.Join(dir, "repo")
+	assert.NilError(t, os.MkdirAll(filepath.Join(repoDir, ".git"), 0o755))
+	t.Chdir(repoDir)
+	t.Setenv(client.EnvOverrideHost, "")
+	t.Setenv(EnvOverrideContext, "")
+
+	sel, pc := resolveContext(nil, nil, io.Discard)
+	assert.Check(t, is.Nil(pc))
+	assert.Check(t, is.Equal(sel.Name, DefaultContextName))
+}
+
+func TestInitializeProjectContext(t *testing.T) {
+	projectDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(projectDir, ProjectContextFileName), []byte(`{"platform": "linux/arm64"}`), 0o644))
+	t.Chdir(projectDir)
+	t.Setenv(client.EnvOverrideHost, "")
+	t.Setenv(EnvOverrideContext, "")
+	t.Setenv(EnvDefaultPlatform, "")
+
+	cli, err := NewDockerCli()
+	assert.NilError(t, err)
+	apiClient, err := client.New()
+	assert.NilError(t, err)
+	assert.NilError(t, cli.Initialize(flags.NewClientOptions(), WithAPIClient(apiClient)))
+	assert.Check(t, cli.ProjectContext() != nil)
+	// the environment of the process is not changed.
+	assert.Check(t, is.Equal(os.Getenv(EnvDefaultPlatform), ""))
+
+	assert.NilError(t, os.WriteFile(filepath.Join(projectDir, ProjectContextFileName), []byte("-invalid"), 0o644))
+	var errOut bytes.Buffer
+	cli, err = NewDockerCli(WithErrorStream(&errOut))
+	assert.NilError(t, err)
+	assert.NilError(t, cli.Initialize(flags.NewClientOptions(), WithAPIClient(apiClient)))
+	assert.Check(t, is.Nil(cli.ProjectContext()))
+	assert.Check(t, is.Contains(errOut.String(), "invalid project context file"))
+}
+
+func TestCurrentContextOrigin(t *testing.T) {
+	cfg := configfile.New("/home/user/.docker/config.json")
+	cfg.CurrentContext = "my-context"
+	assert.Check(t, is.Equal(currentContextOrigin(cfg), cfg.Filename))
+
+	cfg.Layers = []configfile.ConfigLayer{
+		{Layer: configfile.LayerSystem, Filename: "/etc/docker/cli-config.json", Data: map[string]any{"currentContext": "my-context"}},
+		{Layer: configfile.LayerUser, Filename: cfg.Filename, Data: map[string]any{"psFormat": "table"}},
+	}
+	assert.Check(t, is.Equal(currentContextOrigin(cfg), "/etc/docker/cli-config.json"))
+}
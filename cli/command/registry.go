@@ -4,9 +4,12 @@
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/config/configfile"
@@ -14,11 +17,13 @@
 	configtypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/cli/hints"
 	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/oauth/manager"
 	"github.com/docker/cli/internal/prompt"
 	"github.com/docker/cli/internal/tui"
 	"github.com/moby/moby/api/pkg/authconfig"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/morikuni/aec"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -35,6 +40,14 @@
 // [registry.IndexServer]: https://pkg.go.dev/github.com/docker/docker@v28.3.3+incompatible/registry#IndexServer
 const authConfigKey = "https://index.docker.io/v1/"
 
+// oauthRefreshTimeout is the maximum time to spend on refreshing the OAuth
+// tokens for Docker Hub when resolving credentials.
+const oauthRefreshTimeout = 30 * time.Second
+
+// newOAuthManager is used to create the OAuth manager for Docker Hub. It can
+// be overridden in tests to use a stand-in OAuth tenant.
+var newOAuthManager = manager.NewManager
+
 // ResolveAuthConfig returns auth-config for the given registry from the
 // credential-store. It returns an empty AuthConfig if no credentials were
 // found.
@@ -46,7 +59,7 @@ func ResolveAuthConfig(cfg *configfile.ConfigFile, index *registrytypes.IndexInf
 		configKey = authConfigKey
 	}
 
-	a, _ := cfg.GetAuthConfig(configKey)
+	a, _ := getAuthConfig(cfg, configKey)
 	return registrytypes.AuthConfig{
 		Username:      a.Username,
 		Password:      a.Password,
@@ -59,6 +72,26 @@ func ResolveAuthConfig(cfg *configfile.ConfigFile, index *registrytypes.IndexInf
 	}
 }
 
+// RegistryAuthConfigResolver returns a function that resolves the credentials
+// for a registry's domain name from the CLI's configuration, for use with a
+// registry client. It returns an empty AuthConfig if no credentials were
+// found.
+func RegistryAuthConfigResolver(dockerCLI Cli) func(ctx context.Context, domainName string) registrytypes.AuthConfig {
+	return func(ctx context.Context, domainName string) registrytypes.AuthConfig {
+		a, _ := getAuthConfig(dockerCLI.ConfigFile(), getAuthConfigKey(domainName))
+		return registrytypes.AuthConfig{
+			Username:      a.Username,
+			Password:      a.Password,
+			ServerAddress: a.ServerAddress,
+
+			// TODO(thaJeztah): Are these expected to be included?
+			Auth:          a.Auth,
+			IdentityToken: a.IdentityToken,
+			RegistryToken: a.RegistryToken,
+		}
+	}
+}
+
 // GetDefaultAuthConfig gets the default auth config given a serverAddress
 // If credentials for given serverAddress exists in the credential store, the configuration will be populated with values in it
 func GetDefaultAuthConfig(cfg *configfile.ConfigFile, checkCredStore bool, serverAddress string, isDefaultRegistry bool) (registrytypes.AuthConfig, error) {
@@ -68,7 +101,7 @@ func GetDefaultAuthConfig(cfg *configfile.ConfigFile, checkCredStore bool, serve
 	authCfg := configtypes.AuthConfig{}
 	var err error
 	if checkCredStore {
-		authCfg, err = cfg.GetAuthConfig(serverAddress)
+		authCfg, err = getAuthConfig(cfg, serverAddress)
 		if err != nil {
 			return registrytypes.AuthConfig{
 				ServerAddress: serverAddress,
@@ -195,7 +228,7 @@ func RetrieveAuthTokenFromImage(cfg *configfile.ConfigFile, image string) (strin
 		return "", err
 	}
 	configKey := getAuthConfigKey(reference.Domain(registryRef))
-	authConfig, err := cfg.GetAuthConfig(configKey)
+	authConfig, err := getAuthConfig(cfg, configKey)
 	if err != nil {
 		return "", err
 	}
@@ -212,6 +245,58 @@ func RetrieveAuthTokenFromImage(cfg *configfile.ConfigFile, image string) (strin
 	})
 }
 
+// oauthRefresh holds the context and the error stream that are used to
+// refresh the OAuth tokens for Docker Hub for a configuration file. The
+// tokens are checked once per configuration file, so that the credential
+// store isn't queried for them each time credentials are resolved.
+type oauthRefresh struct {
+	once   sync.Once
+	ctx    context.Context
+	errOut io.Writer
+}
+
+// oauthRefreshes holds the [oauthRefresh] of each configuration file.
+var oauthRefreshes sync.Map
+
+// setOAuthRefreshContext sets the context and the error stream that are used
+// to refresh the OAuth tokens for Docker Hub for the configuration file. The
+// background context and [os.Stderr] are used for configuration files that
+// are not loaded by a [DockerCli].
+func setOAuthRefreshContext(ctx context.Context, cfg *configfile.ConfigFile, errOut io.Writer) {
+	oauthRefreshes.Store(cfg, &oauthRefresh{ctx: ctx, errOut: errOut})
+}
+
+// getAuthConfig returns the credentials stored for the given key. Credentials
+// for Docker Hub that were obtained through the web-based login flow are
+// refreshed first if the OAuth access token is about to expire.
+func getAuthConfig(cfg *configfile.ConfigFile, configKey string) (configtypes.AuthConfig, error) {
+	if configKey == authConfigKey {
+		v, _ := oauthRefreshes.LoadOrStore(cfg, &oauthRefresh{ctx: context.Background(), errOut: os.Stderr})
+		r := v.(*oauthRefresh)
+		r.once.Do(func() {
+			refreshOAuthTokens(r.ctx, cfg, r.errOut)
+		})
+	}
+	return cfg.GetAuthConfig(configKey)
+}
+
+// refreshOAuthTokens refreshes the OAuth tokens for Docker Hub if needed.
+// Failing to refresh is not fatal; the stored credentials are used as-is,
+// and authentication fails if they expired.
+func refreshOAuthTokens(ctx context.Context, cfg *configfile.ConfigFile, errOut io.Writer) {
+	ctx, cancel := context.WithTimeout(ctx, oauthRefreshTimeout)
+	defer cancel()
+
+	refreshed, err := newOAuthManager(cfg.GetCredentialsStore(authConfigKey)).RefreshTokens(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintln(errOut, "WARNING: failed to refresh credentials for Docker Hub:", err)
+		return
+	}
+	if refreshed {
+		logrus.Debug("refreshed OAuth tokens for Docker Hub")
+	}
+}
+
 // getAuthConfigKey special-cases using the full index address of the official
 // index as the AuthConfig key, and uses the (host)name[:port] for private indexes.
 //
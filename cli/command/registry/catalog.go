@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/opts"
+	"github.com/spf13/cobra"
+)
+
+type catalogOptions struct {
+	registry string
+	format   string
+	filter   opts.FilterOpt
+	insecure bool
+}
+
+// newCatalogCommand creates a new `docker registry catalog` command
+func newCatalogCommand(dockerCLI command.Cli) *cobra.Command {
+	options := catalogOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "catalog [OPTIONS] REGISTRY",
+		Short: "List the repositories in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.registry = args[0]
+			return runCatalog(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&options.filter, "filter", "f", `Filter output based on conditions provided (e.g. "name=library/*")`)
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runCatalog(ctx context.Context, dockerCLI command.Cli, options catalogOptions) error {
+	match, err := nameFilter(options.filter.Value())
+	if err != nil {
+		return err
+	}
+	domain := strings.TrimSuffix(options.registry, "/")
+	if domain == "" || strings.Contains(domain, "/") {
+		return fmt.Errorf("invalid registry (%s): must be a hostname, optionally with a port", options.registry)
+	}
+
+	repos, err := newRegistryClient(dockerCLI, options.insecure).ListRepositories(ctx, domain)
+	if err != nil {
+		return err
+	}
+	filtered := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if match(repo) {
+			filtered = append(filtered, repo)
+		}
+	}
+
+	catalogCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newCatalogFormat(options.format),
+	}
+	return formatCatalogWrite(catalogCtx, filtered)
+}
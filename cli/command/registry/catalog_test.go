@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestRegistryCatalog(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "table",
+			args:   []string{"example.com"},
+			golden: "catalog.golden",
+		},
+		{
+			name:   "filter",
+			args:   []string{"--filter", "name=library/*", "example.com"},
+			golden: "catalog-filter.golden",
+		},
+		{
+			name:   "format",
+			args:   []string{"--format", "{{.Name}}", "example.com/"},
+			golden: "catalog-format.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{
+				listRepositoriesFunc: func(_ context.Context, domain string) ([]string, error) {
+					assert.Check(t, is.Equal(domain, "example.com"))
+					return []string{"library/alpine", "library/busybox", "myorg/app"}, nil
+				},
+			})
+			cmd := newCatalogCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestRegistryCatalogInvalidRegistry(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{})
+	cmd := newCatalogCommand(cli)
+	cmd.SetArgs([]string{"example.com/library"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "invalid registry (example.com/library)")
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/moby/moby/client"
+)
+
+// registryClientProvider is used in tests to provide a dummy registry client.
+type registryClientProvider interface {
+	RegistryClient(bool) registryclient.RegistryClient
+}
+
+// newRegistryClient returns a client for communicating with a Docker
+// distribution registry, using the credentials stored in the CLI's
+// configuration.
+func newRegistryClient(dockerCLI command.Cli, allowInsecure bool) registryclient.RegistryClient {
+	if rcp, ok := dockerCLI.(registryClientProvider); ok {
+		// registryClientProvider is used in tests to provide a dummy client.
+		return rcp.RegistryClient(allowInsecure)
+	}
+	return registryclient.NewRegistryClient(command.RegistryAuthConfigResolver(dockerCLI), command.UserAgent(), allowInsecure)
+}
+
+// nameFilter returns a function that matches names against the glob
+// patterns passed as "name" filter. Filters other than "name" are rejected,
+// unless included in otherFilters.
+func nameFilter(filters client.Filters, otherFilters ...string) (func(string) bool, error) {
+	var patterns []string
+	for key, values := range filters {
+		if key != "name" {
+			if slices.Contains(otherFilters, key) {
+				continue
+			}
+			return nil, errdefs.ErrInvalidArgument.WithMessage("invalid filter '" + key + "'")
+		}
+		for pattern := range values {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, errdefs.ErrInvalidArgument.WithMessage(fmt.Sprintf("invalid name filter '%s': %v", pattern, err))
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return func(name string) bool {
+		if len(patterns) == 0 {
+			return true
+		}
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,33 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newRegistryCommand)
+}
+
+// newRegistryCommand returns a cobra command for `registry` subcommands
+func newRegistryCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry COMMAND",
+		Short: "Manage images in registries",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_, _ = fmt.Fprint(dockerCLI.Err(), "\n"+cmd.UsageString())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newTagsCommand(dockerCLI),
+		newCatalogCommand(dockerCLI),
+		newRmCommand(dockerCLI),
+	)
+	return cmd
+}
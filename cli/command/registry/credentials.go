@@ -0,0 +1,138 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/internal/commands"
+	"github.com/docker/cli/internal/oauth/manager"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commands.Register(newCredentialsCommand)
+}
+
+// newCredentialsCommand returns a cobra command for `credentials` subcommands
+func newCredentialsCommand(dockerCLI command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "credentials COMMAND",
+		Short: "Manage stored registry credentials",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_, _ = fmt.Fprint(dockerCLI.Err(), "\n"+cmd.UsageString())
+		},
+		DisableFlagsInUseLine: true,
+	}
+	cmd.AddCommand(
+		newCredentialsListCommand(dockerCLI),
+		newCredentialsMigrateCommand(dockerCLI),
+	)
+	return cmd
+}
+
+type credentialsListOptions struct {
+	format string
+	quiet  bool
+	verify bool
+}
+
+// newCredentialsListCommand creates a new `docker credentials ls` command
+func newCredentialsListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts credentialsListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List registries with stored credentials",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCredentialsList(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show registry names")
+	flags.BoolVar(&opts.verify, "verify", false, "Verify the stored credentials with each registry")
+	return cmd
+}
+
+// credentialsEntry describes the credentials stored for a registry.
+type credentialsEntry struct {
+	Registry      string
+	Store         string
+	Username      string
+	IdentityToken bool
+	Status        string
+}
+
+const (
+	credentialsStatusValid   = "valid"
+	credentialsStatusInvalid = "invalid"
+)
+
+func runCredentialsList(ctx context.Context, dockerCLI command.Cli, opts credentialsListOptions) error {
+	cfg := dockerCLI.ConfigFile()
+	auths, err := cfg.GetAllCredentials()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]credentialsEntry, 0, len(auths))
+	for registry, a := range auths {
+		if manager.IsTokenKey(registry) {
+			continue
+		}
+		entry := credentialsEntry{
+			Registry:      registry,
+			Store:         cfg.GetCredentialsStoreName(registry),
+			Username:      a.Username,
+			IdentityToken: a.IdentityToken != "",
+		}
+		if opts.verify {
+			entry.Status = verifyCredentials(ctx, client.RegistryLoginOptions{
+				Username:      a.Username,
+				Password:      a.Password,
+				ServerAddress: registry,
+				IdentityToken: a.IdentityToken,
+				RegistryToken: a.RegistryToken,
+			})
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Registry < entries[j].Registry
+	})
+
+	credsCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newCredentialsFormat(opts.format, opts.quiet, opts.verify),
+	}
+	return formatCredentialsWrite(credsCtx, entries)
+}
+
+// verifyLogin logs in to a registry from the CLI, so that credentials are
+// not sent to the daemon. It's a variable so that it can be replaced in tests.
+var verifyLogin = loginClientSide
+
+// verifyCredentials attempts to log in to the registry with the stored
+// credentials, and returns the result as a status.
+func verifyCredentials(ctx context.Context, options client.RegistryLoginOptions) string {
+	if _, err := verifyLogin(ctx, options); err != nil {
+		if errdefs.IsUnauthorized(err) {
+			return credentialsStatusInvalid
+		}
+		return "error: " + err.Error()
+	}
+	return credentialsStatusValid
+}
@@ -0,0 +1,227 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	credhelpers "github.com/docker/docker-credential-helpers/client"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+	"gotest.tools/v3/skip"
+)
+
+func newCredentialsTestCli(t *testing.T) *test.FakeCli {
+	t.Helper()
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	for _, a := range []configtypes.AuthConfig{
+		{ServerAddress: "registry.example.com", Username: "alice", Password: "secret"},
+		{ServerAddress: "ghcr.io", Username: "bob", IdentityToken: "some-token"},
+		{ServerAddress: "expired.example.com", Username: "carol", Password: expiredPassword},
+	} {
+		assert.NilError(t, cfg.GetCredentialsStore(a.ServerAddress).Store(a))
+	}
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(cfg)
+	return cli
+}
+
+func TestCredentialsList(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "table",
+			args:   []string{},
+			golden: "credentials-list.golden",
+		},
+		{
+			name:   "quiet",
+			args:   []string{"--quiet"},
+			golden: "credentials-list-quiet.golden",
+		},
+		{
+			name:   "json",
+			args:   []string{"--format", "json"},
+			golden: "credentials-list-json.golden",
+		},
+		{
+			name:   "verify",
+			args:   []string{"--verify"},
+			golden: "credentials-list-verify.golden",
+		},
+	}
+	verifyLogin = func(_ context.Context, options client.RegistryLoginOptions) (client.RegistryLoginResult, error) {
+		if options.Password == expiredPassword {
+			return client.RegistryLoginResult{}, errdefs.ErrUnauthenticated.WithMessage("invalid Username or Password")
+		}
+		return client.RegistryLoginResult{}, nil
+	}
+	t.Cleanup(func() { verifyLogin = loginClientSide })
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := newCredentialsTestCli(t)
+			cmd := newCredentialsListCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestCredentialsListOAuthTokens(t *testing.T) {
+	cli := newCredentialsTestCli(t)
+	for _, a := range []configtypes.AuthConfig{
+		{ServerAddress: "https://index.docker.io/v1/", Username: "dave", Password: "pat"},
+		{ServerAddress: "https://index.docker.io/v1/access-token", Username: "dave", Password: "access-token"},
+		{ServerAddress: "https://index.docker.io/v1/refresh-token", Username: "dave", Password: "refresh-token"},
+	} {
+		assert.NilError(t, cli.ConfigFile().GetCredentialsStore(a.ServerAddress).Store(a))
+	}
+	cmd := newCredentialsListCommand(cli)
+	cmd.SetArgs([]string{"--quiet"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `expired.example.com
+ghcr.io
+https://index.docker.io/v1/
+registry.example.com
+`))
+}
+
+func TestCredentialsListEnv(t *testing.T) {
+	t.Setenv(configfile.DockerEnvConfigKey, `{"auths": {"env.example.com": {"auth": "ZW52X3VzZXI6ZW52X3Bhc3M="}}}`)
+	cli := newCredentialsTestCli(t)
+	cmd := newCredentialsListCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Registry}} {{.Store}} {{.Username}}"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "env.example.com env env_user\n"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "ghcr.io file bob\n"))
+}
+
+func TestLogoutAll(t *testing.T) {
+	cli := newCredentialsTestCli(t)
+	cmd := newLogoutCommand(cli)
+	cmd.SetArgs([]string{"--all"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Removing login credentials for expired.example.com
+Removing login credentials for ghcr.io
+Removing login credentials for registry.example.com
+`))
+
+	auths, err := cli.ConfigFile().GetAllCredentials()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(auths, 0))
+}
+
+func TestLogoutAllSkipsEnv(t *testing.T) {
+	t.Setenv(configfile.DockerEnvConfigKey, `{"auths": {"env.example.com": {"auth": "ZW52X3VzZXI6ZW52X3Bhc3M="}}}`)
+	cli := newCredentialsTestCli(t)
+	cmd := newLogoutCommand(cli)
+	cmd.SetArgs([]string{"--all"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, !strings.Contains(cli.OutBuffer().String(), "env.example.com"))
+
+	auths, err := cli.ConfigFile().GetAllCredentials()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(auths, 1))
+	assert.Check(t, is.Equal(auths["env.example.com"].Username, "env_user"))
+}
+
+func TestLogoutAllWithServer(t *testing.T) {
+	cli := newCredentialsTestCli(t)
+	cmd := newLogoutCommand(cli)
+	cmd.SetArgs([]string{"--all", "ghcr.io"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "conflicting options: cannot specify a server with --all")
+
+	auths, err := cli.ConfigFile().GetAllCredentials()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(auths, 3))
+}
+
+// credentialHelperScript is a credential helper that keeps each credential
+// in a file in the "data" directory next to the helper.
+const credentialHelperScript = `#!/bin/sh
+dir="$(dirname "$0")/data"
+mkdir -p "$dir"
+case "$1" in
+store)
+	in="$(cat)"
+	url="$(printf '%s' "$in" | sed -n 's/.*"ServerURL":"\([^"]*\)".*/\1/p')"
+	printf '%s' "$in" > "$dir/$(printf '%s' "$url" | tr '/:' '__')" ;;
+get)
+	f="$dir/$(cat | tr '/:' '__')"
+	if [ -f "$f" ]; then cat "$f"; else echo "credentials not found in native keychain"; exit 1; fi ;;
+erase)
+	rm -f "$dir/$(cat | tr '/:' '__')" ;;
+list)
+	sep=""
+	printf '{'
+	for f in "$dir"/*; do
+		[ -f "$f" ] || continue
+		url="$(sed -n 's/.*"ServerURL":"\([^"]*\)".*/\1/p' "$f")"
+		user="$(sed -n 's/.*"Username":"\([^"]*\)".*/\1/p' "$f")"
+		printf '%s"%s":"%s"' "$sep" "$url" "$user"
+		sep=","
+	done
+	printf '}' ;;
+esac
+`
+
+func TestLogoutAllKeepsContextTLSKey(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test uses a POSIX shell")
+	binDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(binDir, "docker-credential-test"), []byte(credentialHelperScript), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	cfg.CredentialsStore = "test"
+	assert.NilError(t, cfg.GetCredentialsStore("registry.example.com").Store(configtypes.AuthConfig{
+		ServerAddress: "registry.example.com",
+		Username:      "alice",
+		Password:      "secret",
+	}))
+	assert.NilError(t, command.NewContextTLSKeyStore(cfg).CreateKey("test"))
+
+	auths, err := cfg.GetAllCredentials()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(auths, 1))
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(cfg)
+	cmd := newLogoutCommand(cli)
+	cmd.SetArgs([]string{"--all"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Removing login credentials for registry.example.com\n"))
+
+	_, err = credhelpers.Get(credhelpers.NewShellProgramFunc("docker-credential-test"), credentials.ContextTLSKeyServerAddress)
+	assert.Check(t, err, "the context TLS key must not be removed")
+}
@@ -0,0 +1,101 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultTagsTableFormat    = "table {{.Tag}}"
+	defaultCatalogTableFormat = "table {{.Name}}"
+
+	tagHeader        = "TAG"
+	repositoryHeader = "REPOSITORY"
+)
+
+// newTagsFormat returns a Format for rendering using a tagContext.
+func newTagsFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultTagsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// formatTagsWrite writes the tags of repository using the context.
+func formatTagsWrite(fmtCtx formatter.Context, repository string, tags []string) error {
+	tagsCtx := &tagContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Repository": repositoryHeader,
+				"Tag":        tagHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(tagsCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, tag := range tags {
+			if err := format(&tagContext{repository: repository, tag: tag}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type tagContext struct {
+	formatter.HeaderContext
+	repository string
+	tag        string
+}
+
+func (c *tagContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *tagContext) Repository() string {
+	return c.repository
+}
+
+func (c *tagContext) Tag() string {
+	return c.tag
+}
+
+// newCatalogFormat returns a Format for rendering using a catalogContext.
+func newCatalogFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultCatalogTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// formatCatalogWrite writes the repositories using the context.
+func formatCatalogWrite(fmtCtx formatter.Context, repositories []string) error {
+	catalogCtx := &catalogContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Name": repositoryHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(catalogCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, repo := range repositories {
+			if err := format(&catalogContext{name: repo}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type catalogContext struct {
+	formatter.HeaderContext
+	name string
+}
+
+func (c *catalogContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *catalogContext) Name() string {
+	return c.name
+}
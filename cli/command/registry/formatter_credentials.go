@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"strconv"
+
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultCredentialsTableFormat       = "table {{.Registry}}\t{{.Store}}\t{{.Username}}\t{{.IdentityToken}}"
+	defaultCredentialsVerifyTableFormat = "table {{.Registry}}\t{{.Store}}\t{{.Username}}\t{{.IdentityToken}}\t{{.Status}}"
+
+	registryHeader      = "REGISTRY"
+	storeHeader         = "STORE"
+	usernameHeader      = "USERNAME"
+	identityTokenHeader = "IDENTITY TOKEN"
+)
+
+// newCredentialsFormat returns a Format for rendering using a credentialsContext.
+func newCredentialsFormat(source string, quiet bool, verify bool) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		if quiet {
+			return "{{.Registry}}"
+		}
+		if verify {
+			return defaultCredentialsVerifyTableFormat
+		}
+		return defaultCredentialsTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return "registry: {{.Registry}}"
+		}
+		return "registry: {{.Registry}}\nstore: {{.Store}}\nusername: {{.Username}}\nidentity_token: {{.IdentityToken}}\n"
+	}
+	return formatter.Format(source)
+}
+
+// formatCredentialsWrite writes the credentials entries using the context.
+func formatCredentialsWrite(fmtCtx formatter.Context, entries []credentialsEntry) error {
+	credsCtx := &credentialsContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Registry":      registryHeader,
+				"Store":         storeHeader,
+				"Username":      usernameHeader,
+				"IdentityToken": identityTokenHeader,
+				"Status":        formatter.StatusHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(credsCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, entry := range entries {
+			if err := format(&credentialsContext{e: entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type credentialsContext struct {
+	formatter.HeaderContext
+	e credentialsEntry
+}
+
+func (c *credentialsContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *credentialsContext) Registry() string {
+	return c.e.Registry
+}
+
+func (c *credentialsContext) Store() string {
+	return c.e.Store
+}
+
+func (c *credentialsContext) Username() string {
+	return c.e.Username
+}
+
+func (c *credentialsContext) IdentityToken() string {
+	return strconv.FormatBool(c.e.IdentityToken)
+}
+
+func (c *credentialsContext) Status() string {
+	return c.e.Status
+}
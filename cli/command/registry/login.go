@@ -7,6 +7,7 @@
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
@@ -17,6 +18,7 @@
 	"github.com/docker/cli/internal/oauth/manager"
 	"github.com/docker/cli/internal/registry"
 	"github.com/docker/cli/internal/tui"
+	"github.com/docker/go-units"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
@@ -32,6 +34,7 @@ type loginOptions struct {
 	user          string
 	password      string
 	passwordStdin bool
+	status        bool
 }
 
 // newLoginCommand creates a new `docker login` command
@@ -50,6 +53,9 @@ func newLoginCommand(dockerCLI command.Cli) *cobra.Command {
 			if err := verifyLoginFlags(cmd.Flags(), opts); err != nil {
 				return err
 			}
+			if opts.status {
+				return runLoginStatus(dockerCLI, opts.serverAddress)
+			}
 			return runLogin(cmd.Context(), dockerCLI, opts)
 		},
 		Annotations: map[string]string{
@@ -64,6 +70,7 @@ func newLoginCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVarP(&opts.user, "username", "u", "", "Username")
 	flags.StringVarP(&opts.password, "password", "p", "", "Password or Personal Access Token (PAT)")
 	flags.BoolVar(&opts.passwordStdin, "password-stdin", false, "Take the Password or Personal Access Token (PAT) from stdin")
+	flags.BoolVar(&opts.status, "status", false, "Show the account that is logged in, without logging in")
 
 	return cmd
 }
@@ -72,6 +79,13 @@ func newLoginCommand(dockerCLI command.Cli) *cobra.Command {
 //
 // TODO(thaJeztah); combine with verifyLoginOptions, but this requires rewrites of many tests.
 func verifyLoginFlags(flags *pflag.FlagSet, opts loginOptions) error {
+	if flags.Changed("status") {
+		for _, f := range []string{"username", "password", "password-stdin"} {
+			if flags.Changed(f) {
+				return errors.New("conflicting options: cannot specify both --status and --" + f)
+			}
+		}
+	}
 	if flags.Changed("password-stdin") {
 		if flags.Changed("password") {
 			return errors.New("conflicting options: cannot specify both --password and --password-stdin")
@@ -181,6 +195,67 @@ func runLogin(ctx context.Context, dockerCLI command.Cli, opts loginOptions) err
 	return nil
 }
 
+// runLoginStatus prints the account that is logged in to the given registry.
+// For Docker Hub accounts that logged in through the web-based login flow,
+// the expiry and scopes of the OAuth access token are printed as well.
+func runLoginStatus(dockerCLI command.Cli, serverAddress string) error {
+	if serverAddress == "" || serverAddress == registry.DefaultNamespace {
+		serverAddress = registry.IndexServer
+	}
+	isDefaultRegistry := serverAddress == registry.IndexServer
+
+	// resolving the credentials refreshes the OAuth tokens if needed.
+	cfg := dockerCLI.ConfigFile()
+	authConfig, err := command.GetDefaultAuthConfig(cfg, true, serverAddress, isDefaultRegistry)
+	if err != nil {
+		return err
+	}
+	if authConfig.Username == "" && authConfig.Password == "" && authConfig.RegistryToken == "" {
+		return fmt.Errorf("not logged in to %s", authConfig.ServerAddress)
+	}
+
+	out := dockerCLI.Out()
+	_, _ = fmt.Fprintln(out, "Server:          ", authConfig.ServerAddress)
+	if authConfig.Username != "" {
+		_, _ = fmt.Fprintln(out, "Username:        ", authConfig.Username)
+	}
+	_, _ = fmt.Fprintln(out, "Credential store:", cfg.GetCredentialsStoreName(authConfig.ServerAddress))
+	if !isDefaultRegistry {
+		return nil
+	}
+
+	status, err := manager.NewManager(cfg.GetCredentialsStore(registry.IndexServer)).Status()
+	if err != nil {
+		if errors.Is(err, manager.ErrNoTokens) {
+			return nil
+		}
+		return err
+	}
+	if status.Username != authConfig.Username {
+		// the tokens are stale, and don't belong to the account that's logged in.
+		return nil
+	}
+	_, _ = fmt.Fprintln(out, "Login method:     web-based login")
+	if status.Email != "" {
+		_, _ = fmt.Fprintln(out, "Email:           ", status.Email)
+	}
+	if !status.Expiry.IsZero() {
+		_, _ = fmt.Fprintln(out, "Token expires:   ", formatTokenExpiry(status.Expiry, time.Now()))
+	}
+	if len(status.Scopes) > 0 {
+		_, _ = fmt.Fprintln(out, "Scopes:          ", strings.Join(status.Scopes, " "))
+	}
+	return nil
+}
+
+func formatTokenExpiry(expiry, now time.Time) string {
+	ts := expiry.UTC().Format(time.RFC3339)
+	if !expiry.After(now) {
+		return ts + " (expired)"
+	}
+	return ts + " (in " + strings.ToLower(units.HumanDuration(expiry.Sub(now))) + ")"
+}
+
 func loginWithStoredCredentials(ctx context.Context, dockerCLI command.Cli, authConfig registrytypes.AuthConfig) (msg string, _ error) {
 	_, _ = fmt.Fprintf(dockerCLI.Err(), "Authenticating with existing credentials...")
 	if authConfig.Username != "" {
@@ -7,6 +7,7 @@
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 	"testing"
 	"testing/iotest"
 	"time"
@@ -15,9 +16,12 @@
 	"github.com/docker/cli/cli/config/configfile"
 	configtypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/oauth"
 	"github.com/docker/cli/internal/prompt"
 	"github.com/docker/cli/internal/registry"
 	"github.com/docker/cli/internal/test"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
@@ -642,6 +646,11 @@ func TestLoginValidateFlags(t *testing.T) {
 			args:        []string{"--password"},
 			expectedErr: `flag needs an argument: --password`,
 		},
+		{
+			name:        "conflicting options --status and --username",
+			args:        []string{"--status", "--username", "my-username"},
+			expectedErr: `conflicting options: cannot specify both --status and --username`,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			cmd := newLoginCommand(test.NewFakeCli(&fakeClient{}))
@@ -658,3 +667,100 @@ func TestLoginValidateFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginStatus(t *testing.T) {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("a-signing-key-of-sufficient-length")}, nil)
+	assert.NilError(t, err)
+	accessToken, err := jwt.Signed(signer).Claims(oauth.Claims{
+		Claims: jwt.Claims{Expiry: jwt.NewNumericDate(time.Now().Add(3*time.Hour + time.Minute))},
+		Domain: oauth.DomainClaims{Username: "my-username", Email: "me@example.com"},
+		Scope:  "openid offline_access",
+	}).Serialize()
+	assert.NilError(t, err)
+
+	for _, tc := range []struct {
+		name           string
+		args           []string
+		stored         []configtypes.AuthConfig
+		expectedErr    string
+		expectedOut    []string
+		notExpectedOut []string
+	}{
+		{
+			name:        "not logged in",
+			args:        []string{"--status"},
+			expectedErr: "not logged in to https://index.docker.io/v1/",
+		},
+		{
+			name: "private registry",
+			args: []string{"--status", "registry.example.com"},
+			stored: []configtypes.AuthConfig{
+				{ServerAddress: "registry.example.com", Username: "my-username", Password: "a-password"},
+			},
+			expectedOut: []string{
+				"Server:           registry.example.com\n",
+				"Username:         my-username\n",
+				"Credential store: file\n",
+			},
+			notExpectedOut: []string{"Login method", "a-password"},
+		},
+		{
+			name: "docker hub with password",
+			args: []string{"--status"},
+			stored: []configtypes.AuthConfig{
+				{ServerAddress: registry.IndexServer, Username: "my-username", Password: "a-password"},
+			},
+			expectedOut:    []string{"Server:           https://index.docker.io/v1/\n"},
+			notExpectedOut: []string{"Login method"},
+		},
+		{
+			name: "docker hub with web-based login",
+			args: []string{"--status"},
+			stored: []configtypes.AuthConfig{
+				{ServerAddress: registry.IndexServer, Username: "my-username", Password: "a-pat"},
+				{ServerAddress: registry.IndexServer + "access-token", Username: "my-username", Password: accessToken},
+				{ServerAddress: registry.IndexServer + "refresh-token", Username: "my-username", Password: "a-refresh-token..client-id"},
+			},
+			expectedOut: []string{
+				"Username:         my-username\n",
+				"Login method:     web-based login\n",
+				"Email:            me@example.com\n",
+				"(in 3 hours)\n",
+				"Scopes:           openid offline_access\n",
+			},
+			notExpectedOut: []string{"a-pat", accessToken},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+			for _, ac := range tc.stored {
+				assert.NilError(t, cfg.GetCredentialsStore(ac.ServerAddress).Store(ac))
+			}
+			cli.SetConfigFile(cfg)
+
+			cmd := newLoginCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			for _, s := range tc.expectedOut {
+				assert.Check(t, is.Contains(cli.OutBuffer().String(), s))
+			}
+			for _, s := range tc.notExpectedOut {
+				assert.Check(t, !strings.Contains(cli.OutBuffer().String(), s), "unexpected %q in output", s)
+			}
+		})
+	}
+}
+
+func TestFormatTokenExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Check(t, is.Equal(formatTokenExpiry(now.Add(30*time.Minute), now), "2025-01-02T03:34:05Z (in 30 minutes)"))
+	assert.Check(t, is.Equal(formatTokenExpiry(now.Add(-time.Second), now), "2025-01-02T03:04:04Z (expired)"))
+}
@@ -2,11 +2,16 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/internal/commands"
 	"github.com/docker/cli/internal/oauth/manager"
 	"github.com/docker/cli/internal/registry"
@@ -19,12 +24,20 @@ func init() {
 
 // newLogoutCommand creates a new `docker logout` command
 func newLogoutCommand(dockerCLI command.Cli) *cobra.Command {
+	var all bool
+
 	cmd := &cobra.Command{
-		Use:   "logout [SERVER]",
+		Use:   "logout [OPTIONS] [SERVER]",
 		Short: "Log out from a registry",
 		Long:  "Log out from a registry.\nIf no server is specified, the default is defined by the daemon.",
 		Args:  cli.RequiresMaxArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all {
+				if len(args) > 0 {
+					return errors.New("conflicting options: cannot specify a server with --all")
+				}
+				return runLogoutAll(cmd.Context(), dockerCLI)
+			}
 			var serverAddress string
 			if len(args) > 0 {
 				serverAddress = args[0]
@@ -34,10 +47,20 @@ func newLogoutCommand(dockerCLI command.Cli) *cobra.Command {
 		Annotations: map[string]string{
 			"category-top": "9",
 		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			auths, err := dockerCLI.ConfigFile().GetAllCredentials()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return slices.Sorted(maps.Keys(auths)), cobra.ShellCompDirectiveNoFileComp
+		},
 		DisableFlagsInUseLine: true,
-		// TODO (thaJeztah) add completion for registries we have authentication stored for
 	}
 
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Log out from all registries with stored credentials")
 	return cmd
 }
 
@@ -87,3 +110,45 @@ func runLogout(ctx context.Context, dockerCLI command.Cli, serverAddress string)
 
 	return nil
 }
+
+// runLogoutAll removes the credentials of all registries from the
+// credential stores they are stored in. Credentials that are provided
+// through DOCKER_AUTH_CONFIG cannot be removed, and are skipped.
+func runLogoutAll(ctx context.Context, dockerCLI command.Cli) error {
+	maybePrintEnvAuthWarning(dockerCLI)
+
+	cfg := dockerCLI.ConfigFile()
+	auths, err := cfg.GetAllCredentials()
+	if err != nil {
+		return err
+	}
+	var oauthTokens bool
+	maps.DeleteFunc(auths, func(r string, _ configtypes.AuthConfig) bool {
+		if cfg.GetCredentialsStoreName(r) == configfile.CredentialsStoreEnv {
+			return true
+		}
+		// the OAuth tokens of Docker Hub are removed by the OAuth manager.
+		if manager.IsTokenKey(r) {
+			oauthTokens = true
+			return true
+		}
+		return false
+	})
+	if _, ok := auths[registry.IndexServer]; ok || oauthTokens {
+		if err := manager.NewManager(cfg.GetCredentialsStore(registry.IndexServer)).Logout(ctx); err != nil {
+			_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", err)
+		}
+	}
+
+	var errs []error
+	for _, r := range slices.Sorted(maps.Keys(auths)) {
+		_, _ = fmt.Fprintln(dockerCLI.Out(), "Removing login credentials for", r)
+		if err := cfg.GetCredentialsStore(r).Erase(r); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("could not erase credentials: %w", errors.Join(errs...))
+	}
+	return nil
+}
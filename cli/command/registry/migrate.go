@@ -0,0 +1,220 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/spf13/cobra"
+)
+
+// newNativeStore is a variable for unit testing.
+var newNativeStore = func(configFile *configfile.ConfigFile, helperSuffix string) credentials.Store {
+	return credentials.NewNativeStore(unsavedConfig{configFile}, helperSuffix)
+}
+
+// unsavedConfig is a configuration file that's not saved by the credential
+// store. A native store saves the configuration file for each entry it
+// stores, which would write scrubbed entries before the credential store is
+// configured; the migration saves the configuration file once instead.
+type unsavedConfig struct {
+	*configfile.ConfigFile
+}
+
+// Save does not save the configuration file.
+func (unsavedConfig) Save() error {
+	return nil
+}
+
+type credentialsMigrateOptions struct {
+	helper string
+	dryRun bool
+}
+
+// newCredentialsMigrateCommand creates a new `docker credentials migrate` command
+func newCredentialsMigrateCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts credentialsMigrateOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS]",
+		Short: "Move credentials stored in the configuration file to a credential helper",
+		Long:  credentialsMigrateDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCredentialsMigrate(dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.helper, "helper", "", `Credential helper to migrate to (for example, "osxkeychain"); defaults to the configured "credsStore"`)
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Show the credentials that would be migrated, without migrating them")
+	return cmd
+}
+
+var credentialsMigrateDescription = `
+Move credentials that are stored in plain text in the configuration file to a
+credential helper. Credentials for registries that have a registry-specific
+helper configured ("credHelpers") are moved to that helper; other credentials
+are moved to the credential store ("credsStore"), which is configured if not
+set.
+
+Each entry is read back from the helper to verify it was stored correctly
+before the plain text credentials are removed from the configuration file.
+If the helper fails, all changes are rolled back.
+`
+
+// migration describes the migration of the credentials for a registry.
+type migration struct {
+	registry string
+	helper   string
+	auth     configtypes.AuthConfig
+	store    credentials.Store
+	// previous holds the credentials that were stored in the helper before
+	// the migration, if any, to restore on rollback.
+	previous *configtypes.AuthConfig
+}
+
+func runCredentialsMigrate(dockerCLI command.Cli, opts credentialsMigrateOptions) error {
+	cfg := dockerCLI.ConfigFile()
+
+	helper := cfg.CredentialsStore
+	if opts.helper != "" {
+		if helper != "" && helper != opts.helper {
+			return fmt.Errorf(`cannot migrate to %q: credential store is already configured as %q`, opts.helper, helper)
+		}
+		helper = opts.helper
+	}
+
+	var migrations []*migration
+	for _, r := range slices.Sorted(maps.Keys(cfg.GetAuthConfigs())) {
+		auth := cfg.AuthConfigs[r]
+		if auth.Username == "" && auth.Password == "" && auth.IdentityToken == "" {
+			// nothing to migrate; the entry is already scrubbed.
+			continue
+		}
+		if auth.RegistryToken != "" {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Skipping %s: registry tokens cannot be stored in a credential helper\n", r)
+			continue
+		}
+		h := helper
+		if ch, ok := cfg.CredentialHelpers[r]; ok {
+			h = ch
+		}
+		if h == "" {
+			return errors.New(`no credential helper configured: use the --helper option, or set "credsStore" in the configuration file`)
+		}
+		auth.ServerAddress = r
+		migrations = append(migrations, &migration{registry: r, helper: h, auth: auth})
+	}
+
+	if len(migrations) == 0 {
+		_, _ = fmt.Fprintln(dockerCLI.Out(), "No credentials to migrate")
+		return nil
+	}
+	if opts.dryRun {
+		for _, m := range migrations {
+			_, _ = fmt.Fprintf(dockerCLI.Out(), "Would migrate credentials for %s (%s) to %s\n", m.registry, describeAuth(m.auth), m.helper)
+		}
+		if cfg.CredentialsStore == "" && helper != "" {
+			_, _ = fmt.Fprintf(dockerCLI.Out(), "Would set credential store to %s\n", helper)
+		}
+		return nil
+	}
+
+	// Keep a copy of the original entries, as storing credentials in a
+	// native store scrubs the entry in the configuration file.
+	original := maps.Clone(cfg.AuthConfigs)
+	originalStore := cfg.CredentialsStore
+
+	var done []*migration
+	for _, m := range migrations {
+		m.store = newNativeStore(cfg, m.helper)
+		if err := migrateCredentials(m); err != nil {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Failed to migrate credentials for %s: %v\n", m.registry, err)
+			return rollbackMigration(dockerCLI, cfg, append(done, m), original, originalStore, err)
+		}
+		done = append(done, m)
+
+		// Scrub the plain text credentials, but preserve other fields.
+		scrubbed := original[m.registry]
+		scrubbed.Username = ""
+		scrubbed.Password = ""
+		scrubbed.IdentityToken = ""
+		cfg.AuthConfigs[m.registry] = scrubbed
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Migrated credentials for %s to %s\n", m.registry, m.helper)
+	}
+
+	if cfg.CredentialsStore == "" {
+		cfg.CredentialsStore = helper
+	}
+	if err := cfg.Save(); err != nil {
+		return rollbackMigration(dockerCLI, cfg, done, original, originalStore, err)
+	}
+	return nil
+}
+
+// migrateCredentials stores the credentials in the helper, and reads them
+// back to verify they were stored.
+func migrateCredentials(m *migration) error {
+	if prev, err := m.store.Get(m.registry); err == nil && (prev.Username != "" || prev.Password != "" || prev.IdentityToken != "") {
+		m.previous = &prev
+	}
+	if err := m.store.Store(m.auth); err != nil {
+		return err
+	}
+	stored, err := m.store.Get(m.registry)
+	if err != nil {
+		return err
+	}
+	if m.auth.IdentityToken != "" {
+		if stored.IdentityToken != m.auth.IdentityToken {
+			return errors.New("verification failed: stored identity token does not match")
+		}
+		return nil
+	}
+	if stored.Username != m.auth.Username || stored.Password != m.auth.Password {
+		return errors.New("verification failed: stored credentials do not match")
+	}
+	return nil
+}
+
+// rollbackMigration restores the credentials in the helpers and the
+// configuration file to their state before the migration.
+func rollbackMigration(dockerCLI command.Cli, cfg *configfile.ConfigFile, done []*migration, original map[string]configtypes.AuthConfig, originalStore string, cause error) error {
+	errs := []error{cause}
+	for _, m := range slices.Backward(done) {
+		var err error
+		if m.previous != nil {
+			err = m.store.Store(*m.previous)
+		} else {
+			err = m.store.Erase(m.registry)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore credentials for %s in %s: %w", m.registry, m.helper, err))
+		}
+	}
+	cfg.AuthConfigs = original
+	cfg.CredentialsStore = originalStore
+	if err := cfg.Save(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to restore configuration file: %w", err))
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Err(), "Migration rolled back")
+	return errors.Join(errs...)
+}
+
+// describeAuth returns a description of the credentials that does not
+// include secrets.
+func describeAuth(auth configtypes.AuthConfig) string {
+	if auth.IdentityToken != "" {
+		return "identity token"
+	}
+	return "username: " + auth.Username
+}
@@ -0,0 +1,201 @@
+package registry
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+// fakeHelperStore is an in-memory credential helper. Storing credentials
+// for failAddress fails.
+type fakeHelperStore struct {
+	creds       map[string]configtypes.AuthConfig
+	failAddress string
+}
+
+func (s *fakeHelperStore) Erase(serverAddress string) error {
+	delete(s.creds, serverAddress)
+	return nil
+}
+
+func (s *fakeHelperStore) Get(serverAddress string) (configtypes.AuthConfig, error) {
+	return s.creds[serverAddress], nil
+}
+
+func (s *fakeHelperStore) GetAll() (map[string]configtypes.AuthConfig, error) {
+	return s.creds, nil
+}
+
+func (s *fakeHelperStore) Store(authConfig configtypes.AuthConfig) error {
+	if authConfig.ServerAddress == s.failAddress {
+		return errors.New("helper crashed")
+	}
+	s.creds[authConfig.ServerAddress] = authConfig
+	return nil
+}
+
+func setupMigrateTest(t *testing.T, failAddress string) (*test.FakeCli, map[string]*fakeHelperStore) {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	assert.NilError(t, os.WriteFile(configFile, []byte(`{
+	"auths": {
+		"a.example.com": {"auth": "YWxpY2U6c2VjcmV0"},
+		"b.example.com": {"identitytoken": "b-token"},
+		"c.example.com": {"auth": "Y2Fyb2w6cGFzc3dvcmQ="},
+		"d.example.com": {}
+	},
+	"credHelpers": {
+		"c.example.com": "other"
+	}
+}`), 0o600))
+	cfg := loadConfigFile(t, configFile)
+
+	helpers := map[string]*fakeHelperStore{
+		"test":  {creds: map[string]configtypes.AuthConfig{}, failAddress: failAddress},
+		"other": {creds: map[string]configtypes.AuthConfig{"c.example.com": {ServerAddress: "c.example.com", Username: "old", Password: "old"}}},
+	}
+	orig := newNativeStore
+	newNativeStore = func(_ *configfile.ConfigFile, helperSuffix string) credentials.Store {
+		return helpers[helperSuffix]
+	}
+	t.Cleanup(func() { newNativeStore = orig })
+
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+	return cli, helpers
+}
+
+func loadConfigFile(t *testing.T, filename string) *configfile.ConfigFile {
+	t.Helper()
+	f, err := os.Open(filename)
+	assert.NilError(t, err)
+	defer f.Close()
+	cfg := configfile.New(filename)
+	assert.NilError(t, cfg.LoadFromReader(f))
+	return cfg
+}
+
+func TestCredentialsMigrate(t *testing.T) {
+	cli, helpers := setupMigrateTest(t, "")
+	cmd := newCredentialsMigrateCommand(cli)
+	cmd.SetArgs([]string{"--helper", "test"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Migrated credentials for a.example.com to test
+Migrated credentials for b.example.com to test
+Migrated credentials for c.example.com to other
+`))
+	assert.Check(t, is.DeepEqual(helpers["test"].creds, map[string]configtypes.AuthConfig{
+		"a.example.com": {ServerAddress: "a.example.com", Username: "alice", Password: "secret"},
+		"b.example.com": {ServerAddress: "b.example.com", IdentityToken: "b-token"},
+	}))
+	assert.Check(t, is.DeepEqual(helpers["other"].creds, map[string]configtypes.AuthConfig{
+		"c.example.com": {ServerAddress: "c.example.com", Username: "carol", Password: "password"},
+	}))
+
+	saved := loadConfigFile(t, cli.ConfigFile().Filename)
+	assert.Check(t, is.Equal(saved.CredentialsStore, "test"))
+	for r, a := range saved.AuthConfigs {
+		assert.Check(t, is.DeepEqual(a, configtypes.AuthConfig{ServerAddress: r}))
+	}
+	content, err := os.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	assert.Check(t, !is.Contains(string(content), "YWxpY2U6c2VjcmV0")().Success())
+	assert.Check(t, !is.Contains(string(content), "b-token")().Success())
+}
+
+func TestCredentialsMigrateDryRun(t *testing.T) {
+	cli, helpers := setupMigrateTest(t, "")
+	before, err := os.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+
+	cmd := newCredentialsMigrateCommand(cli)
+	cmd.SetArgs([]string{"--helper", "test", "--dry-run"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Would migrate credentials for a.example.com (username: alice) to test
+Would migrate credentials for b.example.com (identity token) to test
+Would migrate credentials for c.example.com (username: carol) to other
+Would set credential store to test
+`))
+	assert.Check(t, is.Len(helpers["test"].creds, 0))
+	after, err := os.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(after), string(before)))
+}
+
+func TestCredentialsMigrateRollback(t *testing.T) {
+	cli, helpers := setupMigrateTest(t, "c.example.com")
+	helpers["other"].failAddress = "c.example.com"
+
+	cmd := newCredentialsMigrateCommand(cli)
+	cmd.SetArgs([]string{"--helper", "test"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "helper crashed")
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Failed to migrate credentials for c.example.com: helper crashed\nMigration rolled back\n"))
+
+	assert.Check(t, is.Len(helpers["test"].creds, 0))
+	assert.Check(t, is.DeepEqual(helpers["other"].creds, map[string]configtypes.AuthConfig{
+		"c.example.com": {ServerAddress: "c.example.com", Username: "old", Password: "old"},
+	}))
+	saved := loadConfigFile(t, cli.ConfigFile().Filename)
+	assert.Check(t, is.Equal(saved.CredentialsStore, ""))
+	assert.Check(t, is.DeepEqual(saved.AuthConfigs["a.example.com"], configtypes.AuthConfig{ServerAddress: "a.example.com", Username: "alice", Password: "secret"}))
+	assert.Check(t, is.DeepEqual(saved.AuthConfigs["b.example.com"], configtypes.AuthConfig{ServerAddress: "b.example.com", IdentityToken: "b-token"}))
+}
+
+func TestCredentialsMigrateErrors(t *testing.T) {
+	t.Run("no helper", func(t *testing.T) {
+		cli, _ := setupMigrateTest(t, "")
+		cmd := newCredentialsMigrateCommand(cli)
+		cmd.SetArgs([]string{})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.ErrorContains(t, cmd.Execute(), "no credential helper configured")
+	})
+	t.Run("conflicting helper", func(t *testing.T) {
+		cli, _ := setupMigrateTest(t, "")
+		cli.ConfigFile().CredentialsStore = "test"
+		cmd := newCredentialsMigrateCommand(cli)
+		cmd.SetArgs([]string{"--helper", "other"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.ErrorContains(t, cmd.Execute(), `cannot migrate to "other": credential store is already configured as "test"`)
+	})
+}
+
+func TestNativeStoreDoesNotSaveConfig(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test uses a POSIX shell")
+	binDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(binDir, "docker-credential-test"), []byte("#!/bin/sh\ncat > /dev/null\n"), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	const content = `{"auths": {"a.example.com": {"auth": "YWxpY2U6c2VjcmV0"}}}`
+	assert.NilError(t, os.WriteFile(configFile, []byte(content), 0o600))
+	cfg := loadConfigFile(t, configFile)
+
+	// storing credentials must not save the configuration file before the
+	// credential store is configured.
+	s := newNativeStore(cfg, "test")
+	assert.NilError(t, s.Store(configtypes.AuthConfig{ServerAddress: "a.example.com", Username: "alice", Password: "secret"}))
+	data, err := os.ReadFile(configFile)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), content))
+}
@@ -0,0 +1,262 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/prompt"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/cli/opts"
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+type rmOptions struct {
+	refs     []string
+	filter   opts.FilterOpt
+	force    bool
+	dryRun   bool
+	insecure bool
+}
+
+// newRmCommand creates a new `docker registry rm` command
+func newRmCommand(dockerCLI command.Cli) *cobra.Command {
+	options := rmOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:     "rm [OPTIONS] REFERENCE [REFERENCE...]",
+		Aliases: []string{"remove"},
+		Short:   "Delete image manifests from a registry",
+		Long:    rmDescription,
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.refs = args
+			return runRm(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.Var(&options.filter, "filter", `Delete the tags of the given repositories that match the filter (e.g. "name=ci-*", "until=720h")`)
+	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the manifests that would be deleted, without deleting them")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+var rmDescription = `
+Delete image manifests from a registry. Tags are resolved to the digest of
+the manifest they refer to, and the manifest is deleted by digest. Deleting
+a manifest removes all tags that refer to it.
+
+When using --filter, the arguments are repositories, and all tags of those
+repositories that match the filter are deleted.
+`
+
+// rmCandidate is a tag, or digest, that is to be deleted.
+type rmCandidate struct {
+	ref     reference.Named
+	digest  digest.Digest
+	created time.Time
+}
+
+func runRm(ctx context.Context, dockerCLI command.Cli, options rmOptions) error {
+	registryClient := newRegistryClient(dockerCLI, options.insecure)
+
+	var (
+		candidates []rmCandidate
+		err        error
+	)
+	if len(options.filter.Value()) > 0 {
+		candidates, err = filterCandidates(ctx, dockerCLI.Err(), registryClient, options.refs, options.filter)
+	} else {
+		candidates, err = refCandidates(ctx, registryClient, options.refs)
+	}
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		_, _ = fmt.Fprintln(dockerCLI.Out(), "No matching tags found")
+		return nil
+	}
+
+	manifests := make(map[string]reference.Canonical)
+	_, _ = fmt.Fprintln(dockerCLI.Out(), "The following manifests will be deleted:")
+	for _, c := range candidates {
+		line := "  " + reference.FamiliarString(c.ref)
+		if _, ok := c.ref.(reference.Canonical); !ok {
+			line += " (" + c.digest.String() + ")"
+		}
+		if !c.created.IsZero() {
+			line += ", created " + units.HumanDuration(time.Since(c.created)) + " ago"
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Out(), line)
+
+		canonical, err := reference.WithDigest(reference.TrimNamed(c.ref), c.digest)
+		if err != nil {
+			return err
+		}
+		manifests[canonical.String()] = canonical
+	}
+	if options.dryRun {
+		return nil
+	}
+
+	if !options.force {
+		r, err := prompt.Confirm(ctx, dockerCLI.In(), dockerCLI.Out(), fmt.Sprintf(rmWarning, len(manifests)))
+		if err != nil {
+			return err
+		}
+		if !r {
+			return cancelledErr{errors.New("registry rm has been cancelled")}
+		}
+	}
+
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		canonical := manifests[name]
+		if err := registryClient.DeleteManifest(ctx, canonical); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Out(), "Deleted:", reference.FamiliarString(canonical))
+	}
+	return errors.Join(errs...)
+}
+
+const rmWarning = `WARNING! This will delete %d manifest(s), including all tags that refer to them.
+Are you sure you want to continue?`
+
+type cancelledErr struct{ error }
+
+func (cancelledErr) Cancelled() {}
+
+// refCandidates resolves the given tagged or digested references.
+func refCandidates(ctx context.Context, registryClient registryclient.RegistryClient, refs []string) ([]rmCandidate, error) {
+	candidates := make([]rmCandidate, 0, len(refs))
+	for _, r := range refs {
+		named, err := reference.ParseNormalizedNamed(r)
+		if err != nil {
+			return nil, err
+		}
+		if reference.IsNameOnly(named) {
+			return nil, fmt.Errorf("invalid reference (%s): must contain a tag or digest, or use --filter to select tags", r)
+		}
+		dgst, err := registryClient.GetManifestDigest(ctx, named)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, rmCandidate{ref: named, digest: dgst})
+	}
+	return candidates, nil
+}
+
+// filterCandidates returns the tags of the given repositories matching
+// the "name" and "until" filters. Tags that refer to the same manifest as a
+// tag that does not match the filters are skipped, as deleting the manifest
+// would also remove that tag.
+func filterCandidates(ctx context.Context, stderr io.Writer, registryClient registryclient.RegistryClient, repos []string, filter opts.FilterOpt) ([]rmCandidate, error) {
+	match, err := nameFilter(filter.Value(), "until")
+	if err != nil {
+		return nil, err
+	}
+	var until time.Time
+	if values := filter.Value()["until"]; len(values) > 0 {
+		if len(values) > 1 {
+			return nil, errdefs.ErrInvalidArgument.WithMessage("more than one until filter specified")
+		}
+		for v := range values {
+			until, err = parseUntil(v, time.Now())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var candidates []rmCandidate
+	for _, repo := range repos {
+		named, err := reference.ParseNormalizedNamed(repo)
+		if err != nil {
+			return nil, err
+		}
+		if !reference.IsNameOnly(named) {
+			return nil, fmt.Errorf("invalid repository name (%s): must not contain a tag or digest when using --filter", repo)
+		}
+		tags, err := registryClient.ListTags(ctx, named)
+		if err != nil {
+			return nil, err
+		}
+		var selected []rmCandidate
+		retained := make(map[digest.Digest][]string)
+		for _, tag := range tags {
+			tagged, err := reference.WithTag(named, tag)
+			if err != nil {
+				return nil, err
+			}
+			dgst, err := registryClient.GetManifestDigest(ctx, tagged)
+			if err != nil {
+				return nil, err
+			}
+			if !match(tag) {
+				retained[dgst] = append(retained[dgst], tag)
+				continue
+			}
+			var created time.Time
+			if !until.IsZero() {
+				created, err = registryClient.GetImageCreated(ctx, tagged)
+				if err != nil {
+					return nil, err
+				}
+				if created.IsZero() || !created.Before(until) {
+					retained[dgst] = append(retained[dgst], tag)
+					continue
+				}
+			}
+			selected = append(selected, rmCandidate{ref: tagged, digest: dgst, created: created})
+		}
+		for _, c := range selected {
+			if other := retained[c.digest]; len(other) > 0 {
+				_, _ = fmt.Fprintf(stderr, "Skipping %s: manifest is also referenced by %s\n", reference.FamiliarString(c.ref), strings.Join(other, ", "))
+				continue
+			}
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+// parseUntil parses the value of the "until" filter, which is either a
+// duration relative to now, a RFC 3339 date or timestamp, or a Unix
+// timestamp.
+func parseUntil(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Time{}, errdefs.ErrInvalidArgument.WithMessage("invalid until filter '" + value + "': must be a duration, RFC 3339 timestamp or Unix timestamp")
+}
@@ -0,0 +1,231 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+var (
+	digestOld = digest.FromString("old")
+	digestNew = digest.FromString("new")
+	digestRel = digest.FromString("release")
+)
+
+// newRmTestClient returns a fake registry client for a repository with two
+// old CI tags referring to the same manifest, a new CI tag, and an old CI
+// tag referring to the same manifest as a release tag. Deleted manifests are
+// recorded in deleted.
+func newRmTestClient(t *testing.T, deleted *[]string) *fakeRegistryClient {
+	t.Helper()
+	digests := map[string]digest.Digest{
+		"ci-1":  digestOld,
+		"ci-2":  digestOld,
+		"ci-3":  digestNew,
+		"ci-0":  digestRel,
+		"1.0.0": digestRel,
+	}
+	return &fakeRegistryClient{
+		listTagsFunc: func(_ context.Context, ref reference.Named) ([]string, error) {
+			assert.Check(t, is.Equal(ref.String(), "example.com/app"))
+			return []string{"1.0.0", "ci-0", "ci-1", "ci-2", "ci-3"}, nil
+		},
+		getManifestDigestFunc: func(_ context.Context, ref reference.Named) (digest.Digest, error) {
+			if digested, ok := ref.(reference.Canonical); ok {
+				return digested.Digest(), nil
+			}
+			dgst, ok := digests[ref.(reference.Tagged).Tag()]
+			if !ok {
+				return "", errors.New("manifest unknown")
+			}
+			return dgst, nil
+		},
+		getImageCreatedFunc: func(_ context.Context, ref reference.Named) (time.Time, error) {
+			if digests[ref.(reference.Tagged).Tag()] != digestNew {
+				return time.Now().Add(-72 * time.Hour), nil
+			}
+			return time.Now().Add(-time.Hour), nil
+		},
+		deleteManifestFunc: func(_ context.Context, ref reference.Canonical) error {
+			*deleted = append(*deleted, ref.String())
+			return nil
+		},
+	}
+}
+
+func TestRegistryRm(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		golden   string
+		expected []string
+	}{
+		{
+			name:     "tag",
+			args:     []string{"--force", "example.com/app:ci-3"},
+			golden:   "rm-tag.golden",
+			expected: []string{"example.com/app@" + digestNew.String()},
+		},
+		{
+			name:     "digest",
+			args:     []string{"--force", "example.com/app@" + digestNew.String()},
+			golden:   "rm-digest.golden",
+			expected: []string{"example.com/app@" + digestNew.String()},
+		},
+		{
+			name:   "filter",
+			args:   []string{"--force", "--filter", "name=ci-*", "--filter", "until=24h", "example.com/app"},
+			golden: "rm-filter.golden",
+			// ci-1 and ci-2 refer to the same manifest, which is deleted once.
+			expected: []string{"example.com/app@" + digestOld.String()},
+		},
+		{
+			name:   "dry-run",
+			args:   []string{"--dry-run", "--filter", "name=ci-*", "example.com/app"},
+			golden: "rm-dry-run.golden",
+		},
+		{
+			name:   "no-match",
+			args:   []string{"--filter", "name=nightly-*", "example.com/app"},
+			golden: "rm-no-match.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var deleted []string
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(newRmTestClient(t, &deleted))
+			cmd := newRmCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.DeepEqual(deleted, tc.expected))
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestRegistryRmSharedManifest(t *testing.T) {
+	var deleted []string
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(newRmTestClient(t, &deleted))
+	cmd := newRmCommand(cli)
+	cmd.SetArgs([]string{"--force", "--filter", "name=ci-*", "example.com/app"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Skipping example.com/app:ci-0: manifest is also referenced by 1.0.0\n"))
+	assert.Check(t, is.DeepEqual(deleted, []string{
+		"example.com/app@" + digestNew.String(),
+		"example.com/app@" + digestOld.String(),
+	}))
+}
+
+func TestRegistryRmPrompt(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected []string
+	}{
+		{input: "y\n", expected: []string{"example.com/app@" + digestNew.String()}},
+		{input: "n\n"},
+	} {
+		t.Run(strings.TrimSpace(tc.input), func(t *testing.T) {
+			var deleted []string
+			cli := test.NewFakeCli(nil)
+			cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(tc.input))))
+			cli.SetRegistryClient(newRmTestClient(t, &deleted))
+			cmd := newRmCommand(cli)
+			cmd.SetArgs([]string{"example.com/app:ci-3"})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			err := cmd.Execute()
+			if tc.expected == nil {
+				assert.Check(t, is.ErrorContains(err, "registry rm has been cancelled"))
+			} else {
+				assert.Check(t, err)
+			}
+			assert.Check(t, is.DeepEqual(deleted, tc.expected))
+			assert.Check(t, is.Contains(cli.OutBuffer().String(), "WARNING! This will delete 1 manifest(s)"))
+		})
+	}
+}
+
+func TestRegistryRmErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "no-args",
+			args:          []string{},
+			expectedError: "requires at least 1 argument",
+		},
+		{
+			name:          "name-only",
+			args:          []string{"example.com/app"},
+			expectedError: "invalid reference (example.com/app): must contain a tag or digest",
+		},
+		{
+			name:          "tag-with-filter",
+			args:          []string{"--filter", "name=ci-*", "example.com/app:ci-1"},
+			expectedError: "must not contain a tag or digest when using --filter",
+		},
+		{
+			name:          "invalid-filter",
+			args:          []string{"--filter", "label=foo", "example.com/app"},
+			expectedError: "invalid filter 'label'",
+		},
+		{
+			name:          "invalid-until",
+			args:          []string{"--filter", "until=last-week", "example.com/app"},
+			expectedError: "invalid until filter 'last-week'",
+		},
+		{
+			name:          "unknown-tag",
+			args:          []string{"example.com/app:no-such-tag"},
+			expectedError: "manifest unknown",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var deleted []string
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(newRmTestClient(t, &deleted))
+			cmd := newRmCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+			assert.Check(t, is.Len(deleted, 0))
+		})
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "24h", expected: now.Add(-24 * time.Hour)},
+		{value: "2025-05-01T00:00:00Z", expected: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "1748779200", expected: time.Unix(1748779200, 0)},
+	} {
+		actual, err := parseUntil(tc.value, now)
+		assert.Check(t, err)
+		assert.Check(t, actual.Equal(tc.expected), "%s: expected %s, got %s", tc.value, tc.expected, actual)
+	}
+}
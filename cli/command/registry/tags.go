@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/opts"
+	"github.com/spf13/cobra"
+)
+
+type tagsOptions struct {
+	repository string
+	format     string
+	filter     opts.FilterOpt
+	insecure   bool
+}
+
+// newTagsCommand creates a new `docker registry tags` command
+func newTagsCommand(dockerCLI command.Cli) *cobra.Command {
+	options := tagsOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "tags [OPTIONS] REPOSITORY",
+		Short: "List the tags of a repository in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.repository = args[0]
+			return runTags(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&options.filter, "filter", "f", `Filter output based on conditions provided (e.g. "name=v1.*")`)
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runTags(ctx context.Context, dockerCLI command.Cli, options tagsOptions) error {
+	match, err := nameFilter(options.filter.Value())
+	if err != nil {
+		return err
+	}
+	named, err := reference.ParseNormalizedNamed(options.repository)
+	if err != nil {
+		return err
+	}
+	if !reference.IsNameOnly(named) {
+		return fmt.Errorf("invalid repository name (%s): must not contain a tag or digest", options.repository)
+	}
+
+	tags, err := newRegistryClient(dockerCLI, options.insecure).ListTags(ctx, named)
+	if err != nil {
+		return err
+	}
+	filtered := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if match(tag) {
+			filtered = append(filtered, tag)
+		}
+	}
+
+	tagsCtx := formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newTagsFormat(options.format),
+	}
+	return formatTagsWrite(tagsCtx, reference.FamiliarName(named), filtered)
+}
@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/cli/internal/test"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	listTagsFunc          func(ctx context.Context, ref reference.Named) ([]string, error)
+	listRepositoriesFunc  func(ctx context.Context, domain string) ([]string, error)
+	getManifestDigestFunc func(ctx context.Context, ref reference.Named) (digest.Digest, error)
+	getImageCreatedFunc   func(ctx context.Context, ref reference.Named) (time.Time, error)
+	deleteManifestFunc    func(ctx context.Context, ref reference.Canonical) error
+}
+
+func (c *fakeRegistryClient) ListTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	if c.listTagsFunc != nil {
+		return c.listTagsFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) ListRepositories(ctx context.Context, domain string) ([]string, error) {
+	if c.listRepositoriesFunc != nil {
+		return c.listRepositoriesFunc(ctx, domain)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) GetManifestDigest(ctx context.Context, ref reference.Named) (digest.Digest, error) {
+	if c.getManifestDigestFunc != nil {
+		return c.getManifestDigestFunc(ctx, ref)
+	}
+	return "", nil
+}
+
+func (c *fakeRegistryClient) GetImageCreated(ctx context.Context, ref reference.Named) (time.Time, error) {
+	if c.getImageCreatedFunc != nil {
+		return c.getImageCreatedFunc(ctx, ref)
+	}
+	return time.Time{}, nil
+}
+
+func (c *fakeRegistryClient) DeleteManifest(ctx context.Context, ref reference.Canonical) error {
+	if c.deleteManifestFunc != nil {
+		return c.deleteManifestFunc(ctx, ref)
+	}
+	return nil
+}
+
+func TestRegistryTags(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "table",
+			args:   []string{"example.com/alpine"},
+			golden: "tags.golden",
+		},
+		{
+			name:   "filter",
+			args:   []string{"--filter", "name=3.*", "--filter", "name=edge", "example.com/alpine"},
+			golden: "tags-filter.golden",
+		},
+		{
+			name:   "json",
+			args:   []string{"--format", "json", "--filter", "name=3.*", "example.com/alpine"},
+			golden: "tags-json.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{
+				listTagsFunc: func(_ context.Context, ref reference.Named) ([]string, error) {
+					assert.Check(t, is.Equal(ref.String(), "example.com/alpine"))
+					return []string{"3.20", "3.21", "edge", "latest"}, nil
+				},
+			})
+			cmd := newTagsCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestRegistryTagsErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{},
+			expectedError: "requires 1 argument",
+		},
+		{
+			name:          "tagged",
+			args:          []string{"alpine:latest"},
+			expectedError: "invalid repository name (alpine:latest): must not contain a tag or digest",
+		},
+		{
+			name:          "invalid-filter",
+			args:          []string{"--filter", "label=foo", "alpine"},
+			expectedError: "invalid filter 'label'",
+		},
+		{
+			name:          "invalid-pattern",
+			args:          []string{"--filter", "name=[", "alpine"},
+			expectedError: "invalid name filter '['",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(nil)
+			cli.SetRegistryClient(&fakeRegistryClient{})
+			cmd := newTagsCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
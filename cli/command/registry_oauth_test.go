@@ -0,0 +1,192 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/oauth"
+	"github.com/docker/cli/internal/oauth/manager"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	registrytypes "github.com/moby/moby/api/types/registry"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newTestOAuthServer starts a stand-in OAuth tenant that hands out the given
+// access token and PAT, and configures newOAuthManager to use it.
+func newTestOAuthServer(t *testing.T, accessToken, pat string) *int {
+	t.Helper()
+	var refreshes int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			assert.Check(t, is.Equal(r.FormValue("grant_type"), "refresh_token"))
+			assert.Check(t, is.Equal(r.FormValue("refresh_token"), "a-refresh-token"))
+			refreshes++
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  accessToken,
+				"refresh_token": "a-new-refresh-token",
+				"expires_in":    3600,
+			})
+		case "/v2/access-tokens/desktop-generate":
+			assert.Check(t, is.Equal(r.Header.Get("Authorization"), "Bearer "+accessToken))
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"token": pat}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := newOAuthManager
+	t.Cleanup(func() { newOAuthManager = orig })
+	newOAuthManager = func(store credentials.Store) *manager.OAuthManager {
+		return manager.New(manager.OAuthManagerOptions{
+			Store:    store,
+			Audience: srv.URL,
+			ClientID: "client-id",
+			Tenant:   srv.URL,
+		})
+	}
+	return &refreshes
+}
+
+func newTestAccessToken(t *testing.T, expiry time.Time) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("a-signing-key-of-sufficient-length")}, nil)
+	assert.NilError(t, err)
+	token, err := jwt.Signed(signer).Claims(oauth.Claims{
+		Claims: jwt.Claims{Expiry: jwt.NewNumericDate(expiry)},
+		Domain: oauth.DomainClaims{Username: "bork"},
+	}).Serialize()
+	assert.NilError(t, err)
+	return token
+}
+
+func newTestOAuthConfig(t *testing.T, accessToken string) *configfile.ConfigFile {
+	t.Helper()
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	store := cfg.GetCredentialsStore(authConfigKey)
+	for _, ac := range []configtypes.AuthConfig{
+		{ServerAddress: authConfigKey, Username: "bork", Password: "an-old-pat"},
+		{ServerAddress: authConfigKey + "access-token", Username: "bork", Password: accessToken},
+		{ServerAddress: authConfigKey + "refresh-token", Username: "bork", Password: "a-refresh-token..client-id"},
+	} {
+		assert.NilError(t, store.Store(ac))
+	}
+	return cfg
+}
+
+// setTestOAuthRefreshContext sets the context and error stream to refresh
+// the OAuth tokens for the configuration file, and returns the error stream.
+func setTestOAuthRefreshContext(t *testing.T, ctx context.Context, cfg *configfile.ConfigFile) *bytes.Buffer {
+	t.Helper()
+	errBuf := new(bytes.Buffer)
+	setOAuthRefreshContext(ctx, cfg, errBuf)
+	t.Cleanup(func() { oauthRefreshes.Delete(cfg) })
+	return errBuf
+}
+
+func TestGetAuthConfigRefreshesOAuthTokens(t *testing.T) {
+	newToken := newTestAccessToken(t, time.Now().Add(time.Hour))
+	refreshes := newTestOAuthServer(t, newToken, "a-new-pat")
+	cfg := newTestOAuthConfig(t, newTestAccessToken(t, time.Now().Add(-time.Minute)))
+	errBuf := setTestOAuthRefreshContext(t, context.Background(), cfg)
+
+	authCfg, err := getAuthConfig(cfg, authConfigKey)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authCfg.Username, "bork"))
+	assert.Check(t, is.Equal(authCfg.Password, "an-old-pat"))
+	assert.Check(t, is.Equal(*refreshes, 1))
+
+	accessCfg, err := cfg.GetAuthConfig(authConfigKey + "access-token")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(accessCfg.Password, newToken))
+	assert.Check(t, is.Equal(errBuf.String(), ""))
+}
+
+func TestGetAuthConfigChecksOAuthTokensOnce(t *testing.T) {
+	refreshes := newTestOAuthServer(t, newTestAccessToken(t, time.Now().Add(time.Hour)), "unused")
+	cfg := newTestOAuthConfig(t, newTestAccessToken(t, time.Now().Add(time.Hour)))
+	setTestOAuthRefreshContext(t, context.Background(), cfg)
+
+	_, err := getAuthConfig(cfg, authConfigKey)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(*refreshes, 0))
+
+	// the tokens are not checked again for the same configuration file.
+	assert.NilError(t, cfg.GetCredentialsStore(authConfigKey).Store(configtypes.AuthConfig{
+		ServerAddress: authConfigKey + "access-token",
+		Username:      "bork",
+		Password:      newTestAccessToken(t, time.Now().Add(-time.Minute)),
+	}))
+	_, err = getAuthConfig(cfg, authConfigKey)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(*refreshes, 0))
+}
+
+func TestGetAuthConfigValidOAuthTokens(t *testing.T) {
+	refreshes := newTestOAuthServer(t, "unused", "unused")
+	cfg := newTestOAuthConfig(t, newTestAccessToken(t, time.Now().Add(time.Hour)))
+	setTestOAuthRefreshContext(t, context.Background(), cfg)
+
+	authCfg, err := getAuthConfig(cfg, authConfigKey)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authCfg.Password, "an-old-pat"))
+	assert.Check(t, is.Equal(*refreshes, 0))
+
+	// credentials for other registries are never refreshed.
+	_, err = getAuthConfig(cfg, "registry.example.com")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(*refreshes, 0))
+}
+
+func TestGetAuthConfigRefreshCancelled(t *testing.T) {
+	refreshes := newTestOAuthServer(t, "unused", "unused")
+	cfg := newTestOAuthConfig(t, newTestAccessToken(t, time.Now().Add(-time.Minute)))
+
+	// the refresh uses the context of the CLI, and failing to refresh is
+	// reported on the CLI's error stream.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errBuf := setTestOAuthRefreshContext(t, ctx, cfg)
+	authCfg, err := getAuthConfig(cfg, authConfigKey)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authCfg.Password, "an-old-pat"))
+	assert.Check(t, is.Equal(*refreshes, 0))
+	assert.Check(t, is.Contains(errBuf.String(), "WARNING: failed to refresh credentials for Docker Hub:"))
+}
+
+func TestRegistryAuthConfigResolverRefreshesOAuthTokens(t *testing.T) {
+	refreshes := newTestOAuthServer(t, newTestAccessToken(t, time.Now().Add(time.Hour)), "unused")
+	cfg := newTestOAuthConfig(t, newTestAccessToken(t, time.Now().Add(-time.Minute)))
+	setTestOAuthRefreshContext(t, context.Background(), cfg)
+	dockerCLI, err := NewDockerCli()
+	assert.NilError(t, err)
+	dockerCLI.configFile = cfg
+
+	authCfg := RegistryAuthConfigResolver(dockerCLI)(context.Background(), "docker.io")
+	assert.Check(t, is.Equal(authCfg.Username, "bork"))
+	assert.Check(t, is.Equal(authCfg.Password, "an-old-pat"))
+	assert.Check(t, is.Equal(*refreshes, 1))
+}
+
+func TestResolveAuthConfigRefreshesOAuthTokens(t *testing.T) {
+	refreshes := newTestOAuthServer(t, newTestAccessToken(t, time.Now().Add(time.Hour)), "unused")
+	cfg := newTestOAuthConfig(t, newTestAccessToken(t, time.Now().Add(-time.Minute)))
+	setTestOAuthRefreshContext(t, context.Background(), cfg)
+
+	authCfg := ResolveAuthConfig(cfg, &registrytypes.IndexInfo{Name: "docker.io", Official: true}) //nolint:staticcheck // ignore SA1019: ResolveAuthConfig is deprecated
+	assert.Check(t, is.Equal(authCfg.Password, "an-old-pat"))
+	assert.Check(t, is.Equal(*refreshes, 1))
+}
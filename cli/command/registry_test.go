@@ -9,6 +9,7 @@
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
 	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/api/pkg/authconfig"
 	"github.com/moby/moby/api/types/registry"
 	"gotest.tools/v3/assert"
@@ -205,3 +206,23 @@ func TestRetrieveAuthTokenFromImage(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistryAuthConfigResolver(t *testing.T) {
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	for _, a := range testAuthConfigs {
+		assert.NilError(t, cfg.GetCredentialsStore(a.ServerAddress).Store(configtypes.AuthConfig{
+			ServerAddress: a.ServerAddress,
+			Username:      a.Username,
+			Password:      a.Password,
+		}))
+	}
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(cfg)
+	resolve := command.RegistryAuthConfigResolver(cli)
+
+	for _, domain := range []string{"docker.io", "index.docker.io"} {
+		assert.Check(t, is.DeepEqual(resolve(t.Context(), domain), testAuthConfigs[0]))
+	}
+	assert.Check(t, is.DeepEqual(resolve(t.Context(), "server1.io"), testAuthConfigs[1]))
+	assert.Check(t, is.DeepEqual(resolve(t.Context(), "no-auth.example.com"), registry.AuthConfig{}))
+}
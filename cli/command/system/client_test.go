@@ -14,11 +14,13 @@ type fakeClient struct {
 	version            string
 	containerListFunc  func(context.Context, client.ContainerListOptions) ([]container.Summary, error)
 	containerPruneFunc func(ctx context.Context, options client.ContainerPruneOptions) (client.ContainerPruneResult, error)
+	diskUsageFunc      func(ctx context.Context, options client.DiskUsageOptions) (client.DiskUsageResult, error)
 	eventsFn           func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error)
 	imageListFunc      func(ctx context.Context, options client.ImageListOptions) (client.ImageListResult, error)
 	infoFunc           func(ctx context.Context, options client.InfoOptions) (client.SystemInfoResult, error)
 	networkListFunc    func(ctx context.Context, options client.NetworkListOptions) (client.NetworkListResult, error)
 	networkPruneFunc   func(ctx context.Context, options client.NetworkPruneOptions) (client.NetworkPruneResult, error)
+	pingFunc           func(ctx context.Context, options client.PingOptions) (client.PingResult, error)
 	nodeListFunc       func(ctx context.Context, options client.NodeListOptions) (client.NodeListResult, error)
 	serverVersion      func(ctx context.Context, options client.ServerVersionOptions) (client.ServerVersionResult, error)
 	volumeListFunc     func(ctx context.Context, options client.VolumeListOptions) (client.VolumeListResult, error)
@@ -45,6 +47,20 @@ func (cli *fakeClient) ContainerPrune(ctx context.Context, opts client.Container
 	return client.ContainerPruneResult{}, nil
 }
 
+func (cli *fakeClient) DiskUsage(ctx context.Context, options client.DiskUsageOptions) (client.DiskUsageResult, error) {
+	if cli.diskUsageFunc != nil {
+		return cli.diskUsageFunc(ctx, options)
+	}
+	return client.DiskUsageResult{}, nil
+}
+
+func (cli *fakeClient) Ping(ctx context.Context, options client.PingOptions) (client.PingResult, error) {
+	if cli.pingFunc != nil {
+		return cli.pingFunc(ctx, options)
+	}
+	return client.PingResult{}, nil
+}
+
 func (cli *fakeClient) Events(ctx context.Context, opts client.EventsListOptions) client.EventsResult {
 	eventC, errC := cli.eventsFn(ctx, opts)
 	return client.EventsResult{
@@ -2,6 +2,9 @@
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -14,6 +17,7 @@
 type diskUsageOptions struct {
 	verbose bool
 	format  string
+	groupBy []string
 }
 
 // newDiskUsageCommand creates a new cobra.Command for `docker df`
@@ -36,14 +40,25 @@ func newDiskUsageCommand(dockerCLI command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Show detailed information on space usage")
 	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.StringSliceVar(&opts.groupBy, "group-by", nil, `Show subtotals grouped by "type" and/or "label=<key>"`)
 
 	return cmd
 }
 
 func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOptions) error {
+	byType, byLabel, err := parseGroupBy(opts.groupBy)
+	if err != nil {
+		return err
+	}
+	grouped := byType || byLabel != ""
+	if grouped && opts.verbose {
+		return errors.New("conflicting options: cannot specify both --group-by and --verbose")
+	}
+
 	// TODO expose types.DiskUsageOptions.Types as flag on the command-line and/or as separate commands (docker container df / docker container usage)
 	du, err := dockerCli.Client().DiskUsage(ctx, client.DiskUsageOptions{
-		Verbose: opts.verbose,
+		// Grouping needs the details of each object.
+		Verbose: opts.verbose || grouped,
 	})
 	if err != nil {
 		return err
@@ -54,12 +69,19 @@ func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOpti
 		format = formatter.TableFormatKey
 	}
 
+	duFormat := formatter.NewDiskUsageFormat(format, opts.verbose)
+	if grouped {
+		duFormat = formatter.NewDiskUsageGroupFormat(format, byType, byLabel)
+	}
+
 	duCtx := formatter.DiskUsageContext{
 		Context: formatter.Context{
 			Output: dockerCli.Out(),
-			Format: formatter.NewDiskUsageFormat(format, opts.verbose),
+			Format: duFormat,
 		},
 		Verbose:             opts.verbose,
+		GroupByType:         byType,
+		GroupByLabel:        byLabel,
 		ImageDiskUsage:      du.Images,
 		BuildCacheDiskUsage: du.BuildCache,
 		ContainerDiskUsage:  du.Containers,
@@ -68,3 +90,23 @@ func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOpti
 
 	return duCtx.Write()
 }
+
+// parseGroupBy parses the values of the --group-by option, which are "type"
+// or "label=<key>". Objects can be grouped by a single label only.
+func parseGroupBy(values []string) (byType bool, byLabel string, _ error) {
+	for _, v := range values {
+		k, label, hasValue := strings.Cut(v, "=")
+		switch {
+		case k == "type" && !hasValue:
+			byType = true
+		case k == "label" && label != "":
+			if byLabel != "" && byLabel != label {
+				return false, "", errors.New("invalid --group-by: cannot group by more than one label")
+			}
+			byLabel = label
+		default:
+			return false, "", fmt.Errorf(`invalid --group-by %q: must be "type" or "label=<key>"`, v)
+		}
+	}
+	return byType, byLabel, nil
+}
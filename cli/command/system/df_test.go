@@ -0,0 +1,101 @@
+package system
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/build"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestDiskUsageGroupBy(t *testing.T) {
+	testCases := []struct {
+		doc    string
+		args   []string
+		golden string
+	}{
+		{
+			doc:    "type",
+			args:   []string{"--group-by", "type"},
+			golden: "docker-df-group-by-type.golden",
+		},
+		{
+			doc:    "label and type",
+			args:   []string{"--group-by", "label=team", "--group-by", "type"},
+			golden: "docker-df-group-by-label-type.golden",
+		},
+		{
+			doc:    "json",
+			args:   []string{"--group-by", "label=team", "--format", "json"},
+			golden: "docker-df-group-by-label-json.golden",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				diskUsageFunc: func(_ context.Context, options client.DiskUsageOptions) (client.DiskUsageResult, error) {
+					assert.Check(t, options.Verbose, "expected details of objects to be requested")
+					return client.DiskUsageResult{
+						Images: client.ImagesDiskUsage{Items: []image.Summary{
+							{ID: "sha256:1", Size: 3000, SharedSize: 1000, Containers: 1, Labels: map[string]string{"team": "web"}},
+							{ID: "sha256:2", Size: 500, SharedSize: 0, Containers: 0},
+						}},
+						Containers: client.ContainersDiskUsage{Items: []container.Summary{
+							{ID: "1", SizeRw: 100, State: container.StateRunning, Labels: map[string]string{"team": "web"}},
+							{ID: "2", SizeRw: 200, State: container.StateExited, Labels: map[string]string{"team": "db"}},
+						}},
+						BuildCache: client.BuildCacheDiskUsage{Items: []build.CacheRecord{
+							{ID: "c1", Size: 400},
+							{ID: "c2", Size: 50, InUse: true, Shared: true},
+						}},
+					}, nil
+				},
+			})
+			cmd := newDiskUsageCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), tc.golden)
+		})
+	}
+}
+
+func TestDiskUsageGroupByInvalid(t *testing.T) {
+	testCases := []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{"--group-by", "name"},
+			expectedErr: `invalid --group-by "name": must be "type" or "label=<key>"`,
+		},
+		{
+			args:        []string{"--group-by", "label="},
+			expectedErr: `invalid --group-by "label=": must be "type" or "label=<key>"`,
+		},
+		{
+			args:        []string{"--group-by", "label=team", "--group-by", "label=owner"},
+			expectedErr: "invalid --group-by: cannot group by more than one label",
+		},
+		{
+			args:        []string{"--group-by", "type", "--verbose"},
+			expectedErr: "conflicting options: cannot specify both --group-by and --verbose",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			cmd := newDiskUsageCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.Error(t, cmd.Execute(), tc.expectedErr)
+		})
+	}
+}
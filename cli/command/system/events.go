@@ -1,11 +1,14 @@
 package system
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -13,6 +16,7 @@
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
 	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/internal/reconnect"
 	"github.com/docker/cli/opts"
 	"github.com/docker/cli/templates"
 	"github.com/moby/moby/api/types/events"
@@ -21,10 +25,17 @@
 )
 
 type eventsOptions struct {
-	since  string
-	until  string
-	filter opts.FilterOpt
-	format string
+	since           string
+	until           string
+	filter          opts.FilterOpt
+	format          string
+	reconnect       bool
+	output          string
+	outputMaxSize   opts.MemBytes
+	outputMaxAge    time.Duration
+	outputMaxFiles  int
+	exec            string
+	execConcurrency int
 }
 
 // newEventsCommand creates a new cobra.Command for `docker events`
@@ -50,6 +61,13 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&options.until, "until", "", "Stream events until this timestamp")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp) // using the same flag description as "inspect" commands for now.
+	flags.BoolVar(&options.reconnect, "reconnect", false, "Reconnect when the connection to the daemon is lost, and resume from the last event")
+	flags.StringVar(&options.output, "output", "", "Write events to files in a directory instead of stdout")
+	flags.Var(&options.outputMaxSize, "output-max-size", "Start a new file when the current file reaches this size (e.g. 10m)")
+	flags.DurationVar(&options.outputMaxAge, "output-max-age", 0, "Start a new file when the current file is older than this duration (e.g. 24h)")
+	flags.IntVar(&options.outputMaxFiles, "output-max-files", 0, "Maximum number of files to keep (0 to keep all files)")
+	flags.StringVar(&options.exec, "exec", "", "Run a command for each event, with the event in JSON format on stdin")
+	flags.IntVar(&options.execConcurrency, "exec-concurrency", 1, "Maximum number of commands to run at the same time")
 
 	_ = cmd.RegisterFlagCompletionFunc("filter", completeEventFilters(dockerCLI))
 
@@ -57,7 +75,7 @@ func newEventsCommand(dockerCLI command.Cli) *cobra.Command {
 }
 
 func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOptions) error {
-	tmpl, err := makeTemplate(options.format)
+	format, err := makeEventFormat(options.format)
 	if err != nil {
 		return cli.StatusError{
 			StatusCode: 64,
@@ -65,30 +83,151 @@ func runEvents(ctx context.Context, dockerCLI command.Cli, options *eventsOption
 		}
 	}
 	ctx, cancel := context.WithCancel(ctx)
-	eventRes := dockerCLI.Client().Events(ctx, client.EventsListOptions{
-		Since:   options.since,
-		Until:   options.until,
-		Filters: options.filter.Value(),
-	})
 	defer cancel()
 
-	out := dockerCLI.Out()
+	// Commands of the --exec option write concurrently with the events, so
+	// all output goes through the same lock to prevent lines from being
+	// interleaved.
+	var mu sync.Mutex
+	stdout := &lockedWriter{mu: &mu, w: dockerCLI.Out()}
+	stderr := &lockedWriter{mu: &mu, w: dockerCLI.Err()}
 
+	w := &eventWriter{out: stdout, format: format}
+	if options.exec != "" {
+		w.hooks, err = newEventHooks(options.exec, options.execConcurrency, stdout, stderr)
+		if err != nil {
+			return err
+		}
+		defer w.hooks.wait()
+	}
+	if options.output != "" {
+		var header bytes.Buffer
+		if err := format.writeHeader(&header); err != nil {
+			return err
+		}
+		files, err := newEventFiles(options.output, options.outputMaxSize.Value(), options.outputMaxAge, options.outputMaxFiles, header.Bytes())
+		if err != nil {
+			return err
+		}
+		defer files.Close()
+		w.out = files
+	} else if err := format.writeHeader(w.out); err != nil {
+		return err
+	}
+
+	var (
+		apiClient = dockerCLI.Client()
+		since     = options.since
+		resume    reconnect.Resume
+		backoff   reconnect.Backoff
+		lost      bool
+		resumeAt  time.Time
+	)
+	if options.reconnect {
+		// Errors of the events request are not returned until the stream
+		// ends, so check that the daemon can be reached, and don't retry if
+		// it can't.
+		if _, err := apiClient.Ping(ctx, client.PingOptions{}); err != nil {
+			return err
+		}
+	}
+	for {
+		eventRes := apiClient.Events(ctx, client.EventsListOptions{
+			Since:   since,
+			Until:   options.until,
+			Filters: options.filter.Value(),
+		})
+		err := streamEvents(ctx, w, eventRes, &resume, func() {
+			// The connection is only known to be re-established when
+			// the first event is received.
+			if lost {
+				reconnect.WriteResumed(stderr, resumeAt)
+				lost = false
+			}
+			backoff.Reset()
+		})
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if !options.reconnect || !reconnect.IsDisconnect(err) {
+			return err
+		}
+
+		reconnect.WriteLost(stderr, err, backoff.Delay())
+		lost = true
+		// Resume from the timestamp of the last event, as set by the daemon;
+		// the clocks of the CLI and the daemon may differ. If no events
+		// were received, the request is repeated as-is.
+		if resumeAt = resume.Last(); !resumeAt.IsZero() {
+			since = reconnect.Since(resumeAt)
+		}
+		if err := backoff.Wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// streamEvents writes the events of the stream until the stream ends, and
+// returns the error that ended the stream. Events that were written before
+// the stream was resumed are skipped. The received function is called when
+// the first event is received.
+func streamEvents(ctx context.Context, w *eventWriter, eventRes client.EventsResult, resume *reconnect.Resume, received func()) error {
 	for {
 		select {
 		case event := <-eventRes.Messages:
-			if err := handleEvent(out, event, tmpl); err != nil {
+			if received != nil {
+				received()
+				received = nil
+			}
+			if resume.Seen(eventTime(event)) {
+				continue
+			}
+			if err := w.write(ctx, event); err != nil {
 				return err
 			}
 		case err := <-eventRes.Err:
-			if err == io.EOF {
-				return nil
-			}
 			return err
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 }
 
+// eventWriter writes events to stdout or to files, and runs the command of
+// the --exec option for each event.
+type eventWriter struct {
+	out    io.Writer
+	format *eventFormat
+	hooks  *eventHooks
+	buf    bytes.Buffer
+}
+
+func (w *eventWriter) write(ctx context.Context, event events.Message) error {
+	// Events are written with a single write, so that an event is not
+	// split across files.
+	w.buf.Reset()
+	if err := w.format.write(&w.buf, event); err != nil {
+		return err
+	}
+	if _, err := w.out.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	if w.hooks != nil {
+		return w.hooks.run(ctx, event)
+	}
+	return nil
+}
+
+func eventTime(event events.Message) time.Time {
+	if event.TimeNano != 0 {
+		return time.Unix(0, event.TimeNano)
+	}
+	if event.Time != 0 {
+		return time.Unix(event.Time, 0)
+	}
+	return time.Time{}
+}
+
 func handleEvent(out io.Writer, event events.Message, tmpl *template.Template) error {
 	if tmpl == nil {
 		return prettyPrintEvent(out, event)
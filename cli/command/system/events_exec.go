@@ -0,0 +1,143 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+
+	"github.com/moby/moby/api/types/events"
+)
+
+// eventHooks runs a command for each event, with the event in JSON format
+// on stdin. At most concurrency commands are run at the same time; events
+// are not read while the limit is reached.
+type eventHooks struct {
+	command string
+	stdout  io.Writer
+	stderr  io.Writer
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+// newEventHooks returns an eventHooks that runs command. Commands run
+// concurrently, so stdout and stderr must be safe for concurrent use.
+func newEventHooks(command string, concurrency int, stdout, stderr io.Writer) (*eventHooks, error) {
+	if concurrency < 1 {
+		return nil, errors.New("invalid --exec-concurrency: must be at least 1")
+	}
+	return &eventHooks{
+		command: command,
+		stdout:  stdout,
+		stderr:  stderr,
+		sem:     make(chan struct{}, concurrency),
+	}, nil
+}
+
+// run starts the command for the event. It waits if the maximum number of
+// commands is running. Commands that fail are reported on stderr, but do not
+// stop processing events.
+func (h *eventHooks) run(ctx context.Context, event events.Message) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	select {
+	case h.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	h.wg.Add(1)
+	go func() {
+		defer func() {
+			<-h.sem
+			h.wg.Done()
+		}()
+		// exec.Cmd copies the output in chunks of any size, so it's
+		// buffered per line to not interleave lines of concurrent commands.
+		stdout, stderr := &lineWriter{w: h.stdout}, &lineWriter{w: h.stderr}
+
+		cmd := hookCommand(ctx, h.command)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		cmd.Env = append(os.Environ(),
+			"DOCKER_EVENT_TYPE="+string(event.Type),
+			"DOCKER_EVENT_ACTION="+string(event.Action),
+			"DOCKER_EVENT_ACTOR_ID="+event.Actor.ID,
+		)
+		err := cmd.Run()
+		stdout.flush()
+		stderr.flush()
+		if err != nil && ctx.Err() == nil {
+			_, _ = fmt.Fprintf(h.stderr, "WARNING: --exec command failed for %s %s event of %s: %v\n", event.Type, event.Action, event.Actor.ID, err)
+		}
+	}()
+	return nil
+}
+
+// wait waits for the running commands to complete.
+func (h *eventHooks) wait() {
+	h.wg.Wait()
+}
+
+// hookCommand returns the command to run command with the shell.
+func hookCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+}
+
+// maxLineSize is the size at which the output of a command is written, even
+// if the line does not end yet.
+const maxLineSize = 64 * 1024
+
+// lineWriter buffers the output of a command, and writes it to w in complete
+// lines. It's not safe for concurrent use.
+type lineWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	n := bytes.LastIndexByte(w.buf, '\n') + 1
+	if len(w.buf) >= maxLineSize {
+		n = len(w.buf)
+	}
+	if n == 0 {
+		return len(p), nil
+	}
+	_, err := w.w.Write(w.buf[:n])
+	w.buf = append(w.buf[:0], w.buf[n:]...)
+	return len(p), err
+}
+
+// flush writes the output that does not end with a newline.
+func (w *lineWriter) flush() {
+	if len(w.buf) > 0 {
+		_, _ = w.w.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+// lockedWriter serializes writes to w; writers that share the mutex don't
+// write at the same time.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
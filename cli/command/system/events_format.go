@@ -0,0 +1,179 @@
+package system
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/templates"
+	"github.com/moby/moby/api/types/events"
+)
+
+const (
+	defaultEventsTableFormat = "table {{.Time}}\t{{.Type}}\t{{.Action}}\t{{.Name}}\t{{.Attributes}}"
+
+	eventTimeHeader       = "TIME"
+	eventTypeHeader       = "TYPE"
+	eventActionHeader     = "ACTION"
+	eventIDHeader         = "ID"
+	eventAttributesHeader = "ATTRIBUTES"
+)
+
+// eventKeyAttributes are the attributes that are shown in the "Attributes"
+// column of the table format.
+var eventKeyAttributes = []string{"image", "exitCode", "signal", "container", "driver"}
+
+// eventContext is the context that's used for the table format. Other formats
+// use the [events.Message] as context.
+type eventContext struct {
+	formatter.HeaderContext
+	e events.Message
+}
+
+func newEventContext(e events.Message) *eventContext {
+	return &eventContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Time":       eventTimeHeader,
+				"Type":       eventTypeHeader,
+				"Action":     eventActionHeader,
+				"Name":       formatter.NameHeader,
+				"ID":         eventIDHeader,
+				"Scope":      formatter.ScopeHeader,
+				"Attributes": eventAttributesHeader,
+			},
+		},
+		e: e,
+	}
+}
+
+func (c *eventContext) Time() string {
+	t := eventTime(c.e)
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (c *eventContext) Type() string {
+	return string(c.e.Type)
+}
+
+func (c *eventContext) Action() string {
+	return string(c.e.Action)
+}
+
+func (c *eventContext) ID() string {
+	return c.e.Actor.ID
+}
+
+// Name returns the name of the actor of the event, or its short ID if the
+// actor has no name.
+func (c *eventContext) Name() string {
+	if name := c.e.Actor.Attributes["name"]; name != "" {
+		return name
+	}
+	return formatter.TruncateID(c.e.Actor.ID)
+}
+
+func (c *eventContext) Scope() string {
+	return c.e.Scope
+}
+
+// Attributes returns the key attributes of the event.
+func (c *eventContext) Attributes() string {
+	var attrs []string
+	for _, k := range eventKeyAttributes {
+		if v, ok := c.e.Actor.Attributes[k]; ok {
+			attrs = append(attrs, k+"="+v)
+		}
+	}
+	return strings.Join(attrs, ", ")
+}
+
+// eventFormat formats events with the template of the --format option.
+type eventFormat struct {
+	tmpl  *template.Template
+	table bool
+}
+
+func makeEventFormat(format string) (*eventFormat, error) {
+	if format == formatter.TableFormatKey {
+		format = defaultEventsTableFormat
+	}
+	if !formatter.Format(format).IsTable() {
+		tmpl, err := makeTemplate(format)
+		return &eventFormat{tmpl: tmpl}, err
+	}
+
+	format = strings.TrimPrefix(format, formatter.TableFormatKey)
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(strings.Trim(format, " "))
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	// execute the template on an empty message to validate a bad
+	// template like "{{.badFieldString}}"
+	return &eventFormat{tmpl: tmpl, table: true}, tmpl.Execute(io.Discard, newEventContext(events.Message{}))
+}
+
+// writeHeader writes the header of the table format. It's a no-op for other
+// formats.
+func (f *eventFormat) writeHeader(out io.Writer) error {
+	if !f.table {
+		return nil
+	}
+	// Clone the template, as the header functions replace the functions
+	// that are used for the events.
+	tmpl, err := f.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	return f.writeRow(out, tmpl.Funcs(templates.HeaderFunctions), newEventContext(events.Message{}).FullHeader())
+}
+
+func (f *eventFormat) write(out io.Writer, event events.Message) error {
+	if !f.table {
+		return handleEvent(out, event, f.tmpl)
+	}
+	return f.writeRow(out, f.tmpl, newEventContext(event))
+}
+
+// writeRow writes a row of the table format. Events are written as they are
+// received, so the width of the columns can't be determined by the widest
+// cell of all rows. Instead, each row is aligned together with a row for a
+// sample event, which is not written.
+func (f *eventFormat) writeRow(out io.Writer, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 10, 1, 3, ' ', 0)
+	if err := f.tmpl.Execute(tw, sampleEvent()); err != nil {
+		return err
+	}
+	_, _ = tw.Write([]byte{'\n'})
+	if err := tmpl.Execute(tw, data); err != nil {
+		return err
+	}
+	_, _ = tw.Write([]byte{'\n'})
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, row, _ := bytes.Cut(buf.Bytes(), []byte{'\n'})
+	_, err := out.Write(row)
+	return err
+}
+
+// sampleEvent returns the event that's used to determine the width of the
+// columns of the table format.
+func sampleEvent() *eventContext {
+	return newEventContext(events.Message{
+		Type:     events.ContainerEventType,
+		Action:   events.ActionCreate,
+		Actor:    events.Actor{ID: strings.Repeat("0", 64)},
+		Scope:    "local",
+		TimeNano: time.Now().UnixNano(),
+	})
+}
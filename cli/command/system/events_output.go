@@ -0,0 +1,132 @@
+package system
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	eventFilePrefix = "events-"
+	eventFileSuffix = ".log"
+
+	// eventFileTimeFormat is the format of the time in the name of event
+	// files; names of files sort in the order they were created.
+	eventFileTimeFormat = "20060102T150405.000Z"
+)
+
+// eventFiles writes events to files in a directory. A new file is started
+// when the current file exceeds maxSize, or is older than maxAge, and the
+// oldest files are removed if there are more than maxFiles. Events are never
+// split across files.
+type eventFiles struct {
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+	// header is written at the start of each file.
+	header []byte
+
+	f       *os.File
+	size    int64
+	created time.Time
+}
+
+func newEventFiles(dir string, maxSize int64, maxAge time.Duration, maxFiles int, header []byte) (*eventFiles, error) {
+	if maxSize < 0 || maxAge < 0 || maxFiles < 0 {
+		return nil, errors.New("invalid output options: values must not be negative")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &eventFiles{
+		dir:      dir,
+		maxSize:  maxSize,
+		maxAge:   maxAge,
+		maxFiles: maxFiles,
+		header:   header,
+	}, nil
+}
+
+// Write writes a single event to the current file, and rotates the file if
+// needed.
+func (w *eventFiles) Write(p []byte) (int, error) {
+	now := time.Now()
+	if w.f != nil && w.needsRotate(now, len(p)) {
+		if err := w.Close(); err != nil {
+			return 0, err
+		}
+	}
+	if w.f == nil {
+		if err := w.open(now); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *eventFiles) needsRotate(now time.Time, n int) bool {
+	if w.maxSize > 0 && w.size > int64(len(w.header)) && w.size+int64(n) > w.maxSize {
+		return true
+	}
+	return w.maxAge > 0 && now.Sub(w.created) >= w.maxAge
+}
+
+func (w *eventFiles) open(now time.Time) error {
+	var f *os.File
+	for t := now; ; t = t.Add(time.Millisecond) {
+		// Use the next millisecond for the name if a file was already
+		// started in this millisecond, so that names sort in order.
+		var err error
+		name := filepath.Join(w.dir, eventFilePrefix+t.UTC().Format(eventFileTimeFormat)+eventFileSuffix)
+		f, err = os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+	}
+	w.f, w.size, w.created = f, 0, now
+	if len(w.header) > 0 {
+		n, err := f.Write(w.header)
+		w.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return w.removeOldFiles()
+}
+
+// removeOldFiles removes the oldest files if there are more than maxFiles.
+func (w *eventFiles) removeOldFiles() error {
+	if w.maxFiles <= 0 {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(w.dir, eventFilePrefix+"*"+eventFileSuffix))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for len(files) > w.maxFiles {
+		if err := os.Remove(files[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Close closes the current file.
+func (w *eventFiles) Close() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
@@ -1,18 +1,28 @@
 package system
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/api/types/events"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
+	"gotest.tools/v3/skip"
 )
 
 func TestEventsFormat(t *testing.T) {
@@ -50,6 +60,14 @@ func TestEventsFormat(t *testing.T) {
 			name: "json action",
 			args: []string{"--format", "{{ json .Action }}"},
 		},
+		{
+			name: "table",
+			args: []string{"--format", "table"},
+		},
+		{
+			name: "table custom",
+			args: []string{"--format", "table {{.Type}}\\t{{.Action}}\\t{{.ID}}"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -79,3 +97,297 @@ func TestEventsFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestEventsReconnect(t *testing.T) {
+	t.Setenv("TZ", "UTC")
+	event := func(action events.Action, sec int64) events.Message {
+		return events.Message{
+			Type:     events.ContainerEventType,
+			Action:   action,
+			Actor:    events.Actor{ID: "abc123"},
+			Time:     sec,
+			TimeNano: sec * int64(time.Second),
+		}
+	}
+	streams := [][]events.Message{
+		{event(events.ActionCreate, 1), event(events.ActionStart, 2)},
+		// the last event before disconnecting is sent again after resuming.
+		{event(events.ActionStart, 2), event(events.ActionDie, 3)},
+	}
+	var since []string
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: func(_ context.Context, options client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		since = append(since, options.Since)
+		messages := make(chan events.Message)
+		errs := make(chan error, 1)
+		go func(n int) {
+			for _, msg := range streams[n] {
+				messages <- msg
+			}
+			if n == 0 {
+				errs <- io.ErrUnexpectedEOF
+			} else {
+				errs <- io.EOF
+			}
+		}(len(since) - 1)
+		return messages, errs
+	}})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--reconnect", "--since", "1970-01-01T00:00:00Z", "--format", "{{.Action}}"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(since, []string{"1970-01-01T00:00:00Z", "1970-01-01T00:00:02Z"}))
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "create\nstart\ndie\n"))
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "--- connection to the daemon lost: unexpected EOF; reconnecting in 500ms\n--- reconnected; resuming from 1970-01-01T00:00:02Z\n"))
+}
+
+// without --since, and before any event is received, the CLI's clock is not
+// used to resume the events.
+func TestEventsReconnectNoEvents(t *testing.T) {
+	var since []string
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: func(_ context.Context, options client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		since = append(since, options.Since)
+		errs := make(chan error, 1)
+		if len(since) == 1 {
+			errs <- io.ErrUnexpectedEOF
+		} else {
+			errs <- io.EOF
+		}
+		return make(chan events.Message), errs
+	}})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--reconnect"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(since, []string{"", ""}))
+}
+
+// failedEventsFn returns a function that fails like the events request of
+// the client: the error is sent after the function returns.
+func failedEventsFn(calls *int, err error) func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error) {
+	return func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		*calls++
+		errs := make(chan error, 1)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			errs <- err
+		}()
+		return make(chan events.Message), errs
+	}
+}
+
+func TestEventsReconnectFailedConnect(t *testing.T) {
+	var calls int
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		pingFunc: func(context.Context, client.PingOptions) (client.PingResult, error) {
+			return client.PingResult{}, errors.New("error during connect")
+		},
+		eventsFn: failedEventsFn(&calls, errors.New("error during connect")),
+	})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--reconnect"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "error during connect"))
+	assert.Check(t, is.Equal(calls, 0), "expected no reconnect if the daemon can't be reached")
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), ""))
+}
+
+func TestEventsReconnectFailedAttempts(t *testing.T) {
+	var calls int
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: failedEventsFn(&calls, io.ErrUnexpectedEOF)})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--reconnect"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorIs(cmd.ExecuteContext(ctx), context.DeadlineExceeded))
+
+	// attempts that fail before an event is received are not reported as
+	// reconnected, and don't reset the delay.
+	assert.Check(t, is.Equal(calls, 3))
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), ""+
+		"--- connection to the daemon lost: unexpected EOF; reconnecting in 500ms\n"+
+		"--- connection to the daemon lost: unexpected EOF; reconnecting in 1s\n"+
+		"--- connection to the daemon lost: unexpected EOF; reconnecting in 2s\n",
+	))
+}
+
+// eventsFn returns a function that sends the messages, and ends the stream.
+func eventsFn(messages ...events.Message) func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error) {
+	return func(context.Context, client.EventsListOptions) (<-chan events.Message, <-chan error) {
+		msgs := make(chan events.Message)
+		errs := make(chan error, 1)
+		go func() {
+			for _, msg := range messages {
+				msgs <- msg
+			}
+			errs <- io.EOF
+		}()
+		return msgs, errs
+	}
+}
+
+func TestEventsOutput(t *testing.T) {
+	var evts []events.Message
+	for i := 0; i < 5; i++ {
+		evts = append(evts, events.Message{
+			Type:     events.ContainerEventType,
+			Action:   events.ActionStart,
+			Actor:    events.Actor{ID: "abc123", Attributes: map[string]string{"name": fmt.Sprintf("ctr%d", i)}},
+			TimeNano: int64(time.Second) * int64(i+1),
+		})
+	}
+	dir := filepath.Join(t.TempDir(), "events")
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: eventsFn(evts...)})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{
+		"--output", dir,
+		"--output-max-size", "45",
+		"--output-max-files", "2",
+		"--format", "table {{.Action}}\t{{.Name}}",
+	})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), ""))
+
+	files, err := filepath.Glob(filepath.Join(dir, "events-*.log"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(files, 2))
+	var contents []string
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		assert.NilError(t, err)
+		contents = append(contents, string(data))
+	}
+	// each file has a header, and two events, except for the last file.
+	assert.Check(t, is.DeepEqual(contents, []string{
+		"ACTION    NAME\nstart     ctr2\nstart     ctr3\n",
+		"ACTION    NAME\nstart     ctr4\n",
+	}))
+}
+
+func TestEventsExec(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test uses a POSIX shell")
+	evts := []events.Message{
+		{Type: events.ContainerEventType, Action: events.ActionCreate, Actor: events.Actor{ID: "abc123"}, TimeNano: 1},
+		{Type: events.ContainerEventType, Action: events.ActionStart, Actor: events.Actor{ID: "abc123"}, TimeNano: 2},
+		{Type: events.ContainerEventType, Action: events.ActionDie, Actor: events.Actor{ID: "abc123"}, TimeNano: 3},
+	}
+	dir := t.TempDir()
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: eventsFn(evts...)})
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{
+		"--exec", `cat > "` + dir + `/$DOCKER_EVENT_ACTION.json"; test "$DOCKER_EVENT_ACTION" != die`,
+		"--exec-concurrency", "2",
+		"--format", "{{.Action}}",
+	})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "create\nstart\ndie\n"))
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "WARNING: --exec command failed for container die event of abc123: exit status 1\n"))
+	for _, e := range evts {
+		data, err := os.ReadFile(filepath.Join(dir, string(e.Action)+".json"))
+		assert.NilError(t, err)
+		var actual events.Message
+		assert.NilError(t, json.Unmarshal(data, &actual))
+		assert.Check(t, is.DeepEqual(actual, e))
+	}
+}
+
+// overlapWriter records whether writes overlap.
+type overlapWriter struct {
+	writing atomic.Bool
+	overlap atomic.Bool
+	buf     bytes.Buffer
+}
+
+func (w *overlapWriter) Write(p []byte) (int, error) {
+	if !w.writing.CompareAndSwap(false, true) {
+		w.overlap.Store(true)
+		return len(p), nil
+	}
+	defer w.writing.Store(false)
+	time.Sleep(time.Millisecond)
+	return w.buf.Write(p)
+}
+
+func TestEventsExecOutput(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows", "test uses a POSIX shell")
+	var evts []events.Message //nolint:prealloc
+	for i := 1; i <= 20; i++ {
+		evts = append(evts, events.Message{Type: events.ContainerEventType, Action: events.ActionStart, Actor: events.Actor{ID: "abc123"}, TimeNano: int64(i)})
+	}
+	out := &overlapWriter{}
+	fakeCLI := test.NewFakeCli(&fakeClient{eventsFn: eventsFn(evts...)})
+	fakeCLI.SetOut(streams.NewOut(out))
+	cmd := newEventsCommand(fakeCLI)
+	cmd.SetArgs([]string{"--exec", "echo hook", "--exec-concurrency", "4", "--format", "{{.Action}}"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	// the output of the commands is not written at the same time as events.
+	assert.Check(t, !out.overlap.Load(), "writes must not overlap")
+	assert.Check(t, is.Equal(strings.Count(out.buf.String(), "start\n"), 20))
+	assert.Check(t, is.Equal(strings.Count(out.buf.String(), "hook\n"), 20))
+}
+
+func TestLineWriter(t *testing.T) {
+	var writes []string
+	w := &lineWriter{w: writerFunc(func(p []byte) (int, error) {
+		writes = append(writes, string(p))
+		return len(p), nil
+	})}
+	for _, chunk := range []string{"fir", "st\nsec", "ond\nthird\nfou", "rth"} {
+		n, err := w.Write([]byte(chunk))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(n, len(chunk)))
+	}
+	w.flush()
+	assert.Check(t, is.DeepEqual(writes, []string{"first\n", "second\nthird\n", "fourth"}))
+
+	// long lines are written when the buffer is full.
+	writes = nil
+	_, err := w.Write(bytes.Repeat([]byte("x"), maxLineSize))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(writes, 1))
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestEventsExecInvalidConcurrency(t *testing.T) {
+	cmd := newEventsCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--exec", "true", "--exec-concurrency", "0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "invalid --exec-concurrency: must be at least 1"))
+}
+
+func TestEventFilesMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newEventFiles(dir, 0, time.Nanosecond, 0, nil)
+	assert.NilError(t, err)
+	for _, line := range []string{"one\n", "two\n", "three\n"} {
+		_, err := w.Write([]byte(line))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, w.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "events-*.log"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(files, 3), "expected a file for each event")
+	data, err := os.ReadFile(files[2])
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "three\n"), "expected files to sort in the order they were created")
+}
@@ -44,6 +44,7 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display volume names")
 	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 	flags.VarP(&options.filter, "filter", "f", `Provide filter values (e.g. "dangling=true")`)
+	_ = flags.SetAnnotation("filter", command.FlagAnnotationLabelFilter, nil)
 	flags.BoolVar(&options.cluster, "cluster", false, "Display only cluster volumes, and use cluster volume list formatting")
 	_ = flags.SetAnnotation("cluster", "version", []string{"1.42"})
 	_ = flags.SetAnnotation("cluster", "swarm", []string{"manager"})
@@ -28,6 +28,11 @@
 	ConfigFileName = "config.json"
 	configFileDir  = ".docker"
 	contextsDir    = "contexts"
+
+	// SystemConfigFileName is the name of the system-wide configuration file
+	// inside the system configuration directory ("/etc/docker" on Linux and
+	// macOS, and "%ProgramData%\docker" on Windows).
+	SystemConfigFileName = "cli-config.json"
 )
 
 var (
@@ -68,6 +73,15 @@ func getHomeDir() string {
 	return home
 }
 
+// systemConfigDir returns the directory containing the system-wide
+// configuration file. It is a variable so that it can be overridden in tests.
+var systemConfigDir = func() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "docker")
+	}
+	return "/etc/docker"
+}
+
 // Provider defines an interface for providing the CLI config.
 type Provider interface {
 	ConfigFile() *configfile.ConfigFile
@@ -139,7 +153,7 @@ func load(configDir string) (*configfile.ConfigFile, error) {
 		if os.IsNotExist(err) {
 			// It is OK for no configuration file to be present, in which
 			// case we return a default struct.
-			return configFile, nil
+			return configFile, loadLayers(configFile, configDir)
 		}
 		// Any other error happening when failing to read the file must be returned.
 		return configFile, fmt.Errorf("loading config file: %w", err)
@@ -147,9 +161,135 @@ func load(configDir string) (*configfile.ConfigFile, error) {
 	defer func() { _ = file.Close() }()
 	err = configFile.LoadFromReader(file)
 	if err != nil {
-		err = fmt.Errorf("parsing config file (%s): %w", filename, err)
+		return configFile, fmt.Errorf("parsing config file (%s): %w", filename, err)
+	}
+	return configFile, loadLayers(configFile, configDir)
+}
+
+// loadLayers merges the system-wide configuration file and the configuration
+// file of the project in the current working directory (if present) with the
+// user's configuration file.
+func loadLayers(configFile *configfile.ConfigFile, configDir string) error {
+	layers := []struct {
+		layer    configfile.Layer
+		filename string
+	}{
+		{layer: configfile.LayerSystem, filename: filepath.Join(systemConfigDir(), SystemConfigFileName)},
+		{layer: configfile.LayerProject, filename: findProjectConfigFile(configDir)},
+	}
+	for _, l := range layers {
+		if l.filename == "" {
+			continue
+		}
+		file, err := os.Open(l.filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("loading %s config file: %w", l.layer, err)
+		}
+		err = configFile.AddLayer(l.layer, l.filename, file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("parsing %s config file (%s): %w", l.layer, l.filename, err)
+		}
+	}
+	return nil
+}
+
+// vcsDirs are the names of the directories that mark the root of a
+// repository.
+var vcsDirs = []string{".git", ".hg", ".svn"}
+
+// findProjectConfigFile looks for a ".docker/config.json" in the current
+// working directory and its parent directories, and returns its path, or an
+// empty string if none was found. The user's configuration directory, and the
+// ".docker" directory in the user's home directory are skipped. Refer to
+// [FindProjectFile] for the files and directories that are considered.
+func findProjectConfigFile(configDir string) string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	userDir, _ := filepath.Abs(configDir)
+	homeDir := filepath.Join(getHomeDir(), configFileDir)
+	var found string
+	walkProjectDirs(dir, func(dir string) bool {
+		candidate := filepath.Join(dir, configFileDir)
+		if candidate == userDir || candidate == homeDir {
+			return false
+		}
+		fileName := filepath.Join(candidate, ConfigFileName)
+		if isProjectFile(fileName) {
+			found = fileName
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+// FindProjectFile looks for a file with the given name in dir and its parent
+// directories, and returns its path, or an empty string if none was found.
+// Files that are not regular files or that are not owned by the current user
+// are skipped. The search stops at the root of a repository, and does not
+// cross filesystem boundaries, so that files in directories of other users
+// are not picked up.
+func FindProjectFile(dir, name string) string {
+	var found string
+	walkProjectDirs(dir, func(dir string) bool {
+		if fileName := filepath.Join(dir, name); isProjectFile(fileName) {
+			found = fileName
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+// walkProjectDirs calls fn for dir and its parent directories, until fn
+// returns true, the root of a repository is reached, or the parent directory
+// is on another filesystem.
+func walkProjectDirs(dir string, fn func(dir string) bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return
+	}
+	for {
+		if fn(dir) || isRepositoryRoot(dir) {
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		parentInfo, err := os.Stat(parent)
+		if err != nil || !sameDevice(dirInfo, parentInfo) {
+			return
+		}
+		dir, dirInfo = parent, parentInfo
+	}
+}
+
+// isProjectFile returns whether fileName is a regular file that's owned by
+// the current user.
+func isProjectFile(fileName string) bool {
+	fi, err := os.Stat(fileName)
+	return err == nil && fi.Mode().IsRegular() && ownedByCurrentUser(fileName, fi)
+}
+
+// isRepositoryRoot returns whether dir is the root of a repository.
+func isRepositoryRoot(dir string) bool {
+	for _, name := range vcsDirs {
+		if _, err := os.Lstat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
 	}
-	return configFile, err
+	return false
 }
 
 // LoadDefaultConfigFile attempts to load the default config file and returns
@@ -482,3 +482,72 @@ func TestSetDir(t *testing.T) {
 	SetDir(expected)
 	assert.Check(t, is.Equal(Dir(), expected))
 }
+
+func TestLoadLayers(t *testing.T) {
+	dir := setupConfigDir(t)
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(`{"psFormat": "user-format"}`), 0o600))
+
+	systemDir := t.TempDir()
+	origSystemConfigDir := systemConfigDir
+	t.Cleanup(func() { systemConfigDir = origSystemConfigDir })
+	systemConfigDir = func() string { return systemDir }
+	systemFile := filepath.Join(systemDir, SystemConfigFileName)
+	assert.NilError(t, os.WriteFile(systemFile, []byte(`{"psFormat": "system-format", "imagesFormat": "system-format"}`), 0o644))
+
+	projectDir := t.TempDir()
+	projectFile := filepath.Join(projectDir, ".docker", ConfigFileName)
+	assert.NilError(t, os.MkdirAll(filepath.Join(projectDir, ".docker"), 0o755))
+	assert.NilError(t, os.WriteFile(projectFile, []byte(`{"imagesFormat": "project-format"}`), 0o644))
+	workDir := filepath.Join(projectDir, "src", "app")
+	assert.NilError(t, os.MkdirAll(workDir, 0o755))
+	t.Chdir(workDir)
+
+	configFile, err := Load(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(configFile.PsFormat, "user-format"))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "project-format"))
+	assert.Check(t, is.Len(configFile.Layers, 3))
+	assert.Check(t, is.Equal(configFile.Layers[0].Filename, systemFile))
+	assert.Check(t, is.Equal(configFile.Layers[2].Filename, projectFile))
+
+	configFile.PsFormat = "new-format"
+	assert.NilError(t, configFile.Save())
+	saved, err := os.ReadFile(filepath.Join(dir, ConfigFileName))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(saved), "{\n\t\"auths\": {},\n\t\"psFormat\": \"new-format\"\n}"))
+
+	t.Run("malformed project config", func(t *testing.T) {
+		assert.NilError(t, os.WriteFile(projectFile, []byte(`{"imagesFormat": `), 0o644))
+		_, err := Load(dir)
+		assert.Check(t, is.ErrorContains(err, "parsing project config file ("+projectFile+")"))
+	})
+}
+
+func TestFindProjectConfigFile(t *testing.T) {
+	configDir := setupConfigDir(t)
+
+	// a configuration file outside the repository is not used.
+	baseDir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(baseDir, ".docker"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(baseDir, ".docker", ConfigFileName), []byte(`{}`), 0o644))
+	repoDir := filepath.Join(baseDir, "repo")
+	assert.NilError(t, os.MkdirAll(filepath.Join(repoDir, ".git"), 0o755))
+	workDir := filepath.Join(repoDir, "src")
+	assert.NilError(t, os.MkdirAll(workDir, 0o755))
+	t.Chdir(workDir)
+	assert.Check(t, is.Equal(findProjectConfigFile(configDir), ""))
+
+	// the configuration file at the root of the repository is used.
+	projectFile := filepath.Join(repoDir, ".docker", ConfigFileName)
+	assert.NilError(t, os.MkdirAll(filepath.Join(repoDir, ".docker"), 0o755))
+	assert.NilError(t, os.WriteFile(projectFile, []byte(`{}`), 0o644))
+	assert.Check(t, is.Equal(findProjectConfigFile(configDir), projectFile))
+
+	t.Run("owned by another user", func(t *testing.T) {
+		if runtime.GOOS == "windows" || os.Getuid() != 0 {
+			t.Skip("requires root to change the owner of files")
+		}
+		assert.NilError(t, os.Chown(projectFile, 1234, 1234))
+		assert.Check(t, is.Equal(findProjectConfigFile(configDir), ""))
+	})
+}
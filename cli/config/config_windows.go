@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// ownedByCurrentUser returns whether the file is owned by the current user.
+// Files that are owned by a group, such as files that are created by an
+// elevated process of an administrator, are not considered to be owned by
+// the user.
+func ownedByCurrentUser(fileName string, _ os.FileInfo) bool {
+	sd, err := windows.GetNamedSecurityInfo(fileName, windows.SE_FILE_OBJECT, windows.OWNER_SECURITY_INFORMATION)
+	if err != nil {
+		return false
+	}
+	owner, _, err := sd.Owner()
+	if err != nil || owner == nil {
+		return false
+	}
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return false
+	}
+	return owner.Equals(user.User.Sid)
+}
+
+// sameDevice returns whether both files are on the same filesystem. Parent
+// directories are always on the same volume on Windows.
+func sameDevice(_, _ os.FileInfo) bool {
+	return true
+}
@@ -13,6 +13,7 @@
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/memorystore"
@@ -43,25 +44,35 @@ type ConfigFile struct {
 	PruneFilters         []string                     `json:"pruneFilters,omitempty"`
 	Proxies              map[string]ProxyConfig       `json:"proxies,omitempty"`
 	CurrentContext       string                       `json:"currentContext,omitempty"`
+	ContextTLSKeyStore   string                       `json:"contextTLSKeyStore,omitempty"`
 	CLIPluginsExtraDirs  []string                     `json:"cliPluginsExtraDirs,omitempty"`
 	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
 	Aliases              map[string]string            `json:"aliases,omitempty"`
 	Features             map[string]string            `json:"features,omitempty"`
+	Layers               []ConfigLayer                `json:"-"` // Note: for internal use only
 }
 
 type configEnvAuth struct {
-	Auth string `json:"auth"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+	RegistryToken string `json:"registrytoken,omitempty"`
 }
 
 type configEnv struct {
-	AuthConfigs map[string]configEnvAuth `json:"auths"`
+	AuthConfigs       map[string]configEnvAuth `json:"auths"`
+	CredentialHelpers map[string]string        `json:"credHelpers,omitempty"`
 }
 
 // DockerEnvConfigKey is an environment variable that contains a JSON encoded
-// credential config. It only supports storing the credentials as a base64
-// encoded string in the format base64("username:pat").
+// credential config. Credentials for a registry can be provided as a base64
+// encoded string in the format base64("username:pat"), as an identity token
+// (optionally combined with "auth" to provide the username), or as a registry
+// token. The "credHelpers" field configures a credential helper to use for a
+// registry, which takes precedence over the credential helpers and
+// credential store configured in the configuration file.
 //
-// Adding additional fields will produce a parsing error.
+// A registry can only be configured once, and adding additional fields will
+// produce a parsing error.
 //
 // Example:
 //
@@ -69,7 +80,13 @@ type configEnv struct {
 //		"auths": {
 //			"example.test": {
 //				"auth": base64-encoded-username-pat
+//			},
+//			"token.example.test": {
+//				"identitytoken": identity-token
 //			}
+//		},
+//		"credHelpers": {
+//			"helper.example.test": "ecr-login"
 //		}
 //	}
 const DockerEnvConfigKey = "DOCKER_AUTH_CONFIG"
@@ -132,7 +149,8 @@ func (configFile *ConfigFile) GetAuthConfigs() map[string]types.AuthConfig {
 }
 
 // SaveToWriter encodes and writes out all the authorization information to
-// the given writer
+// the given writer. If the configuration is merged from multiple layers, only
+// the user layer is written.
 func (configFile *ConfigFile) SaveToWriter(writer io.Writer) error {
 	// Encode sensitive data into a new/temp struct
 	tmpAuthConfigs := make(map[string]types.AuthConfig, len(configFile.AuthConfigs))
@@ -161,6 +179,14 @@ func (configFile *ConfigFile) SaveToWriter(writer io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if len(configFile.Layers) > 0 {
+		// Only write the user layer; values inherited from the system or
+		// project configuration must not end up in the user's configuration.
+		data, err = configFile.userLayerJSON(data)
+		if err != nil {
+			return err
+		}
+	}
 	_, err = writer.Write(data)
 	return err
 }
@@ -215,6 +241,60 @@ func (configFile *ConfigFile) Save() (retErr error) {
 	return os.Rename(temp.Name(), cfgFile)
 }
 
+// updateMu serializes updates within the current process, as file locks are
+// held per process on some platforms.
+var updateMu sync.Mutex
+
+// Update applies fn to the configuration file while holding an exclusive lock
+// on it, to prevent concurrent updates (for example, from other docker
+// processes or CLI plugins) from being lost. The configuration file is read
+// from disk before calling fn, so that fn operates on the latest content of
+// the user's configuration file, and saved after fn returns without an
+// error. fn is also applied to the receiver, so that the change is reflected
+// in the loaded configuration.
+//
+// The lock is held on a "<filename>.lock" file next to the configuration file.
+func (configFile *ConfigFile) Update(fn func(*ConfigFile) error) error {
+	if configFile.Filename == "" {
+		return errors.New("can't save config with empty filename")
+	}
+	if err := os.MkdirAll(filepath.Dir(configFile.Filename), 0o700); err != nil {
+		return err
+	}
+
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	lock, err := os.OpenFile(configFile.Filename+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to lock config file: %w", err)
+	}
+	defer func() { _ = lock.Close() }()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock config file: %w", err)
+	}
+	defer func() { _ = unlockFile(lock) }()
+
+	current := New(configFile.Filename)
+	if f, err := os.Open(configFile.Filename); err == nil {
+		err = current.LoadFromReader(f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("parsing config file (%s): %w", configFile.Filename, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+
+	if err := fn(current); err != nil {
+		return err
+	}
+	if err := current.Save(); err != nil {
+		return err
+	}
+	return fn(configFile)
+}
+
 // ParseProxyConfig computes proxy configuration by retrieving the config for the provided host and
 // then checking this against any environment variables provided to the container
 func (configFile *ConfigFile) ParseProxyConfig(host string, runOpts map[string]*string) map[string]*string {
@@ -302,16 +382,22 @@ func (configFile *ConfigFile) GetCredentialsStore(registryHostname string) crede
 		return store
 	}
 
-	authConfig, err := parseEnvConfig(envConfig)
+	authConfig, credHelpers, err := parseEnvConfig(envConfig)
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "Failed to create credential store from DOCKER_AUTH_CONFIG: ", err)
 		return store
 	}
 
+	helperStores := make(map[string]credentials.Store, len(credHelpers))
+	for addr, helper := range credHelpers {
+		helperStores[addr] = newNativeStore(configFile, helper)
+	}
+
 	// use DOCKER_AUTH_CONFIG if set
 	// it uses the native or file store as a fallback to fetch and store credentials
 	envStore, err := memorystore.New(
 		memorystore.WithAuthConfig(authConfig),
+		memorystore.WithCredentialHelpers(helperStores),
 		memorystore.WithFallbackStore(store),
 	)
 	if err != nil {
@@ -322,33 +408,61 @@ func (configFile *ConfigFile) GetCredentialsStore(registryHostname string) crede
 	return envStore
 }
 
-func parseEnvConfig(v string) (map[string]types.AuthConfig, error) {
+// parseEnvConfig parses the value of DOCKER_AUTH_CONFIG, and returns the
+// credentials and the credential helpers it configures, by registry.
+func parseEnvConfig(v string) (map[string]types.AuthConfig, map[string]string, error) {
 	envConfig := &configEnv{}
 	decoder := json.NewDecoder(strings.NewReader(v))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(envConfig); err != nil && !errors.Is(err, io.EOF) {
-		return nil, err
+		return nil, nil, err
 	}
 	if decoder.More() {
-		return nil, errors.New("DOCKER_AUTH_CONFIG does not support more than one JSON object")
+		return nil, nil, errors.New("DOCKER_AUTH_CONFIG does not support more than one JSON object")
 	}
 
 	authConfigs := make(map[string]types.AuthConfig)
 	for addr, envAuth := range envConfig.AuthConfigs {
-		if envAuth.Auth == "" {
-			return nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable is missing key `auth` for %s", addr)
+		if addr == "" {
+			return nil, nil, errors.New("DOCKER_AUTH_CONFIG environment variable contains an empty registry address in `auths`")
 		}
-		username, password, err := decodeAuth(envAuth.Auth)
-		if err != nil {
-			return nil, err
+		if envAuth.Auth == "" && envAuth.IdentityToken == "" && envAuth.RegistryToken == "" {
+			return nil, nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable is missing key `auth`, `identitytoken` or `registrytoken` for %s", addr)
+		}
+		if envAuth.RegistryToken != "" && (envAuth.Auth != "" || envAuth.IdentityToken != "") {
+			return nil, nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable: `registrytoken` cannot be combined with `auth` or `identitytoken` for %s", addr)
 		}
-		authConfigs[addr] = types.AuthConfig{
-			Username:      username,
-			Password:      password,
+		authConfig := types.AuthConfig{
 			ServerAddress: addr,
+			IdentityToken: envAuth.IdentityToken,
+			RegistryToken: envAuth.RegistryToken,
+		}
+		if envAuth.Auth != "" {
+			username, password, err := decodeAuth(envAuth.Auth)
+			if err != nil {
+				return nil, nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable has an invalid `auth` for %s: %w", addr, err)
+			}
+			if envAuth.IdentityToken != "" && password != "" {
+				return nil, nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable: `auth` must not contain a password when combined with `identitytoken` for %s", addr)
+			}
+			authConfig.Username = username
+			authConfig.Password = password
+		}
+		authConfigs[addr] = authConfig
+	}
+
+	for addr, helper := range envConfig.CredentialHelpers {
+		if addr == "" {
+			return nil, nil, errors.New("DOCKER_AUTH_CONFIG environment variable contains an empty registry address in `credHelpers`")
+		}
+		if _, ok := authConfigs[addr]; ok {
+			return nil, nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable configures %s in both `auths` and `credHelpers`", addr)
+		}
+		if helper == "" || strings.ContainsAny(helper, `/\ `) {
+			return nil, nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable has an invalid credential helper %q for %s", helper, addr)
 		}
 	}
-	return authConfigs, nil
+	return authConfigs, envConfig.CredentialHelpers, nil
 }
 
 // var for unit testing.
@@ -373,6 +487,39 @@ func getConfiguredCredentialStore(c *ConfigFile, registryHostname string) string
 	return c.CredentialsStore
 }
 
+// Names returned by [ConfigFile.GetCredentialsStoreName] for credentials
+// that are not held by a credential helper.
+const (
+	// CredentialsStoreFile is used for credentials stored in the
+	// configuration file.
+	CredentialsStoreFile = "file"
+	// CredentialsStoreEnv is used for credentials provided through the
+	// DOCKER_AUTH_CONFIG environment variable.
+	CredentialsStoreEnv = "env"
+)
+
+// GetCredentialsStoreName returns the name of the store that holds the
+// credentials for the given registry. It returns [CredentialsStoreEnv] if
+// the credentials are provided through DOCKER_AUTH_CONFIG, the name of the
+// credential helper if one is configured (either through DOCKER_AUTH_CONFIG
+// or the configuration file), or [CredentialsStoreFile].
+func (configFile *ConfigFile) GetCredentialsStoreName(registryHostname string) string {
+	if envConfig := os.Getenv(DockerEnvConfigKey); envConfig != "" {
+		if authConfigs, credHelpers, err := parseEnvConfig(envConfig); err == nil {
+			if _, ok := authConfigs[registryHostname]; ok {
+				return CredentialsStoreEnv
+			}
+			if helper, ok := credHelpers[registryHostname]; ok {
+				return helper
+			}
+		}
+	}
+	if helper := getConfiguredCredentialStore(configFile, registryHostname); helper != "" {
+		return helper
+	}
+	return CredentialsStoreFile
+}
+
 // GetAllCredentials returns all of the credentials stored in all of the
 // configured credential stores.
 func (configFile *ConfigFile) GetAllCredentials() (map[string]types.AuthConfig, error) {
@@ -441,3 +588,14 @@ func (configFile *ConfigFile) SetPluginConfig(pluginname, option, value string)
 		delete(configFile.Plugins, pluginname)
 	}
 }
+
+// UpdatePluginConfig sets the option to the given value for the given plugin
+// (see [ConfigFile.SetPluginConfig]), and saves the configuration file. The
+// configuration file is updated using [ConfigFile.Update], which prevents
+// changes that are made concurrently by other processes from being lost.
+func (configFile *ConfigFile) UpdatePluginConfig(pluginname, option, value string) error {
+	return configFile.Update(func(c *ConfigFile) error {
+		c.SetPluginConfig(pluginname, option, value)
+		return nil
+	})
+}
@@ -584,13 +584,28 @@ func TestGetAllCredentialsFromEnvironment(t *testing.T) {
 	})
 }
 
+func TestGetCredentialsStoreName(t *testing.T) {
+	configFile := New("filename")
+	configFile.CredentialsStore = "default-store"
+	configFile.CredentialHelpers = map[string]string{"helper.example.test": "my-helper"}
+
+	t.Setenv("DOCKER_AUTH_CONFIG", envTestAuthConfig)
+
+	assert.Check(t, is.Equal(configFile.GetCredentialsStoreName("env.example.test"), CredentialsStoreEnv))
+	assert.Check(t, is.Equal(configFile.GetCredentialsStoreName("helper.example.test"), "my-helper"))
+	assert.Check(t, is.Equal(configFile.GetCredentialsStoreName("other.example.test"), "default-store"))
+
+	configFile.CredentialsStore = ""
+	assert.Check(t, is.Equal(configFile.GetCredentialsStoreName("other.example.test"), CredentialsStoreFile))
+}
+
 func TestParseEnvConfig(t *testing.T) {
 	t.Run("should error on unexpected fields", func(t *testing.T) {
-		_, err := parseEnvConfig(envTestUserPassConfig)
+		_, _, err := parseEnvConfig(envTestUserPassConfig)
 		assert.ErrorContains(t, err, "json: unknown field \"username\"")
 	})
 	t.Run("should be able to load env credentials", func(t *testing.T) {
-		got, err := parseEnvConfig(envTestAuthConfig)
+		got, _, err := parseEnvConfig(envTestAuthConfig)
 		assert.NilError(t, err)
 		expected := map[string]types.AuthConfig{
 			"env.example.test": {
@@ -603,9 +618,133 @@ func TestParseEnvConfig(t *testing.T) {
 		assert.Check(t, is.DeepEqual(got, expected))
 	})
 	t.Run("should not support multiple JSON objects", func(t *testing.T) {
-		_, err := parseEnvConfig(`{"auths":{"env.example.test":{"auth":"something"}}}{}`)
+		_, _, err := parseEnvConfig(`{"auths":{"env.example.test":{"auth":"something"}}}{}`)
 		assert.ErrorContains(t, err, "does not support more than one JSON object")
 	})
+	t.Run("should load tokens and credential helpers", func(t *testing.T) {
+		got, credHelpers, err := parseEnvConfig(`{
+			"auths": {
+				"identity.example.test": {"identitytoken": "id-token"},
+				"user-identity.example.test": {"auth": "ZW52X3VzZXI6", "identitytoken": "id-token"},
+				"registry.example.test": {"registrytoken": "reg-token"}
+			},
+			"credHelpers": {
+				"helper.example.test": "ecr-login"
+			}
+		}`)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(got, map[string]types.AuthConfig{
+			"identity.example.test": {
+				ServerAddress: "identity.example.test",
+				IdentityToken: "id-token",
+			},
+			"user-identity.example.test": {
+				Username:      "env_user",
+				ServerAddress: "user-identity.example.test",
+				IdentityToken: "id-token",
+			},
+			"registry.example.test": {
+				ServerAddress: "registry.example.test",
+				RegistryToken: "reg-token",
+			},
+		}))
+		assert.Check(t, is.DeepEqual(credHelpers, map[string]string{"helper.example.test": "ecr-login"}))
+	})
+	t.Run("should validate entries", func(t *testing.T) {
+		for _, tc := range []struct {
+			doc         string
+			config      string
+			expectedErr string
+		}{
+			{
+				doc:         "missing credentials",
+				config:      `{"auths": {"env.example.test": {}}}`,
+				expectedErr: "DOCKER_AUTH_CONFIG environment variable is missing key `auth`, `identitytoken` or `registrytoken` for env.example.test",
+			},
+			{
+				doc:         "invalid auth",
+				config:      `{"auths": {"env.example.test": {"auth": "bm9jb2xvbg=="}}}`,
+				expectedErr: "DOCKER_AUTH_CONFIG environment variable has an invalid `auth` for env.example.test",
+			},
+			{
+				doc:         "registry token combined with auth",
+				config:      `{"auths": {"env.example.test": {"auth": "ZW52X3VzZXI6ZW52X3Bhc3M=", "registrytoken": "reg-token"}}}`,
+				expectedErr: "`registrytoken` cannot be combined with `auth` or `identitytoken` for env.example.test",
+			},
+			{
+				doc:         "identity token with password",
+				config:      `{"auths": {"env.example.test": {"auth": "ZW52X3VzZXI6ZW52X3Bhc3M=", "identitytoken": "id-token"}}}`,
+				expectedErr: "`auth` must not contain a password when combined with `identitytoken` for env.example.test",
+			},
+			{
+				doc:         "empty registry",
+				config:      `{"auths": {"": {"identitytoken": "id-token"}}}`,
+				expectedErr: "contains an empty registry address in `auths`",
+			},
+			{
+				doc:         "registry in auths and credHelpers",
+				config:      `{"auths": {"env.example.test": {"identitytoken": "id-token"}}, "credHelpers": {"env.example.test": "ecr-login"}}`,
+				expectedErr: "configures env.example.test in both `auths` and `credHelpers`",
+			},
+			{
+				doc:         "invalid credential helper",
+				config:      `{"credHelpers": {"env.example.test": "../evil"}}`,
+				expectedErr: `has an invalid credential helper "../evil" for env.example.test`,
+			},
+			{
+				doc:         "unknown field",
+				config:      `{"credsStore": "desktop"}`,
+				expectedErr: `json: unknown field "credsStore"`,
+			},
+		} {
+			t.Run(tc.doc, func(t *testing.T) {
+				_, _, err := parseEnvConfig(tc.config)
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			})
+		}
+	})
+}
+
+func TestGetCredentialsStoreFromEnvironment(t *testing.T) {
+	configFile := New("filename")
+	configFile.CredentialsStore = "file-helper"
+	configFile.CredentialHelpers = map[string]string{"helper.example.test": "file-helper"}
+	configFile.AuthConfigs["file.example.test"] = types.AuthConfig{Username: "file-user", ServerAddress: "file.example.test"}
+
+	helpers := map[string]credentials.Store{
+		"file-helper": NewMockNativeStore(map[string]types.AuthConfig{
+			"helper.example.test": {Username: "file-helper-user", ServerAddress: "helper.example.test"},
+			"file.example.test":   {Username: "file-helper-user", ServerAddress: "file.example.test"},
+		}, nil),
+		"env-helper": NewMockNativeStore(map[string]types.AuthConfig{
+			"helper.example.test": {Username: "env-helper-user", ServerAddress: "helper.example.test"},
+		}, nil),
+	}
+	tmpNewNativeStore := newNativeStore
+	defer func() { newNativeStore = tmpNewNativeStore }()
+	newNativeStore = func(_ *ConfigFile, helperSuffix string) credentials.Store {
+		return helpers[helperSuffix]
+	}
+
+	t.Setenv("DOCKER_AUTH_CONFIG", `{
+		"auths": {"token.example.test": {"identitytoken": "id-token"}},
+		"credHelpers": {"helper.example.test": "env-helper"}
+	}`)
+
+	authConfig, err := configFile.GetAuthConfig("helper.example.test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.Username, "env-helper-user"))
+
+	authConfig, err = configFile.GetAuthConfig("token.example.test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.IdentityToken, "id-token"))
+
+	authConfig, err = configFile.GetAuthConfig("file.example.test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.Username, "file-helper-user"))
+
+	assert.Check(t, is.Equal(configFile.GetCredentialsStoreName("helper.example.test"), "env-helper"))
+	assert.Check(t, is.Equal(configFile.GetCredentialsStoreName("token.example.test"), CredentialsStoreEnv))
 }
 
 func TestSave(t *testing.T) {
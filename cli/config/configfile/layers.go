@@ -0,0 +1,376 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Layer identifies the configuration file that supplied a value.
+type Layer string
+
+const (
+	// LayerDefault is used for values that are not set in any of the
+	// configuration files, such as the credentials store that is detected
+	// for the current platform.
+	LayerDefault Layer = "default"
+	// LayerSystem is the system-wide configuration file, which holds the
+	// defaults for all users.
+	LayerSystem Layer = "system"
+	// LayerUser is the user's configuration file (~/.docker/config.json).
+	// It is the only layer that is written by [ConfigFile.Save].
+	LayerUser Layer = "user"
+	// LayerContext holds the configuration of the current context, which
+	// overrides the user's configuration when the context is used. It
+	// supports the same options as [LayerProject].
+	LayerContext Layer = "context"
+	// LayerProject is the configuration file of the project in the current
+	// working directory. It only supports a limited set of options; see
+	// [ConfigFile.AddLayer].
+	LayerProject Layer = "project"
+)
+
+// layerPrecedence is the order in which layers are merged; values in later
+// layers override values in earlier layers.
+var layerPrecedence = []Layer{LayerSystem, LayerUser, LayerContext, LayerProject}
+
+// ConfigLayer holds the contents of a configuration file that is merged into
+// the configuration.
+type ConfigLayer struct {
+	Layer    Layer
+	Filename string
+	// Data is the normalized content of the configuration file, excluding
+	// options that are not permitted in the layer.
+	Data map[string]any
+}
+
+// Setting is a configuration value, and the configuration file it was
+// supplied by.
+type Setting struct {
+	Key      string
+	Value    any
+	Layer    Layer
+	Filename string
+}
+
+// mergeDepth defines the options holding a map that is merged key-by-key
+// instead of being replaced as a whole, and how many levels are merged.
+var mergeDepth = map[string]int{
+	"auths":       1,
+	"HttpHeaders": 1,
+	"credHelpers": 1,
+	"proxies":     1,
+	"plugins":     2,
+	"aliases":     1,
+	"features":    1,
+}
+
+// projectOptions are the options that are permitted in the project and
+// context layers. Options that could be used to run arbitrary binaries (such
+// as the hooks of "plugins", or "features" that enable them), send credentials
+// or traffic elsewhere, or connect to a different daemon are not permitted,
+// as the project configuration is picked up from the working directory, and
+// contexts can be imported from other users.
+var projectOptions = map[string]bool{
+	"psFormat":             true,
+	"imagesFormat":         true,
+	"networksFormat":       true,
+	"pluginsFormat":        true,
+	"volumesFormat":        true,
+	"statsFormat":          true,
+	"serviceInspectFormat": true,
+	"servicesFormat":       true,
+	"tasksFormat":          true,
+	"secretFormat":         true,
+	"configFormat":         true,
+	"nodesFormat":          true,
+	"detachKeys":           true,
+	"pruneFilters":         true,
+}
+
+// contextOptions are the options that are permitted in the context layer in
+// addition to projectOptions. A context connects to a daemon on the user's
+// behalf, and commonly needs the proxies of the network it's in; the user
+// chooses which context to use.
+var contextOptions = map[string]bool{
+	"proxies": true,
+}
+
+// permitted returns whether the given option can be set in the layer.
+func permitted(layer Layer, option string) bool {
+	switch layer {
+	case LayerUser:
+		return true
+	case LayerProject:
+		return projectOptions[option]
+	case LayerContext:
+		return projectOptions[option] || contextOptions[option]
+	default:
+		// credentials are per-user, and must not be shared.
+		return option != "auths"
+	}
+}
+
+// Permits returns whether the option with the given dotted key (see
+// [ConfigFile.GetOption]) can be set in the layer.
+func (l Layer) Permits(key string) bool {
+	option, _, _ := strings.Cut(key, ".")
+	return permitted(l, option)
+}
+
+// AddLayer reads a system, context, or project configuration file from r,
+// and merges it with the configuration. Values are taken from the
+// configuration file with the highest precedence (project, context, user,
+// system). Options holding a map (such as "proxies" or "features") are
+// merged key-by-key.
+//
+// The system layer cannot hold credentials ("auths"). The context and project
+// layers are limited to formatting options, "detachKeys", and "pruneFilters",
+// and the context layer can also set "proxies". Options that are not permitted are
+// ignored with a warning.
+//
+// The current content of the ConfigFile is used as the user layer.
+func (configFile *ConfigFile) AddLayer(layer Layer, filename string, r io.Reader) error {
+	if layer != LayerSystem && layer != LayerContext && layer != LayerProject {
+		return fmt.Errorf("invalid configuration layer: %s", layer)
+	}
+
+	layerFile := New(filename)
+	if err := layerFile.LoadFromReader(r); err != nil {
+		return err
+	}
+	data, err := toMap(layerFile)
+	if err != nil {
+		return err
+	}
+	if auths, ok := data["auths"].(map[string]any); ok && len(auths) == 0 {
+		delete(data, "auths")
+	}
+	var ignored []string
+	for option := range data {
+		if !permitted(layer, option) {
+			ignored = append(ignored, option)
+			delete(data, option)
+		}
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: ignoring %s in %s: not permitted in the %s configuration\n", strings.Join(ignored, ", "), filename, layer)
+	}
+
+	layers := configFile.Layers
+	if len(layers) == 0 {
+		userData, err := toMap(configFile)
+		if err != nil {
+			return err
+		}
+		layers = []ConfigLayer{{Layer: LayerUser, Filename: configFile.Filename, Data: userData}}
+	}
+	layers = slices.DeleteFunc(slices.Clone(layers), func(l ConfigLayer) bool {
+		return l.Layer == layer
+	})
+	layers = append(layers, ConfigLayer{Layer: layer, Filename: filename, Data: data})
+	sort.SliceStable(layers, func(i, j int) bool {
+		return slices.Index(layerPrecedence, layers[i].Layer) < slices.Index(layerPrecedence, layers[j].Layer)
+	})
+
+	merged := make(map[string]any)
+	for _, l := range layers {
+		for _, v := range flatten(l.Data) {
+			setPath(merged, v.path, v.value)
+		}
+	}
+	mergedJSON, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+
+	mergedFile := New(configFile.Filename)
+	if err := mergedFile.LoadFromReader(bytes.NewReader(mergedJSON)); err != nil {
+		return err
+	}
+	mergedFile.Layers = layers
+	*configFile = *mergedFile
+	return nil
+}
+
+// Settings returns the configuration values, with the configuration file
+// that supplied them, sorted by key. Values that are modified after loading
+// the configuration are reported as [LayerDefault]. Credentials are not
+// included.
+func (configFile *ConfigFile) Settings() ([]Setting, error) {
+	current, err := toMap(configFile)
+	if err != nil {
+		return nil, err
+	}
+	delete(current, "auths")
+
+	origins := configFile.origins()
+	var settings []Setting
+	for _, v := range flatten(current) {
+		s := Setting{Key: v.key(), Value: v.value, Layer: LayerDefault}
+		if len(configFile.Layers) == 0 {
+			s.Layer, s.Filename = LayerUser, configFile.Filename
+		} else if o, ok := origins[s.Key]; ok && reflect.DeepEqual(o.value, v.value) {
+			s.Layer, s.Filename = o.layer, o.filename
+		}
+		settings = append(settings, s)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+type origin struct {
+	leaf
+	layer    Layer
+	filename string
+}
+
+// origins returns the layer that supplied each value, by key.
+func (configFile *ConfigFile) origins() map[string]origin {
+	origins := make(map[string]origin)
+	for _, l := range configFile.Layers {
+		for _, v := range flatten(l.Data) {
+			origins[v.key()] = origin{leaf: v, layer: l.Layer, filename: l.Filename}
+		}
+	}
+	return origins
+}
+
+// userLayerJSON takes the JSON-encoded configuration, and removes the values
+// that are inherited from the system or project layer, restoring the values
+// from the user layer that they override (if any).
+func (configFile *ConfigFile) userLayerJSON(data []byte) ([]byte, error) {
+	var current map[string]any
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil, err
+	}
+
+	var user map[string]any
+	for _, l := range configFile.Layers {
+		if l.Layer == LayerUser {
+			user = l.Data
+		}
+	}
+	for _, o := range configFile.origins() {
+		if o.layer == LayerUser {
+			continue
+		}
+		if v, ok := getPath(current, o.path); !ok || !reflect.DeepEqual(v, o.value) {
+			// the value was changed; store it in the user layer.
+			continue
+		}
+		if v, ok := getPath(user, o.path); ok {
+			setPath(current, o.path, v)
+		} else {
+			deletePath(current, o.path)
+		}
+	}
+
+	// Decode into a ConfigFile to preserve the order of options.
+	userData, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	var userFile ConfigFile
+	if err := json.Unmarshal(userData, &userFile); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(&userFile, "", "\t")
+}
+
+// toMap returns the JSON representation of the ConfigFile as a map.
+func toMap(configFile *ConfigFile) (map[string]any, error) {
+	data, err := json.Marshal(configFile)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// leaf is a value in the configuration that is set by a single layer.
+type leaf struct {
+	path  []string
+	value any
+}
+
+func (l leaf) key() string {
+	return strings.Join(l.path, ".")
+}
+
+// flatten returns the values in the given configuration, descending into
+// options that are merged key-by-key according to mergeDepth.
+func flatten(data map[string]any) []leaf {
+	var leaves []leaf
+	var walk func(path []string, v any, depth int)
+	walk = func(path []string, v any, depth int) {
+		m, ok := v.(map[string]any)
+		if !ok || depth == 0 {
+			leaves = append(leaves, leaf{path: path, value: v})
+			return
+		}
+		for k, child := range m {
+			walk(append(slices.Clone(path), k), child, depth-1)
+		}
+	}
+	for option, v := range data {
+		walk([]string{option}, v, mergeDepth[option])
+	}
+	return leaves
+}
+
+func getPath(m map[string]any, path []string) (any, bool) {
+	var v any = m
+	for _, p := range path {
+		mm, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = mm[p]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func setPath(m map[string]any, path []string, value any) {
+	for _, p := range path[:len(path)-1] {
+		child, ok := m[p].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			m[p] = child
+		}
+		m = child
+	}
+	m[path[len(path)-1]] = value
+}
+
+// deletePath deletes the value at the given path, and removes maps that are
+// left empty.
+func deletePath(m map[string]any, path []string) {
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+	child, ok := m[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+	deletePath(child, path[1:])
+	if len(child) == 0 {
+		delete(m, path[0])
+	}
+}
@@ -0,0 +1,170 @@
+package configfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const (
+	systemLayer = `{
+	"psFormat": "table {{.ID}}",
+	"imagesFormat": "table {{.Repository}}",
+	"credsStore": "secretservice",
+	"auths": {"registry.example.com": {"auth": "dXNlcjpwYXNz"}},
+	"proxies": {"default": {"httpProxy": "http://proxy.example.com:3128"}},
+	"plugins": {"buildx": {"builder": "system-builder"}},
+	"features": {"hooks": "true", "system-feature": "true"}
+}`
+	userLayer = `{
+	"auths": {"user.example.com": {"auth": "dXNlcjpwYXNz"}},
+	"psFormat": "table {{.Names}}",
+	"features": {"hooks": "false"}
+}`
+	projectLayer = `{
+	"imagesFormat": "table {{.ID}}\t{{.Tag}}",
+	"credsStore": "evil",
+	"cliPluginsExtraDirs": ["./plugins"],
+	"proxies": {"default": {"httpProxy": "http://evil.example.com:3128"}},
+	"plugins": {"buildx": {"builder": "project-builder"}},
+	"features": {"hooks": "true"}
+}`
+)
+
+func newLayeredConfig(t *testing.T) *ConfigFile {
+	t.Helper()
+	configFile := New("/home/user/.docker/config.json")
+	assert.NilError(t, configFile.LoadFromReader(strings.NewReader(userLayer)))
+	assert.NilError(t, configFile.AddLayer(LayerProject, "/src/project/.docker/config.json", strings.NewReader(projectLayer)))
+	assert.NilError(t, configFile.AddLayer(LayerSystem, "/etc/docker/cli-config.json", strings.NewReader(systemLayer)))
+	return configFile
+}
+
+func TestAddLayer(t *testing.T) {
+	configFile := newLayeredConfig(t)
+
+	assert.Check(t, is.Equal(configFile.Filename, "/home/user/.docker/config.json"))
+	assert.Check(t, is.Equal(configFile.PsFormat, "table {{.Names}}"))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "table {{.ID}}\t{{.Tag}}"))
+	assert.Check(t, is.Equal(configFile.CredentialsStore, "secretservice"))
+	assert.Check(t, is.Len(configFile.CLIPluginsExtraDirs, 0))
+	assert.Check(t, is.Equal(configFile.Proxies["default"].HTTPProxy, "http://proxy.example.com:3128"))
+	assert.Check(t, is.DeepEqual(configFile.Features, map[string]string{"hooks": "false", "system-feature": "true"}))
+	assert.Check(t, is.DeepEqual(configFile.Plugins, map[string]map[string]string{"buildx": {"builder": "system-builder"}}))
+
+	// credentials are only taken from the user layer
+	assert.Check(t, is.Len(configFile.AuthConfigs, 1))
+	assert.Check(t, is.Equal(configFile.AuthConfigs["user.example.com"].Username, "user"))
+	assert.Check(t, is.Equal(configFile.AuthConfigs["user.example.com"].Password, "pass"))
+}
+
+func TestAddLayerInvalid(t *testing.T) {
+	configFile := New("config.json")
+	err := configFile.AddLayer(LayerUser, "config.json", strings.NewReader(`{}`))
+	assert.Check(t, is.Error(err, "invalid configuration layer: user"))
+
+	err = configFile.AddLayer(LayerSystem, "cli-config.json", strings.NewReader(`{"psFormat": 1}`))
+	assert.Check(t, is.ErrorContains(err, "cannot unmarshal number"))
+	assert.Check(t, is.Len(configFile.Layers, 0))
+}
+
+func TestSettings(t *testing.T) {
+	configFile := newLayeredConfig(t)
+	configFile.DetachKeys = "ctrl-x"
+
+	settings, err := configFile.Settings()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(settings, []Setting{
+		{Key: "credsStore", Value: "secretservice", Layer: LayerSystem, Filename: "/etc/docker/cli-config.json"},
+		{Key: "detachKeys", Value: "ctrl-x", Layer: LayerDefault},
+		{Key: "features.hooks", Value: "false", Layer: LayerUser, Filename: "/home/user/.docker/config.json"},
+		{Key: "features.system-feature", Value: "true", Layer: LayerSystem, Filename: "/etc/docker/cli-config.json"},
+		{Key: "imagesFormat", Value: "table {{.ID}}\t{{.Tag}}", Layer: LayerProject, Filename: "/src/project/.docker/config.json"},
+		{Key: "plugins.buildx.builder", Value: "system-builder", Layer: LayerSystem, Filename: "/etc/docker/cli-config.json"},
+		{Key: "proxies.default", Value: map[string]any{"httpProxy": "http://proxy.example.com:3128"}, Layer: LayerSystem, Filename: "/etc/docker/cli-config.json"},
+		{Key: "psFormat", Value: "table {{.Names}}", Layer: LayerUser, Filename: "/home/user/.docker/config.json"},
+	}))
+}
+
+func TestSettingsWithoutLayers(t *testing.T) {
+	configFile := New("/home/user/.docker/config.json")
+	assert.NilError(t, configFile.LoadFromReader(strings.NewReader(userLayer)))
+
+	settings, err := configFile.Settings()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(settings, []Setting{
+		{Key: "features.hooks", Value: "false", Layer: LayerUser, Filename: "/home/user/.docker/config.json"},
+		{Key: "psFormat", Value: "table {{.Names}}", Layer: LayerUser, Filename: "/home/user/.docker/config.json"},
+	}))
+}
+
+func TestSaveLayered(t *testing.T) {
+	configFile := newLayeredConfig(t)
+	configFile.DetachKeys = "ctrl-x"
+	configFile.Plugins["buildx"]["builder"] = "my-builder"
+	configFile.AuthConfigs["new.example.com"] = configFile.AuthConfigs["user.example.com"]
+
+	var buf bytes.Buffer
+	assert.NilError(t, configFile.SaveToWriter(&buf))
+	assert.Check(t, is.Equal(buf.String(), `{
+	"auths": {
+		"new.example.com": {
+			"auth": "dXNlcjpwYXNz"
+		},
+		"user.example.com": {
+			"auth": "dXNlcjpwYXNz"
+		}
+	},
+	"psFormat": "table {{.Names}}",
+	"detachKeys": "ctrl-x",
+	"plugins": {
+		"buildx": {
+			"builder": "my-builder"
+		}
+	},
+	"features": {
+		"hooks": "false"
+	}
+}`))
+
+	// the merged configuration is not modified by saving.
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "table {{.ID}}\t{{.Tag}}"))
+	assert.Check(t, is.Equal(configFile.Features["system-feature"], "true"))
+}
+
+func TestAddLayerContext(t *testing.T) {
+	configFile := newLayeredConfig(t)
+	const contextLayer = `{
+	"psFormat": "table {{.ID}}\t{{.Status}}",
+	"imagesFormat": "table {{.Repository}}",
+	"proxies": {"default": {"httpProxy": "http://edge-proxy.example.com:3128"}},
+	"credsStore": "evil"
+}`
+	assert.NilError(t, configFile.AddLayer(LayerContext, "/home/user/.docker/contexts/meta/1234/meta.json", strings.NewReader(contextLayer)))
+
+	// the context overrides the user's configuration, but not the project's.
+	assert.Check(t, is.Equal(configFile.PsFormat, "table {{.ID}}\t{{.Status}}"))
+	assert.Check(t, is.Equal(configFile.ImagesFormat, "table {{.ID}}\t{{.Tag}}"))
+	assert.Check(t, is.Equal(configFile.Proxies["default"].HTTPProxy, "http://edge-proxy.example.com:3128"))
+	assert.Check(t, is.Equal(configFile.CredentialsStore, "secretservice"))
+
+	var buf bytes.Buffer
+	assert.NilError(t, configFile.SaveToWriter(&buf))
+	assert.Check(t, !strings.Contains(buf.String(), "edge-proxy"), "context configuration must not be saved")
+}
+
+func TestLayerPermits(t *testing.T) {
+	assert.Check(t, LayerContext.Permits("psFormat"))
+	assert.Check(t, LayerContext.Permits("proxies.default.httpProxy"))
+	assert.Check(t, LayerProject.Permits("psFormat"))
+	assert.Check(t, !LayerProject.Permits("proxies.default.httpProxy"))
+	assert.Check(t, !LayerProject.Permits("plugins.buildx.builder"))
+	assert.Check(t, !LayerProject.Permits("features.hooks"))
+	assert.Check(t, !LayerContext.Permits("features.hooks"))
+	assert.Check(t, !LayerContext.Permits("credsStore"))
+	assert.Check(t, !LayerContext.Permits("cliPluginsExtraDirs"))
+	assert.Check(t, LayerUser.Permits("credsStore"))
+}
@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package configfile
+
+import "os"
+
+// lockFile is a no-op on platforms that don't support file locking; updates
+// are only serialized within the current process.
+func lockFile(*os.File) error {
+	return nil
+}
+
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,332 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package configfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/internal/contextname"
+	"github.com/docker/cli/templates"
+	"github.com/moby/term"
+)
+
+// optionKind describes the structure of an option's key.
+type optionKind int
+
+const (
+	// scalarOption is an option without sub-keys ("psFormat").
+	scalarOption optionKind = iota
+	// mapOption is an option holding a map ("features.<name>").
+	mapOption
+	// pluginOption is a per-plugin option ("plugins.<plugin>.<option>").
+	pluginOption
+	// proxyOption is a per-host proxy option ("proxies.<host>.<field>"),
+	// where the host may contain dots.
+	proxyOption
+)
+
+type optionSpec struct {
+	kind optionKind
+	// list is set for options holding a list of strings.
+	list bool
+	// validate validates a value, and returns the value to store.
+	validate func(key, value string) (any, error)
+}
+
+var formatOption = optionSpec{validate: validateFormat}
+
+// options are the options that can be get and set using [ConfigFile.GetOption]
+// and [ConfigFile.SetOption], by name.
+var options = map[string]optionSpec{
+	"HttpHeaders":          {kind: mapOption, validate: validateHTTPHeader},
+	"psFormat":             formatOption,
+	"imagesFormat":         formatOption,
+	"networksFormat":       formatOption,
+	"pluginsFormat":        formatOption,
+	"volumesFormat":        formatOption,
+	"statsFormat":          formatOption,
+	"detachKeys":           {validate: validateDetachKeys},
+	"credsStore":           {validate: validateCredentialHelper},
+	"credHelpers":          {kind: mapOption, validate: validateCredentialHelper},
+	"serviceInspectFormat": formatOption,
+	"servicesFormat":       formatOption,
+	"tasksFormat":          formatOption,
+	"secretFormat":         formatOption,
+	"configFormat":         formatOption,
+	"nodesFormat":          formatOption,
+	"pruneFilters":         {list: true, validate: validatePruneFilter},
+	"proxies":              {kind: proxyOption, validate: validateProxy},
+	"currentContext":       {validate: validateContextName},
+	"cliPluginsExtraDirs":  {list: true, validate: validateNotEmpty},
+	"plugins":              {kind: pluginOption, validate: validateNotEmpty},
+	"aliases":              {kind: mapOption, validate: validateNotEmpty},
+	"features":             {kind: mapOption, validate: validateBool},
+}
+
+// proxyFields are the fields of a [ProxyConfig].
+var proxyFields = map[string]bool{
+	"httpProxy":  true,
+	"httpsProxy": true,
+	"noProxy":    true,
+	"ftpProxy":   true,
+	"allProxy":   true,
+}
+
+// OptionNames returns the names of the options that can be get and set using
+// [ConfigFile.GetOption] and [ConfigFile.SetOption], sorted by name.
+func OptionNames() []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseOptionKey splits a dotted key into the option's spec, and the path of
+// the value in the JSON representation of the configuration file. Keys may
+// refer to a whole option holding a map ("proxies") or a part of it
+// ("plugins.buildx") unless exact is set, in which case the key must refer to
+// a single value.
+func parseOptionKey(key string, exact bool) (optionSpec, []string, error) {
+	name, rest, hasRest := strings.Cut(key, ".")
+	if name == "auths" {
+		return optionSpec{}, nil, errors.New(`credentials ("auths") cannot be modified with this command; use "docker login" and "docker logout" instead`)
+	}
+	spec, ok := options[name]
+	if !ok {
+		return optionSpec{}, nil, fmt.Errorf("unknown configuration option: %s", key)
+	}
+	if hasRest && rest == "" {
+		return optionSpec{}, nil, fmt.Errorf("invalid configuration option: %s", key)
+	}
+
+	path := []string{name}
+	switch spec.kind {
+	case scalarOption:
+		if hasRest {
+			return optionSpec{}, nil, fmt.Errorf("invalid configuration option: %s: %s does not have sub-keys", key, name)
+		}
+		return spec, path, nil
+	case mapOption:
+		if hasRest {
+			path = append(path, rest)
+		}
+	case pluginOption:
+		if plugin, option, ok := strings.Cut(rest, "."); ok {
+			if plugin == "" || option == "" {
+				return optionSpec{}, nil, fmt.Errorf("invalid configuration option: %s", key)
+			}
+			path = append(path, plugin, option)
+		} else if hasRest {
+			path = append(path, rest)
+		}
+	case proxyOption:
+		if i := strings.LastIndex(rest, "."); i > 0 && proxyFields[rest[i+1:]] {
+			path = append(path, rest[:i], rest[i+1:])
+		} else if hasRest {
+			path = append(path, rest)
+		}
+	}
+	if exact {
+		if want := map[optionKind]int{mapOption: 2, pluginOption: 3, proxyOption: 3}[spec.kind]; len(path) != want {
+			return optionSpec{}, nil, fmt.Errorf("invalid configuration option: %s: %s", key, exampleKey(name, spec))
+		}
+	}
+	return spec, path, nil
+}
+
+func exampleKey(name string, spec optionSpec) string {
+	switch spec.kind {
+	case mapOption:
+		return "use " + name + ".<name>"
+	case pluginOption:
+		return "use " + name + ".<plugin>.<option>"
+	case proxyOption:
+		return "use " + name + ".<host>.<httpProxy|httpsProxy|noProxy|ftpProxy|allProxy>"
+	default:
+		return ""
+	}
+}
+
+// GetOption returns the value of the option with the given dotted key, for
+// example "psFormat", "features.hooks", "plugins.buildx.builder", or
+// "proxies.default.httpProxy". Keys that refer to an option holding a map
+// ("proxies") or a part of it ("plugins.buildx") return the map. Values are
+// returned in their JSON representation; a string, a []any, or a
+// map[string]any. GetOption returns false if the option is not set.
+func (configFile *ConfigFile) GetOption(key string) (any, bool, error) {
+	_, path, err := parseOptionKey(key, false)
+	if err != nil {
+		return nil, false, err
+	}
+	current, err := toMap(configFile)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := getPath(current, path)
+	return v, ok, nil
+}
+
+// SetOption validates the value, and sets the option with the given dotted
+// key (see [ConfigFile.GetOption]) to it. Options holding a list accept a
+// comma-separated list of values, or a JSON array. Credentials ("auths")
+// cannot be set.
+func (configFile *ConfigFile) SetOption(key, value string) error {
+	spec, path, err := parseOptionKey(key, true)
+	if err != nil {
+		return err
+	}
+
+	var v any
+	if spec.list {
+		var items []string
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			if err := json.Unmarshal([]byte(value), &items); err != nil {
+				return fmt.Errorf("invalid value for %s: %w", key, err)
+			}
+		} else if value != "" {
+			items = strings.Split(value, ",")
+		}
+		list := make([]any, 0, len(items))
+		for _, item := range items {
+			iv, err := spec.validate(key, strings.TrimSpace(item))
+			if err != nil {
+				return err
+			}
+			list = append(list, iv)
+		}
+		v = list
+	} else {
+		if v, err = spec.validate(key, value); err != nil {
+			return err
+		}
+	}
+	return configFile.updateOptions(func(current map[string]any) {
+		setPath(current, path, v)
+	})
+}
+
+// UnsetOption removes the option with the given dotted key (see
+// [ConfigFile.GetOption]). It returns false if the option was not set.
+func (configFile *ConfigFile) UnsetOption(key string) (bool, error) {
+	_, path, err := parseOptionKey(key, false)
+	if err != nil {
+		return false, err
+	}
+	var found bool
+	err = configFile.updateOptions(func(current map[string]any) {
+		if _, found = getPath(current, path); found {
+			deletePath(current, path)
+		}
+	})
+	return found, err
+}
+
+// updateOptions applies fn to the JSON representation of the configuration,
+// and updates the configuration with the result.
+func (configFile *ConfigFile) updateOptions(fn func(current map[string]any)) error {
+	current, err := toMap(configFile)
+	if err != nil {
+		return err
+	}
+	fn(current)
+	data, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	updated := New(configFile.Filename)
+	if err := json.Unmarshal(data, updated); err != nil {
+		return err
+	}
+	updated.Layers = configFile.Layers
+	*configFile = *updated
+	return nil
+}
+
+func validateNotEmpty(key, value string) (any, error) {
+	if value == "" {
+		return nil, fmt.Errorf("invalid value for %s: value cannot be empty", key)
+	}
+	return value, nil
+}
+
+func validateBool(key, value string) (any, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %q is not a boolean", key, value)
+	}
+	return strconv.FormatBool(b), nil
+}
+
+// validateFormat validates a format option, which can be "table", "json",
+// or a Go template (optionally prefixed with "table").
+func validateFormat(key, value string) (any, error) {
+	tmpl := strings.TrimSpace(strings.TrimPrefix(value, "table"))
+	if tmpl == "" || tmpl == "json" {
+		return value, nil
+	}
+	if _, err := templates.Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func validateDetachKeys(key, value string) (any, error) {
+	if _, err := term.ToBytes(value); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func validateCredentialHelper(key, value string) (any, error) {
+	if value == "" || strings.ContainsAny(value, `/\ `) {
+		return nil, fmt.Errorf("invalid value for %s: invalid credential helper name %q", key, value)
+	}
+	return value, nil
+}
+
+func validateHTTPHeader(key, value string) (any, error) {
+	_, name, _ := strings.Cut(key, ".")
+	if strings.ContainsAny(name, " :\t\r\n") {
+		return nil, fmt.Errorf("invalid configuration option: %s: invalid header name %q", key, name)
+	}
+	if strings.EqualFold(name, "User-Agent") {
+		return nil, fmt.Errorf("invalid configuration option: %s: the User-Agent header cannot be set", key)
+	}
+	return value, nil
+}
+
+func validatePruneFilter(key, value string) (any, error) {
+	if k, _, ok := strings.Cut(value, "="); !ok || k == "" {
+		return nil, fmt.Errorf("invalid value for %s: %q is not in key=value format", key, value)
+	}
+	return value, nil
+}
+
+func validateProxy(key, value string) (any, error) {
+	if value == "" || strings.HasSuffix(key, ".noProxy") {
+		return value, nil
+	}
+	if _, err := url.Parse(value); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func validateContextName(key, value string) (any, error) {
+	if value == "default" {
+		return value, nil
+	}
+	if err := contextname.Validate(value); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return value, nil
+}
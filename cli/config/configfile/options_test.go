@@ -0,0 +1,143 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package configfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSetOption(t *testing.T) {
+	for _, tc := range []struct {
+		key, value  string
+		expected    any
+		expectedErr string
+	}{
+		{key: "psFormat", value: "table {{.ID}}\t{{.Names}}", expected: "table {{.ID}}\t{{.Names}}"},
+		{key: "psFormat", value: "json", expected: "json"},
+		{key: "psFormat", value: "table {{.ID}", expectedErr: `invalid value for psFormat: template: :1: bad character U+007D '}'`},
+		{key: "detachKeys", value: "ctrl-x,x", expected: "ctrl-x,x"},
+		{key: "detachKeys", value: "ctrl-", expectedErr: "invalid value for detachKeys"},
+		{key: "credsStore", value: "pass", expected: "pass"},
+		{key: "credsStore", value: "../evil", expectedErr: `invalid value for credsStore: invalid credential helper name "../evil"`},
+		{key: "credHelpers.registry.example.com", value: "ecr-login", expected: "ecr-login"},
+		{key: "currentContext", value: "my-context", expected: "my-context"},
+		{key: "currentContext", value: "-invalid", expectedErr: "invalid value for currentContext: context name \"-invalid\" is invalid"},
+		{key: "features.hooks", value: "1", expected: "true"},
+		{key: "features.hooks", value: "maybe", expectedErr: `invalid value for features.hooks: "maybe" is not a boolean`},
+		{key: "features", value: "true", expectedErr: "invalid configuration option: features: use features.<name>"},
+		{key: "plugins.buildx.builder", value: "my-builder", expected: "my-builder"},
+		{key: "plugins.buildx", value: "my-builder", expectedErr: "invalid configuration option: plugins.buildx: use plugins.<plugin>.<option>"},
+		{key: "proxies.default.httpProxy", value: "http://proxy.example.com:3128", expected: "http://proxy.example.com:3128"},
+		{key: "proxies.tcp://docker.example.com:2376.noProxy", value: "*.example.com", expected: "*.example.com"},
+		{key: "proxies.default.socksProxy", value: "socks5://proxy", expectedErr: "invalid configuration option: proxies.default.socksProxy: use proxies.<host>.<httpProxy|httpsProxy|noProxy|ftpProxy|allProxy>"},
+		{key: "pruneFilters", value: "label=foo, until=24h", expected: []any{"label=foo", "until=24h"}},
+		{key: "pruneFilters", value: `["label=a,b"]`, expected: []any{"label=a,b"}},
+		{key: "pruneFilters", value: "foo", expectedErr: `invalid value for pruneFilters: "foo" is not in key=value format`},
+		{key: "HttpHeaders.X-Meta", value: "hello", expected: "hello"},
+		{key: "HttpHeaders.User-Agent", value: "hello", expectedErr: "the User-Agent header cannot be set"},
+		{key: "psFormat.foo", value: "x", expectedErr: "invalid configuration option: psFormat.foo: psFormat does not have sub-keys"},
+		{key: "auths.registry.example.com", value: "x", expectedErr: `credentials ("auths") cannot be modified with this command`},
+		{key: "noSuchOption", value: "x", expectedErr: "unknown configuration option: noSuchOption"},
+	} {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			configFile := New("config.json")
+			err := configFile.SetOption(tc.key, tc.value)
+			if tc.expectedErr != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			v, ok, err := configFile.GetOption(tc.key)
+			assert.NilError(t, err)
+			assert.Check(t, ok)
+			assert.Check(t, is.DeepEqual(v, tc.expected))
+		})
+	}
+}
+
+func TestSetOptionUpdatesFields(t *testing.T) {
+	configFile := New("config.json")
+	configFile.AuthConfigs["registry.example.com"] = types.AuthConfig{Username: "user", Password: "pass"}
+
+	assert.NilError(t, configFile.SetOption("psFormat", "table {{.ID}}"))
+	assert.NilError(t, configFile.SetOption("proxies.tcp://docker.example.com:2376.httpProxy", "http://proxy:3128"))
+	assert.NilError(t, configFile.SetOption("plugins.buildx.builder", "my-builder"))
+
+	assert.Check(t, is.Equal(configFile.PsFormat, "table {{.ID}}"))
+	assert.Check(t, is.Equal(configFile.Proxies["tcp://docker.example.com:2376"].HTTPProxy, "http://proxy:3128"))
+	v, _ := configFile.PluginConfig("buildx", "builder")
+	assert.Check(t, is.Equal(v, "my-builder"))
+	assert.Check(t, is.Equal(configFile.AuthConfigs["registry.example.com"].Password, "pass"))
+	assert.Check(t, is.Equal(configFile.Filename, "config.json"))
+
+	v2, ok, err := configFile.GetOption("proxies")
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Check(t, is.DeepEqual(v2, map[string]any{"tcp://docker.example.com:2376": map[string]any{"httpProxy": "http://proxy:3128"}}))
+}
+
+func TestUnsetOption(t *testing.T) {
+	configFile := New("config.json")
+	assert.NilError(t, configFile.SetOption("plugins.buildx.builder", "my-builder"))
+	assert.NilError(t, configFile.SetOption("features.hooks", "true"))
+
+	removed, err := configFile.UnsetOption("plugins.buildx.builder")
+	assert.NilError(t, err)
+	assert.Check(t, removed)
+	assert.Check(t, is.Len(configFile.Plugins, 0))
+
+	removed, err = configFile.UnsetOption("plugins.buildx.builder")
+	assert.NilError(t, err)
+	assert.Check(t, !removed)
+
+	removed, err = configFile.UnsetOption("features")
+	assert.NilError(t, err)
+	assert.Check(t, removed)
+	assert.Check(t, is.Len(configFile.Features, 0))
+
+	_, err = configFile.UnsetOption("auths")
+	assert.Check(t, is.ErrorContains(err, "cannot be modified with this command"))
+}
+
+func TestUpdate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	assert.NilError(t, os.WriteFile(filename, []byte(`{"psFormat": "table {{.ID}}"}`), 0o600))
+
+	configFile := New(filename)
+	const n = 20
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each update uses its own (stale) copy of the configuration,
+			// as would be the case for separate processes.
+			cfg := New(filename)
+			assert.Check(t, cfg.UpdatePluginConfig("myplugin", fmt.Sprintf("option%d", i), "value"))
+		}()
+	}
+	wg.Wait()
+
+	assert.NilError(t, configFile.Update(func(c *ConfigFile) error {
+		return c.SetOption("features.hooks", "true")
+	}))
+	assert.Check(t, is.Equal(configFile.Features["hooks"], "true"))
+
+	data, err := os.ReadFile(filename)
+	assert.NilError(t, err)
+	saved := New(filename)
+	assert.NilError(t, saved.LoadFromReader(strings.NewReader(string(data))))
+	assert.Check(t, is.Equal(saved.PsFormat, "table {{.ID}}"))
+	assert.Check(t, is.Len(saved.Plugins["myplugin"], n))
+	assert.Check(t, is.Equal(saved.Features["hooks"], "true"))
+}
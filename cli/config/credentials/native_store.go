@@ -9,6 +9,11 @@
 const (
 	remoteCredentialsPrefix = "docker-credential-" //nolint:gosec // ignore G101: Potential hardcoded credentials
 	tokenUsername           = "<token>"
+
+	// ContextTLSKeyServerAddress is the server address under which the key
+	// to encrypt the TLS data of contexts is kept in a credential helper.
+	// It's not a registry, and is not returned by [Store.GetAll].
+	ContextTLSKeyServerAddress = "docker-context-tls-key"
 )
 
 // nativeStore implements a credentials store
@@ -69,6 +74,9 @@ func (c *nativeStore) GetAll() (map[string]types.AuthConfig, error) {
 
 	authConfigs := make(map[string]types.AuthConfig)
 	for registry := range auths {
+		if registry == ContextTLSKeyServerAddress {
+			continue
+		}
 		creds, err := c.getCredentialsFromStore(registry)
 		if err != nil {
 			return nil, err
@@ -73,7 +73,7 @@ func (m *mockCommand) Output() ([]byte, error) {
 			return []byte("program failed"), errCommandExited
 		}
 	case "list":
-		return fmt.Appendf(nil, `{"%s": "%s", "%s": "%s"}`, validServerAddress, "foo", validServerAddress2, "<token>"), nil
+		return fmt.Appendf(nil, `{"%s": "%s", "%s": "%s", "%s": "%s"}`, validServerAddress, "foo", validServerAddress2, "<token>", ContextTLSKeyServerAddress, "docker-context"), nil
 	}
 
 	return fmt.Appendf(nil, "unknown argument %q with %q", m.arg, inS), errCommandExited
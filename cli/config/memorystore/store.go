@@ -27,16 +27,27 @@ func (e notFoundErr) Error() string {
 type Config struct {
 	lock              sync.RWMutex
 	memoryCredentials map[string]types.AuthConfig
+	helperStores      map[string]credentials.Store
 	fallbackStore     credentials.Store
 }
 
+// backingStore returns the store that backs the credentials for the given
+// server address; the credential helper configured for the address, or the
+// fallback store. It returns nil if neither is configured.
+func (e *Config) backingStore(serverAddress string) credentials.Store {
+	if store, ok := e.helperStores[serverAddress]; ok {
+		return store
+	}
+	return e.fallbackStore
+}
+
 func (e *Config) Erase(serverAddress string) error {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 	delete(e.memoryCredentials, serverAddress)
 
-	if e.fallbackStore != nil {
-		err := e.fallbackStore.Erase(serverAddress)
+	if store := e.backingStore(serverAddress); store != nil {
+		err := store.Erase(serverAddress)
 		if err != nil {
 			_, _ = fmt.Fprintln(os.Stderr, "memorystore: ", err)
 		}
@@ -50,8 +61,8 @@ func (e *Config) Get(serverAddress string) (types.AuthConfig, error) {
 	defer e.lock.RUnlock()
 	authConfig, ok := e.memoryCredentials[serverAddress]
 	if !ok {
-		if e.fallbackStore != nil {
-			return e.fallbackStore.Get(serverAddress)
+		if store := e.backingStore(serverAddress); store != nil {
+			return store.Get(serverAddress)
 		}
 		return types.AuthConfig{}, errValueNotFound
 	}
@@ -72,6 +83,15 @@ func (e *Config) GetAll() (map[string]types.AuthConfig, error) {
 		}
 	}
 
+	for serverAddress, store := range e.helperStores {
+		authConfig, err := store.Get(serverAddress)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, "memorystore: ", err)
+			continue
+		}
+		creds[serverAddress] = authConfig
+	}
+
 	maps.Copy(creds, e.memoryCredentials)
 	return creds, nil
 }
@@ -81,8 +101,8 @@ func (e *Config) Store(authConfig types.AuthConfig) error {
 	defer e.lock.Unlock()
 	e.memoryCredentials[authConfig.ServerAddress] = authConfig
 
-	if e.fallbackStore != nil {
-		return e.fallbackStore.Store(authConfig)
+	if store := e.backingStore(authConfig.ServerAddress); store != nil {
+		return store.Store(authConfig)
 	}
 	return nil
 }
@@ -107,6 +127,19 @@ func WithFallbackStore(store credentials.Store) Options {
 	}
 }
 
+// WithCredentialHelpers sets the stores to use for specific server
+// addresses, such as credential helpers.
+//
+// Read and write operations for these server addresses are performed on the
+// given store instead of the fallback store. Credentials in the memory store
+// take precedence over credentials in these stores.
+func WithCredentialHelpers(stores map[string]credentials.Store) Options {
+	return func(s *Config) error {
+		s.helperStores = stores
+		return nil
+	}
+}
+
 // WithAuthConfig allows to set the initial credentials in the memory store.
 func WithAuthConfig(config map[string]types.AuthConfig) Options {
 	return func(s *Config) error {
@@ -3,6 +3,7 @@
 import (
 	"testing"
 
+	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/types"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -129,3 +130,60 @@ func TestMemoryStoreWithoutFallback(t *testing.T) {
 		assert.Check(t, is.ErrorIs(err, errValueNotFound))
 	})
 }
+
+func TestMemoryStoreWithCredentialHelpers(t *testing.T) {
+	config := map[string]types.AuthConfig{
+		"both.example.test": {Username: "memory-user", ServerAddress: "both.example.test"},
+	}
+	helperStore, err := New(WithAuthConfig(map[string]types.AuthConfig{
+		"helper.example.test": {Username: "helper-user", ServerAddress: "helper.example.test"},
+		"both.example.test":   {Username: "helper-user", ServerAddress: "both.example.test"},
+	}))
+	assert.NilError(t, err)
+	fallbackStore, err := New(WithAuthConfig(map[string]types.AuthConfig{
+		"helper.example.test": {Username: "file-user", ServerAddress: "helper.example.test"},
+		"file.example.test":   {Username: "file-user", ServerAddress: "file.example.test"},
+	}))
+	assert.NilError(t, err)
+
+	memoryStore, err := New(
+		WithAuthConfig(config),
+		WithCredentialHelpers(map[string]credentials.Store{
+			"helper.example.test": helperStore,
+			"both.example.test":   helperStore,
+		}),
+		WithFallbackStore(fallbackStore),
+	)
+	assert.NilError(t, err)
+
+	t.Run("memory store takes precedence over credential helper", func(t *testing.T) {
+		c, err := memoryStore.Get("both.example.test")
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(c.Username, "memory-user"))
+	})
+
+	t.Run("credential helper takes precedence over fallback store", func(t *testing.T) {
+		c, err := memoryStore.Get("helper.example.test")
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(c.Username, "helper-user"))
+	})
+
+	t.Run("get all merges all stores", func(t *testing.T) {
+		all, err := memoryStore.GetAll()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(all["both.example.test"].Username, "memory-user"))
+		assert.Check(t, is.Equal(all["helper.example.test"].Username, "helper-user"))
+		assert.Check(t, is.Equal(all["file.example.test"].Username, "file-user"))
+	})
+
+	t.Run("storing credentials uses the credential helper", func(t *testing.T) {
+		err := memoryStore.Store(types.AuthConfig{Username: "new-user", ServerAddress: "helper.example.test"})
+		assert.NilError(t, err)
+		c, err := helperStore.Get("helper.example.test")
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(c.Username, "new-user"))
+		c, err = fallbackStore.Get("helper.example.test")
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(c.Username, "file-user"))
+	})
+}
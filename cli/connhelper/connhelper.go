@@ -13,6 +13,7 @@
 	"strings"
 
 	"github.com/docker/cli/cli/connhelper/commandconn"
+	"github.com/docker/cli/cli/connhelper/internal/nativessh"
 	"github.com/docker/cli/cli/connhelper/ssh"
 )
 
@@ -52,12 +53,7 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 		sshFlags = addSSHTimeout(sshFlags)
 		sshFlags = disablePseudoTerminalAllocation(sshFlags)
 
-		remoteCommand := []string{"docker", "system", "dial-stdio"}
-		socketPath := sp.Path
-		if strings.Trim(sp.Path, "/") != "" {
-			remoteCommand = []string{"docker", "--host=unix://" + socketPath, "system", "dial-stdio"}
-		}
-		sshArgs, err := sp.Command(sshFlags, remoteCommand...)
+		sshArgs, err := sp.Command(sshFlags, dialStdioCommand(sp)...)
 		if err != nil {
 			return nil, err
 		}
@@ -73,6 +69,50 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 	return nil, err
 }
 
+// GetNativeSSHConnectionHelper returns a connection helper for the given
+// ssh:// URL, which connects to the remote host using the built-in ssh
+// client instead of the ssh binary. The ssh connection is shared by all
+// connections that are returned by the helper's dialer.
+//
+// Only a subset of the ssh options is supported by the built-in client; an
+// error is returned for unsupported options.
+//
+// Requires Docker 18.09 or later on the remote host.
+func GetNativeSSHConnectionHelper(daemonURL string, opts ssh.Options) (*ConnectionHelper, error) {
+	sp, err := ssh.ParseURL(daemonURL)
+	if err != nil {
+		return nil, fmt.Errorf("ssh host connection is not valid: %w", err)
+	}
+	remoteCommand, err := sp.RemoteCommand(dialStdioCommand(sp)...)
+	if err != nil {
+		return nil, err
+	}
+	dialer, err := nativessh.NewDialer(nativessh.Config{
+		User:    sp.User,
+		Host:    sp.Host,
+		Port:    sp.Port,
+		Options: opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectionHelper{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialCommand(ctx, remoteCommand)
+		},
+		Host: "http://docker.example.com",
+	}, nil
+}
+
+// dialStdioCommand returns the command that connects to the daemon on the
+// remote host.
+func dialStdioCommand(sp *ssh.Spec) []string {
+	if strings.Trim(sp.Path, "/") != "" {
+		return []string{"docker", "--host=unix://" + sp.Path, "system", "dial-stdio"}
+	}
+	return []string{"docker", "system", "dial-stdio"}
+}
+
 // GetCommandConnectionHelper returns Docker-specific connection helper constructed from an arbitrary command.
 func GetCommandConnectionHelper(cmd string, flags ...string) (*ConnectionHelper, error) {
 	return &ConnectionHelper{
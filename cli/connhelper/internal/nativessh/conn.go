@@ -0,0 +1,180 @@
+package nativessh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+// sessionConn implements net.Conn for the standard input and output of a
+// command that's running in an ssh session.
+type sessionConn struct {
+	session    *ssh.Session
+	command    string
+	remoteAddr string
+	stdin      io.WriteCloser
+	stdout     io.Reader
+
+	stderrMu sync.Mutex
+	stderr   bytes.Buffer
+
+	closing atomic.Bool
+	waitCh  chan struct{}
+	waitErr error
+
+	// onClose is called once, when the connection is closed.
+	onClose   func()
+	closeOnce sync.Once
+}
+
+// newSessionConn starts the command in the session. The onClose function
+// is called when the returned connection is closed; it's not called if an
+// error is returned.
+func newSessionConn(session *ssh.Session, command string, remoteAddr string, onClose func()) (net.Conn, error) {
+	c := &sessionConn{
+		session:    session,
+		command:    command,
+		remoteAddr: remoteAddr,
+		waitCh:     make(chan struct{}),
+		onClose:    onClose,
+	}
+	var err error
+	if c.stdin, err = session.StdinPipe(); err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	if c.stdout, err = session.StdoutPipe(); err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	session.Stderr = &stderrWriter{c: c}
+	if err := session.Start(command); err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("failed to run %q on %s: %w", command, remoteAddr, err)
+	}
+	go func() {
+		c.waitErr = session.Wait()
+		close(c.waitCh)
+	}()
+	return c, nil
+}
+
+type stderrWriter struct {
+	c *sessionConn
+}
+
+func (w *stderrWriter) Write(p []byte) (int, error) {
+	w.c.stderrMu.Lock()
+	defer w.c.stderrMu.Unlock()
+	return w.c.stderr.Write(p)
+}
+
+func (c *sessionConn) stderrString() string {
+	c.stderrMu.Lock()
+	defer c.stderrMu.Unlock()
+	return c.stderr.String()
+}
+
+// handleEOF returns a descriptive error if the command exited with an error.
+func (c *sessionConn) handleEOF(err error) error {
+	if err != io.EOF {
+		return err
+	}
+	select {
+	case <-c.waitCh:
+	case <-time.After(10 * time.Second):
+		return fmt.Errorf("command %q did not exit after %v: stderr=%q", c.command, err, c.stderrString())
+	}
+	if c.waitErr == nil {
+		return err
+	}
+	return fmt.Errorf("command %q has exited with %v, make sure the URL is valid, and Docker 18.09 or later is installed on the remote host: stderr=%s", c.command, c.waitErr, c.stderrString())
+}
+
+func (c *sessionConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if c.closing.Load() {
+		return n, err
+	}
+	return n, c.handleEOF(err)
+}
+
+func (c *sessionConn) Write(p []byte) (int, error) {
+	n, err := c.stdin.Write(p)
+	if c.closing.Load() {
+		return n, err
+	}
+	return n, c.handleEOF(err)
+}
+
+// CloseRead allows sessionConn to implement halfCloser. The ssh session
+// does not support closing its output, so it's a no-op.
+func (*sessionConn) CloseRead() error {
+	return nil
+}
+
+// CloseWrite allows sessionConn to implement halfCloser
+func (c *sessionConn) CloseWrite() error {
+	if err := c.stdin.Close(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Close is the net.Conn func that gets called by the transport when a
+// dial is cancelled due to its context timing out.
+func (c *sessionConn) Close() error {
+	c.closing.Store(true)
+	defer c.closing.Store(false)
+	defer c.closeOnce.Do(c.onClose)
+
+	_ = c.stdin.Close()
+	if err := c.session.Close(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func (*sessionConn) LocalAddr() net.Addr {
+	return dummyAddr{network: "dummy", s: "dummy-0"}
+}
+
+func (c *sessionConn) RemoteAddr() net.Addr {
+	return dummyAddr{network: "ssh", s: c.remoteAddr}
+}
+
+func (c *sessionConn) SetDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetDeadline(%v)", t)
+	return nil
+}
+
+func (c *sessionConn) SetReadDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetReadDeadline(%v)", t)
+	return nil
+}
+
+func (c *sessionConn) SetWriteDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetWriteDeadline(%v)", t)
+	return nil
+}
+
+type dummyAddr struct {
+	network string
+	s       string
+}
+
+func (d dummyAddr) Network() string {
+	return d.network
+}
+
+func (d dummyAddr) String() string {
+	return d.s
+}
@@ -0,0 +1,608 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+// Package nativessh provides an ssh client for connecting to a remote daemon
+// host without using the ssh binary.
+package nativessh
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // G505: hashed known_hosts entries use HMAC-SHA1
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sshspec "github.com/docker/cli/cli/connhelper/ssh"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultPort           = "22"
+	defaultConnectTimeout = 30 * time.Second
+	// defaultAliveCountMax is the default of ServerAliveCountMax, as in ssh.
+	defaultAliveCountMax = 3
+)
+
+// systemKnownHostsFile is the system-wide known_hosts file, which is used in
+// addition to the user's known_hosts file if no known_hosts file is set.
+var systemKnownHostsFile = "/etc/ssh/ssh_known_hosts"
+
+// Config is the configuration of the connection.
+type Config struct {
+	// User is the user to log in as. It defaults to the current user.
+	User string
+	// Host is the host to connect to.
+	Host string
+	// Port is the port to connect to. It defaults to port 22.
+	Port string
+	// Options are the options for the connection. Only the following ssh
+	// options ([sshspec.Options.Config]) are supported: User, Port,
+	// ConnectTimeout, ServerAliveInterval, ServerAliveCountMax, and
+	// StrictHostKeyChecking.
+	Options sshspec.Options
+
+	// homeDir is the directory that holds the ".ssh" directory, and is
+	// used for testing.
+	homeDir string
+}
+
+// hop is a host to connect to, either a jump host, or the remote host.
+type hop struct {
+	user, host, port string
+}
+
+func (h hop) addr() string {
+	return net.JoinHostPort(h.host, h.port)
+}
+
+// Dialer runs commands on the remote host, and returns connections to
+// their standard input and output. The ssh connection to the remote host
+// is reused for all commands, and is closed when the last connection that's
+// returned by [Dialer.DialCommand] is closed.
+type Dialer struct {
+	hops            []hop
+	connectTimeout  time.Duration
+	aliveInterval   time.Duration
+	aliveCountMax   int
+	identityFiles   []string
+	knownHostsFiles []string
+	identityIsSet   bool
+
+	mu      sync.Mutex
+	clients []*ssh.Client
+	// conns is the number of connections returned by DialCommand that are
+	// not closed yet.
+	conns int
+}
+
+// NewDialer returns a [Dialer] for the given configuration. It returns an
+// error if the configuration is invalid. It does not connect to the host.
+func NewDialer(cfg Config) (*Dialer, error) {
+	if _, err := cfg.Options.Flags(); err != nil {
+		return nil, err
+	}
+	if cfg.Host == "" {
+		return nil, errors.New("no host specified")
+	}
+	homeDir := cfg.homeDir
+	if homeDir == "" {
+		homeDir, _ = os.UserHomeDir()
+	}
+	target := hop{user: cfg.User, host: cfg.Host, port: cfg.Port}
+	d := &Dialer{connectTimeout: defaultConnectTimeout, aliveCountMax: defaultAliveCountMax}
+	for _, opt := range cfg.Options.Config {
+		key, value, _ := strings.Cut(opt, "=")
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(key) {
+		case "user":
+			if target.user == "" {
+				target.user = value
+			}
+		case "port":
+			if target.port == "" {
+				target.port = value
+			}
+		case "connecttimeout":
+			v, err := strconv.Atoi(value)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("invalid ssh option %q: invalid timeout", opt)
+			}
+			d.connectTimeout = time.Duration(v) * time.Second
+		case "serveraliveinterval":
+			v, err := strconv.Atoi(value)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid ssh option %q: invalid interval", opt)
+			}
+			d.aliveInterval = time.Duration(v) * time.Second
+		case "serveralivecountmax":
+			v, err := strconv.Atoi(value)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("invalid ssh option %q: invalid count", opt)
+			}
+			d.aliveCountMax = v
+		case "stricthostkeychecking":
+			if !strings.EqualFold(value, "yes") {
+				return nil, fmt.Errorf("invalid ssh option %q: only \"yes\" is supported by the native ssh transport", opt)
+			}
+		default:
+			return nil, fmt.Errorf("ssh option %q is not supported by the native ssh transport", opt)
+		}
+	}
+
+	if cfg.Options.ProxyJump != "" {
+		for _, j := range strings.Split(cfg.Options.ProxyJump, ",") {
+			h, err := parseJumpHost(j)
+			if err != nil {
+				return nil, err
+			}
+			d.hops = append(d.hops, h)
+		}
+	}
+	d.hops = append(d.hops, target)
+	for i := range d.hops {
+		if d.hops[i].user == "" {
+			u, err := user.Current()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the current user: %w", err)
+			}
+			d.hops[i].user = u.Username
+		}
+		if d.hops[i].port == "" {
+			d.hops[i].port = defaultPort
+		}
+	}
+
+	sshDir := filepath.Join(homeDir, ".ssh")
+	if cfg.Options.IdentityFile != "" {
+		d.identityFiles = []string{cfg.Options.IdentityFile}
+		d.identityIsSet = true
+	} else {
+		for _, f := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			d.identityFiles = append(d.identityFiles, filepath.Join(sshDir, f))
+		}
+	}
+	if cfg.Options.KnownHostsFile != "" {
+		d.knownHostsFiles = []string{cfg.Options.KnownHostsFile}
+	} else {
+		d.knownHostsFiles = []string{filepath.Join(sshDir, "known_hosts"), systemKnownHostsFile}
+	}
+	return d, nil
+}
+
+// parseJumpHost parses a jump host in "[user@]host[:port]" format.
+func parseJumpHost(s string) (hop, error) {
+	var h hop
+	hostPort := s
+	if u, hp, ok := strings.Cut(s, "@"); ok {
+		h.user, hostPort = u, hp
+	}
+	h.host = hostPort
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		h.host, h.port = host, port
+	}
+	if h.host == "" {
+		return hop{}, fmt.Errorf("invalid jump host %q", s)
+	}
+	return h, nil
+}
+
+// DialCommand runs the command on the remote host, and returns a connection
+// to its standard input and output.
+func (d *Dialer) DialCommand(ctx context.Context, command string) (net.Conn, error) {
+	d.mu.Lock()
+	d.conns++
+	d.mu.Unlock()
+	conn, err := d.dialCommand(ctx, command)
+	if err != nil {
+		d.release()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (d *Dialer) dialCommand(ctx context.Context, command string) (net.Conn, error) {
+	client, err := d.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		// The connection may have been lost; reconnect once.
+		d.reset(client)
+		if client, err = d.client(ctx); err != nil {
+			return nil, err
+		}
+		if session, err = client.NewSession(); err != nil {
+			return nil, fmt.Errorf("failed to open ssh session: %w", err)
+		}
+	}
+	logrus.Debugf("nativessh: running %q on %s", command, d.hops[len(d.hops)-1].addr())
+	return newSessionConn(session, command, d.hops[len(d.hops)-1].addr(), d.release)
+}
+
+// release closes the connection to the remote host when the last connection
+// that's returned by DialCommand is closed, which also stops the keep-alive
+// requests.
+func (d *Dialer) release() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.conns--
+	if d.conns == 0 {
+		_ = d.closeClients()
+	}
+}
+
+// Close closes the connection to the remote host, and the jump hosts.
+func (d *Dialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closeClients()
+}
+
+func (d *Dialer) closeClients() error {
+	var errs []error
+	for i := len(d.clients) - 1; i >= 0; i-- {
+		if err := d.clients[i].Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	d.clients = nil
+	return errors.Join(errs...)
+}
+
+// reset closes the connection, if it's the given client.
+func (d *Dialer) reset(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.clients) > 0 && d.clients[len(d.clients)-1] == client {
+		_ = d.closeClients()
+	}
+}
+
+// client returns the client connected to the remote host, connecting
+// through the jump hosts if needed.
+func (d *Dialer) client(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.clients) > 0 {
+		return d.clients[len(d.clients)-1], nil
+	}
+
+	known, err := d.knownHosts()
+	if err != nil {
+		return nil, err
+	}
+	auth, closeAgent, err := d.authMethod()
+	if err != nil {
+		return nil, err
+	}
+	// The agent is only used for authentication.
+	defer closeAgent()
+
+	var client *ssh.Client
+	for _, h := range d.hops {
+		client, err = d.connect(ctx, client, h, auth, known)
+		if err != nil {
+			_ = d.closeClients()
+			return nil, err
+		}
+		d.clients = append(d.clients, client)
+	}
+	go func(c *ssh.Client) {
+		// forget the connection when it's lost, so that the next command
+		// reconnects.
+		_ = c.Wait()
+		d.reset(c)
+	}(client)
+	return client, nil
+}
+
+// connect connects to the host, either directly, or through the jump host's
+// client.
+func (d *Dialer) connect(ctx context.Context, jump *ssh.Client, h hop, auth ssh.AuthMethod, known *knownHosts) (*ssh.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.connectTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if jump == nil {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", h.addr())
+	} else {
+		conn, err = jump.DialContext(ctx, "tcp", h.addr())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", h.addr(), err)
+	}
+
+	config := &ssh.ClientConfig{
+		User:              h.user,
+		Auth:              []ssh.AuthMethod{auth},
+		HostKeyCallback:   known.verify,
+		HostKeyAlgorithms: known.algorithms(h),
+		Timeout:           d.connectTimeout,
+	}
+
+	// Deadlines are not supported for connections through a jump host, so
+	// the handshake is aborted by closing the connection instead.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	c, chans, reqs, err := ssh.NewClientConn(conn, h.addr(), config)
+	if !stop() {
+		if err == nil {
+			_ = c.Close()
+		}
+		return nil, fmt.Errorf("failed to connect to %s: %w", h.addr(), ctx.Err())
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", h.addr(), err)
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	if d.aliveInterval > 0 {
+		go keepAlive(client, d.aliveInterval, d.aliveCountMax)
+	}
+	return client, nil
+}
+
+// keepAliveClient is the part of [ssh.Client] that's used by [keepAlive].
+type keepAliveClient interface {
+	SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error)
+	Wait() error
+	Close() error
+}
+
+// keepAlive sends keep-alive requests to the server, and closes the
+// connection if the server does not respond to countMax requests in a row.
+// A request that's not answered within the interval counts as unanswered.
+func keepAlive(client keepAliveClient, interval time.Duration, countMax int) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	done := make(chan struct{})
+	go func() {
+		_ = client.Wait()
+		close(done)
+	}()
+	var missed int
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+		}
+		// SendRequest does not return until the server replies, or the
+		// connection is closed.
+		reply := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			reply <- err
+		}()
+		timeout := time.NewTimer(interval)
+		select {
+		case <-done:
+			timeout.Stop()
+			return
+		case err := <-reply:
+			timeout.Stop()
+			if err != nil {
+				logrus.Debugf("nativessh: keep-alive failed: %v", err)
+				_ = client.Close()
+				return
+			}
+			missed = 0
+		case <-timeout.C:
+			missed++
+			if missed >= countMax {
+				logrus.Debugf("nativessh: no reply to %d keep-alive requests", missed)
+				_ = client.Close()
+				return
+			}
+		}
+	}
+}
+
+// authMethod returns the public key authentication method, using the keys
+// of the ssh agent ("SSH_AUTH_SOCK"), and the identity files. The returned
+// function closes the connection to the agent.
+func (d *Dialer) authMethod() (_ ssh.AuthMethod, closeAgent func(), retErr error) {
+	closeAgent = func() {}
+	var signers []ssh.Signer
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			logrus.Debugf("nativessh: failed to connect to ssh agent: %v", err)
+		} else {
+			closeAgent = func() { _ = conn.Close() }
+			defer func() {
+				if retErr != nil {
+					closeAgent()
+				}
+			}()
+			agentSigners, err := agent.NewClient(conn).Signers()
+			if err != nil {
+				logrus.Debugf("nativessh: failed to get keys from ssh agent: %v", err)
+			}
+			signers = append(signers, agentSigners...)
+		}
+	}
+	for _, f := range d.identityFiles {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			if d.identityIsSet {
+				return nil, nil, fmt.Errorf("failed to read identity file: %w", err)
+			}
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			var passphraseErr *ssh.PassphraseMissingError
+			if errors.As(err, &passphraseErr) {
+				// Keys protected by a passphrase must be added to the agent.
+				logrus.Debugf("nativessh: skipping identity file %s, which is protected by a passphrase", f)
+				if d.identityIsSet && len(signers) == 0 {
+					return nil, nil, fmt.Errorf("identity file %s is protected by a passphrase: add it to the ssh agent (ssh-add)", f)
+				}
+				continue
+			}
+			return nil, nil, fmt.Errorf("invalid identity file %s: %w", f, err)
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) == 0 {
+		return nil, nil, errors.New("no ssh keys found: start an ssh agent, or set the identity file of the context")
+	}
+	return ssh.PublicKeys(signers...), closeAgent, nil
+}
+
+// knownHosts verifies host keys using the known_hosts files.
+type knownHosts struct {
+	files []string
+	// callback is the callback of the knownhosts package, which returns a
+	// [*knownhosts.KeyError] for unknown hosts and keys.
+	callback ssh.HostKeyCallback
+}
+
+// knownHosts loads the known_hosts files.
+func (d *Dialer) knownHosts() (*knownHosts, error) {
+	var files []string
+	for _, f := range d.knownHostsFiles {
+		if _, err := os.Stat(f); err == nil {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("host key verification failed: no known_hosts file found (%s)", strings.Join(d.knownHostsFiles, ", "))
+	}
+	cb, err := knownhosts.New(files...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid known_hosts file: %w", err)
+	}
+	return &knownHosts{files: files, callback: cb}, nil
+}
+
+// verify is the [ssh.HostKeyCallback] that verifies the host key.
+func (k *knownHosts) verify(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	err := k.callback(hostname, remote, key)
+	var keyErr *knownhosts.KeyError
+	if errors.As(err, &keyErr) {
+		if len(keyErr.Want) == 0 {
+			return fmt.Errorf("host key verification failed: no host key for %s in %s", hostname, strings.Join(k.files, ", "))
+		}
+		return fmt.Errorf("host key verification failed: the host key of %s does not match the key in %s:%d", hostname, keyErr.Want[0].Filename, keyErr.Want[0].Line)
+	}
+	return err
+}
+
+// algorithms returns the algorithms of the host's keys in the known_hosts
+// files, so that the server does not present a key of another type than the
+// one that's known.
+func (k *knownHosts) algorithms(h hop) []string {
+	host := knownhosts.Normalize(h.addr())
+	var algos []string
+	for _, f := range k.files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		for len(data) > 0 {
+			marker, hosts, key, _, rest, err := ssh.ParseKnownHosts(data)
+			if err != nil {
+				// The files were already parsed by knownhosts.New, so
+				// this is the end of the file.
+				break
+			}
+			data = rest
+			if marker != "" || !matchHost(hosts, host) {
+				continue
+			}
+			for _, algo := range keyAlgorithms(key.Type()) {
+				if !slices.Contains(algos, algo) {
+					algos = append(algos, algo)
+				}
+			}
+		}
+	}
+	return algos
+}
+
+// keyAlgorithms returns the host key algorithms for a key type.
+func keyAlgorithms(keyType string) []string {
+	if keyType == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{keyType}
+}
+
+// matchHost returns whether host matches the patterns of a known_hosts entry.
+// Patterns can be hashed ("|1|salt|hash"), contain wildcards ("*" and "?"),
+// or be negated ("!pattern").
+func matchHost(patterns []string, host string) bool {
+	var matched bool
+	for _, p := range patterns {
+		p, negated := strings.CutPrefix(p, "!")
+		if !matchPattern(p, host) {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+func matchPattern(pattern, host string) bool {
+	hashed, ok := strings.CutPrefix(pattern, "|1|")
+	if !ok {
+		return matchWildcard(pattern, host)
+	}
+	salt64, hash64, _ := strings.Cut(hashed, "|")
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil {
+		return false
+	}
+	hash, err := base64.StdEncoding.DecodeString(hash64)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, salt)
+	_, _ = mac.Write([]byte(host))
+	return hmac.Equal(mac.Sum(nil), hash)
+}
+
+// matchWildcard matches s against a pattern, in which "*" matches any
+// sequence of characters, and "?" matches a single character.
+func matchWildcard(pattern, s string) bool {
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchWildcard(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		default:
+			if s == "" || pattern[0] != s[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return s == ""
+}
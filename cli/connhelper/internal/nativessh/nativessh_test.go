@@ -0,0 +1,575 @@
+package nativessh
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sshspec "github.com/docker/cli/cli/connhelper/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const dialStdio = "docker system dial-stdio"
+
+// testServer is an in-process ssh server that accepts public key
+// authentication for a single key. It runs the "docker system dial-stdio"
+// command by echoing its input, and supports "direct-tcpip" channels to be
+// used as a jump host.
+type testServer struct {
+	addr    string
+	hostKey ssh.Signer
+
+	mu       sync.Mutex
+	commands []string
+	conns    atomic.Int32
+}
+
+// newTestServer starts a server. It uses a new ed25519 host key if no host
+// keys are given.
+func newTestServer(t *testing.T, authorized ssh.PublicKey, hostKeys ...ssh.Signer) *testServer {
+	t.Helper()
+	if len(hostKeys) == 0 {
+		hostKeys = []ssh.Signer{newSigner(t)}
+	}
+	srv := &testServer{hostKey: hostKeys[0]}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(authorized.Marshal()) {
+				return nil, errors.New("unauthorized")
+			}
+			return &ssh.Permissions{}, nil
+		},
+	}
+	for _, k := range hostKeys {
+		config.AddHostKey(k)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	srv.addr = l.Addr().String()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn, config)
+		}
+	}()
+	return srv
+}
+
+func (srv *testServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	defer sconn.Close()
+	srv.conns.Add(1)
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		switch newCh.ChannelType() {
+		case "session":
+			go srv.session(newCh)
+		case "direct-tcpip":
+			go directTCPIP(newCh)
+		default:
+			_ = newCh.Reject(ssh.UnknownChannelType, "unknown channel type")
+		}
+	}
+}
+
+func (srv *testServer) session(newCh ssh.NewChannel) {
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		return
+	}
+	defer ch.Close()
+	for req := range reqs {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			return
+		}
+		_ = req.Reply(true, nil)
+		srv.mu.Lock()
+		srv.commands = append(srv.commands, payload.Command)
+		srv.mu.Unlock()
+
+		var status uint32
+		if payload.Command == dialStdio {
+			_, _ = io.Copy(ch, ch)
+		} else {
+			_, _ = io.WriteString(ch.Stderr(), "docker: unknown command\n")
+			status = 1
+		}
+		_ = ch.CloseWrite()
+		_, _ = ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+		return
+	}
+}
+
+func directTCPIP(newCh ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newCh.ExtraData(), &payload); err != nil {
+		_ = newCh.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		_ = newCh.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	go func() {
+		_, _ = io.Copy(conn, ch)
+		_ = conn.Close()
+	}()
+	_, _ = io.Copy(ch, conn)
+	_ = ch.Close()
+}
+
+func (srv *testServer) knownHostsLine() string {
+	return knownhosts.Line([]string{knownhosts.Normalize(srv.addr)}, srv.hostKey.PublicKey()) + "\n"
+}
+
+func (srv *testServer) config(t *testing.T, opts sshspec.Options) Config {
+	t.Helper()
+	host, port, err := net.SplitHostPort(srv.addr)
+	assert.NilError(t, err)
+	return Config{User: "me", Host: host, Port: port, Options: opts, homeDir: t.TempDir()}
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	assert.NilError(t, err)
+	return signer
+}
+
+// writeIdentity writes a new private key to the directory, and returns its
+// path and public key.
+func writeIdentity(t *testing.T, dir string) (string, ssh.PublicKey) {
+	t.Helper()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	block, err := ssh.MarshalPrivateKey(key, "")
+	assert.NilError(t, err)
+	filename := filepath.Join(dir, "id_ed25519")
+	assert.NilError(t, os.WriteFile(filename, pem.EncodeToMemory(block), 0o600))
+	sshPub, err := ssh.NewPublicKey(pub)
+	assert.NilError(t, err)
+	return filename, sshPub
+}
+
+func writeKnownHosts(t *testing.T, lines ...string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "known_hosts")
+	var content string
+	for _, l := range lines {
+		content += l
+	}
+	assert.NilError(t, os.WriteFile(filename, []byte(content), 0o600))
+	return filename
+}
+
+// roundTrip runs the dial-stdio command, and checks that the connection
+// echoes its input.
+func roundTrip(t *testing.T, d *Dialer) error {
+	t.Helper()
+	conn, err := d.DialCommand(context.Background(), dialStdio)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "hello"); err != nil {
+		return err
+	}
+	assert.NilError(t, conn.(interface{ CloseWrite() error }).CloseWrite())
+	out, err := io.ReadAll(conn)
+	if err != nil {
+		return err
+	}
+	assert.Check(t, is.Equal(string(out), "hello"))
+	return nil
+}
+
+func TestDialCommand(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	identity, pub := writeIdentity(t, t.TempDir())
+	srv := newTestServer(t, pub)
+
+	d, err := NewDialer(srv.config(t, sshspec.Options{
+		IdentityFile:   identity,
+		KnownHostsFile: writeKnownHosts(t, srv.knownHostsLine()),
+	}))
+	assert.NilError(t, err)
+	defer d.Close()
+
+	conn, err := d.DialCommand(context.Background(), dialStdio)
+	assert.NilError(t, err)
+	assert.NilError(t, roundTrip(t, d))
+	assert.NilError(t, roundTrip(t, d))
+
+	// the ssh connection is reused while a connection is open.
+	assert.Check(t, is.Equal(srv.conns.Load(), int32(1)))
+	srv.mu.Lock()
+	assert.Check(t, is.DeepEqual(srv.commands, []string{dialStdio, dialStdio, dialStdio}))
+	srv.mu.Unlock()
+
+	// the ssh connection is closed with the last connection.
+	assert.NilError(t, conn.Close())
+	d.mu.Lock()
+	assert.Check(t, is.Len(d.clients, 0))
+	assert.Check(t, is.Equal(d.conns, 0))
+	d.mu.Unlock()
+	assert.NilError(t, roundTrip(t, d))
+	assert.Check(t, is.Equal(srv.conns.Load(), int32(2)))
+}
+
+func TestDialCommandDefaultFiles(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	home := t.TempDir()
+	sshDir := filepath.Join(home, ".ssh")
+	assert.NilError(t, os.Mkdir(sshDir, 0o700))
+	_, pub := writeIdentity(t, sshDir)
+	srv := newTestServer(t, pub)
+	assert.NilError(t, os.WriteFile(filepath.Join(sshDir, "known_hosts"), []byte(srv.knownHostsLine()), 0o600))
+
+	cfg := srv.config(t, sshspec.Options{})
+	cfg.homeDir = home
+	d, err := NewDialer(cfg)
+	assert.NilError(t, err)
+	defer d.Close()
+	assert.NilError(t, roundTrip(t, d))
+}
+
+func TestDialCommandAgent(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	keyring := agent.NewKeyring()
+	assert.NilError(t, keyring.Add(agent.AddedKey{PrivateKey: key}))
+	signers, err := keyring.Signers()
+	assert.NilError(t, err)
+
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	assert.NilError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = agent.ServeAgent(keyring, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", sock)
+
+	srv := newTestServer(t, signers[0].PublicKey())
+	d, err := NewDialer(srv.config(t, sshspec.Options{
+		KnownHostsFile: writeKnownHosts(t, srv.knownHostsLine()),
+	}))
+	assert.NilError(t, err)
+	defer d.Close()
+	assert.NilError(t, roundTrip(t, d))
+}
+
+func TestDialCommandProxyJump(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	identity, pub := writeIdentity(t, t.TempDir())
+	jump1 := newTestServer(t, pub)
+	jump2 := newTestServer(t, pub)
+	srv := newTestServer(t, pub)
+
+	d, err := NewDialer(srv.config(t, sshspec.Options{
+		IdentityFile:   identity,
+		KnownHostsFile: writeKnownHosts(t, jump1.knownHostsLine(), jump2.knownHostsLine(), srv.knownHostsLine()),
+		ProxyJump:      "jumper@" + jump1.addr + "," + jump2.addr,
+	}))
+	assert.NilError(t, err)
+	defer d.Close()
+	assert.NilError(t, roundTrip(t, d))
+
+	assert.Check(t, is.Equal(jump1.conns.Load(), int32(1)))
+	assert.Check(t, is.Equal(jump2.conns.Load(), int32(1)))
+	assert.Check(t, is.Equal(srv.conns.Load(), int32(1)))
+}
+
+func TestDialCommandHostKeyVerification(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	identity, pub := writeIdentity(t, t.TempDir())
+	srv := newTestServer(t, pub)
+	other := newTestServer(t, pub)
+
+	// a known_hosts entry for the server's address, with another key.
+	mismatch := knownhosts.Line([]string{knownhosts.Normalize(srv.addr)}, other.hostKey.PublicKey()) + "\n"
+
+	tests := []struct {
+		doc         string
+		knownHosts  string
+		expectedErr string
+	}{
+		{
+			doc:         "unknown host",
+			knownHosts:  other.knownHostsLine(),
+			expectedErr: "host key verification failed: no host key for " + srv.addr,
+		},
+		{
+			doc:         "key mismatch",
+			knownHosts:  mismatch,
+			expectedErr: "host key verification failed: the host key of " + srv.addr + " does not match",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			d, err := NewDialer(srv.config(t, sshspec.Options{
+				IdentityFile:   identity,
+				KnownHostsFile: writeKnownHosts(t, tc.knownHosts),
+			}))
+			assert.NilError(t, err)
+			defer d.Close()
+			err = roundTrip(t, d)
+			if tc.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			}
+		})
+	}
+
+	t.Run("known key of another type", func(t *testing.T) {
+		// The server also offers ECDSA and ed25519 keys; the ECDSA key is
+		// preferred by the client, but only the RSA key is known.
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NilError(t, err)
+		rsaSigner, err := ssh.NewSignerFromKey(rsaKey)
+		assert.NilError(t, err)
+		ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NilError(t, err)
+		ecdsaSigner, err := ssh.NewSignerFromKey(ecdsaKey)
+		assert.NilError(t, err)
+		mixed := newTestServer(t, pub, rsaSigner, ecdsaSigner, newSigner(t))
+
+		d, err := NewDialer(mixed.config(t, sshspec.Options{
+			IdentityFile:   identity,
+			KnownHostsFile: writeKnownHosts(t, mixed.knownHostsLine()),
+		}))
+		assert.NilError(t, err)
+		defer d.Close()
+		assert.NilError(t, roundTrip(t, d))
+	})
+
+	t.Run("no known_hosts file", func(t *testing.T) {
+		systemKnownHostsFile = filepath.Join(t.TempDir(), "ssh_known_hosts")
+		defer func() { systemKnownHostsFile = "/etc/ssh/ssh_known_hosts" }()
+		d, err := NewDialer(srv.config(t, sshspec.Options{IdentityFile: identity}))
+		assert.NilError(t, err)
+		defer d.Close()
+		assert.Check(t, is.ErrorContains(roundTrip(t, d), "host key verification failed: no known_hosts file found"))
+	})
+}
+
+func TestDialCommandUnauthorized(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	identity, _ := writeIdentity(t, t.TempDir())
+	srv := newTestServer(t, newSigner(t).PublicKey())
+
+	d, err := NewDialer(srv.config(t, sshspec.Options{
+		IdentityFile:   identity,
+		KnownHostsFile: writeKnownHosts(t, srv.knownHostsLine()),
+	}))
+	assert.NilError(t, err)
+	defer d.Close()
+	assert.Check(t, is.ErrorContains(roundTrip(t, d), "unable to authenticate"))
+}
+
+func TestDialCommandExitStatus(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	identity, pub := writeIdentity(t, t.TempDir())
+	srv := newTestServer(t, pub)
+
+	d, err := NewDialer(srv.config(t, sshspec.Options{
+		IdentityFile:   identity,
+		KnownHostsFile: writeKnownHosts(t, srv.knownHostsLine()),
+	}))
+	assert.NilError(t, err)
+	defer d.Close()
+
+	conn, err := d.DialCommand(context.Background(), "docker --host=unix:///foo system dial-stdio")
+	assert.NilError(t, err)
+	defer conn.Close()
+	_, err = io.ReadAll(conn)
+	assert.Check(t, is.ErrorContains(err, "Process exited with status 1"))
+	assert.Check(t, is.ErrorContains(err, "stderr=docker: unknown command"))
+}
+
+func TestNewDialer(t *testing.T) {
+	tests := []struct {
+		doc         string
+		opts        sshspec.Options
+		expectedErr string
+	}{
+		{
+			doc:  "supported options",
+			opts: sshspec.Options{Config: []string{"User=me", "Port=2222", "ConnectTimeout=5", "ServerAliveInterval=10", "ServerAliveCountMax=2", "StrictHostKeyChecking=yes"}},
+		},
+		{
+			doc:         "unsupported option",
+			opts:        sshspec.Options{Config: []string{"Compression=yes"}},
+			expectedErr: `ssh option "Compression=yes" is not supported by the native ssh transport`,
+		},
+		{
+			doc:         "disallowed option",
+			opts:        sshspec.Options{Config: []string{"ProxyCommand=nc %h %p"}},
+			expectedErr: "ProxyCommand is not allowed",
+		},
+		{
+			doc:         "invalid timeout",
+			opts:        sshspec.Options{Config: []string{"ConnectTimeout=soon"}},
+			expectedErr: "invalid timeout",
+		},
+		{
+			doc:         "unsupported StrictHostKeyChecking",
+			opts:        sshspec.Options{Config: []string{"StrictHostKeyChecking=accept-new"}},
+			expectedErr: `only "yes" is supported by the native ssh transport`,
+		},
+		{
+			doc:         "invalid jump host",
+			opts:        sshspec.Options{ProxyJump: "me@:22"},
+			expectedErr: `invalid jump host "me@:22"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			_, err := NewDialer(Config{Host: "example.com", Options: tc.opts, homeDir: t.TempDir()})
+			if tc.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			}
+		})
+	}
+}
+
+type fakeKeepAliveClient struct {
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *fakeKeepAliveClient) SendRequest(string, bool, []byte) (bool, []byte, error) {
+	// a dead connection, which never replies.
+	<-c.closed
+	return false, nil, io.EOF
+}
+
+func (c *fakeKeepAliveClient) Wait() error {
+	<-c.closed
+	return nil
+}
+
+func (c *fakeKeepAliveClient) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func TestKeepAliveNoReply(t *testing.T) {
+	c := &fakeKeepAliveClient{closed: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		keepAlive(c, 10*time.Millisecond, 2)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("keep-alive did not close the connection")
+	}
+	select {
+	case <-c.closed:
+	default:
+		t.Fatal("expected the connection to be closed")
+	}
+}
+
+func TestParseJumpHost(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected hop
+	}{
+		{in: "example.com", expected: hop{host: "example.com"}},
+		{in: "me@example.com", expected: hop{user: "me", host: "example.com"}},
+		{in: "me@example.com:2222", expected: hop{user: "me", host: "example.com", port: "2222"}},
+		{in: "[::1]:2222", expected: hop{host: "::1", port: "2222"}},
+	}
+	for _, tc := range tests {
+		h, err := parseJumpHost(tc.in)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(h, tc.expected))
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	hashed := knownhosts.HashHostname("example.com")
+	for _, tc := range []struct {
+		patterns []string
+		host     string
+		expected bool
+	}{
+		{patterns: []string{"example.com"}, host: "example.com", expected: true},
+		{patterns: []string{"other.com", "example.com"}, host: "example.com", expected: true},
+		{patterns: []string{"example.com"}, host: "[example.com]:2222", expected: false},
+		{patterns: []string{"[example.com]:2222"}, host: "[example.com]:2222", expected: true},
+		{patterns: []string{"*.example.com"}, host: "www.example.com", expected: true},
+		{patterns: []string{"*.example.com"}, host: "example.com", expected: false},
+		{patterns: []string{"192.168.0.?"}, host: "192.168.0.1", expected: true},
+		{patterns: []string{"*.example.com", "!www.example.com"}, host: "www.example.com", expected: false},
+		{patterns: []string{hashed}, host: "example.com", expected: true},
+		{patterns: []string{hashed}, host: "other.com", expected: false},
+		{patterns: []string{"|1|invalid"}, host: "example.com", expected: false},
+	} {
+		assert.Check(t, is.Equal(matchHost(tc.patterns, tc.host), tc.expected), "%v %s", tc.patterns, tc.host)
+	}
+}
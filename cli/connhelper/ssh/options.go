@@ -0,0 +1,116 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// allowedConfigOptions are the ssh options that can be set through
+// [Options.Config], and the values they accept (any value if nil). Options
+// are stored in docker contexts, which can be imported from untrusted
+// sources, so options that execute commands, load libraries, forward
+// connections, or weaken host key verification are not allowed.
+var allowedConfigOptions = map[string][]string{
+	"compression":           nil,
+	"connecttimeout":        nil,
+	"port":                  nil,
+	"serveralivecountmax":   nil,
+	"serveraliveinterval":   nil,
+	"stricthostkeychecking": {"yes", "accept-new"},
+	"user":                  nil,
+}
+
+// Options are options for the ssh connection that cannot be set through
+// the ssh URL.
+type Options struct {
+	// IdentityFile is the path of the private key to authenticate with
+	// ("ssh -i").
+	IdentityFile string
+	// KnownHostsFile is the path of the known_hosts file to verify the
+	// host key with ("ssh -o UserKnownHostsFile").
+	KnownHostsFile string
+	// ProxyJump is a comma-separated list of jump hosts to connect through
+	// ("ssh -J").
+	ProxyJump string
+	// Config are ssh options in "Key=Value" format, as used in ssh_config(5)
+	// ("ssh -o"). Only the Compression, ConnectTimeout, Port,
+	// ServerAliveCountMax, ServerAliveInterval, StrictHostKeyChecking ("yes"
+	// or "accept-new"), and User options are allowed.
+	Config []string
+}
+
+// Flags validates the options, and returns the corresponding ssh flags.
+func (o Options) Flags() ([]string, error) {
+	var flags []string
+	if o.IdentityFile != "" {
+		if err := validateValue(o.IdentityFile); err != nil {
+			return nil, fmt.Errorf("invalid identity file: %w", err)
+		}
+		flags = append(flags, "-i", o.IdentityFile)
+	}
+	if o.KnownHostsFile != "" {
+		if err := validateValue(o.KnownHostsFile); err != nil {
+			return nil, fmt.Errorf("invalid known hosts file: %w", err)
+		}
+		// ssh splits the value of UserKnownHostsFile on whitespace, unless
+		// it's quoted.
+		flags = append(flags, "-o", `UserKnownHostsFile="`+o.KnownHostsFile+`"`)
+	}
+	if o.ProxyJump != "" {
+		if err := validateValue(o.ProxyJump); err != nil {
+			return nil, fmt.Errorf("invalid jump host: %w", err)
+		}
+		if strings.ContainsAny(o.ProxyJump, " \t") {
+			return nil, fmt.Errorf("invalid jump host: %q contains whitespace", o.ProxyJump)
+		}
+		flags = append(flags, "-J", o.ProxyJump)
+	}
+	for _, opt := range o.Config {
+		if err := validateConfigOption(opt); err != nil {
+			return nil, err
+		}
+		flags = append(flags, "-o", opt)
+	}
+	return flags, nil
+}
+
+func validateConfigOption(opt string) error {
+	key, value, ok := strings.Cut(opt, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid ssh option %q: must be in Key=Value format", opt)
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return fmt.Errorf("invalid ssh option %q: invalid option name", opt)
+		}
+	}
+	allowedValues, ok := allowedConfigOptions[strings.ToLower(key)]
+	if !ok {
+		return fmt.Errorf("invalid ssh option %q: %s is not allowed", opt, key)
+	}
+	if allowedValues != nil && !containsValue(allowedValues, value) {
+		return fmt.Errorf("invalid ssh option %q: value must be one of %s", opt, strings.Join(allowedValues, ", "))
+	}
+	return validateValue(opt)
+}
+
+func containsValue(values []string, v string) bool {
+	v = strings.TrimSpace(v)
+	for _, allowed := range values {
+		if strings.EqualFold(v, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateValue(v string) error {
+	if strings.HasPrefix(v, "-") {
+		return fmt.Errorf("%q must not start with '-'", v)
+	}
+	if strings.ContainsAny(v, "\r\n\x00") {
+		return errors.New("value must not contain newlines or NUL characters")
+	}
+	return nil
+}
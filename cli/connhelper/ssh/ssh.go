@@ -76,6 +76,10 @@ type Spec struct {
 	Host string
 	Port string
 	Path string
+
+	// Options are additional options for the connection, which are
+	// included in the arguments returned by [Spec.Args] and [Spec.Command].
+	Options *Options
 }
 
 // Args returns args except "ssh" itself combined with optional additional
@@ -122,6 +126,14 @@ func (sp *Spec) args(sshFlags ...string) ([]string, error) {
 		args = append(args, "-p", port)
 	}
 
+	if sp.Options != nil {
+		opts, err := sp.Options.Flags()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, opts...)
+	}
+
 	// We consider "sshFlags" to be "trusted", and set from code only,
 	// as they are not parsed from the DOCKER_HOST URL.
 	args = append(args, sshFlags...)
@@ -161,6 +173,17 @@ func (sp *Spec) Command(sshFlags []string, remoteCommandAndArgs ...string) ([]st
 	return sshArgs, nil
 }
 
+// RemoteCommand returns the command to execute on the remote host as a
+// single string, quoting values where needed because ssh executes the
+// remote command in a shell. It is used by ssh clients that do not use
+// the ssh binary.
+func (sp *Spec) RemoteCommand(remoteCommandAndArgs ...string) (string, error) {
+	if len(remoteCommandAndArgs) == 0 {
+		return "", errors.New("no remote command specified")
+	}
+	return quoteCommand(remoteCommandAndArgs...)
+}
+
 // quoteCommand returns the remote command to run using the ssh connection
 // as a single string, quoting values where needed because ssh executes
 // these in a POSIX shell.
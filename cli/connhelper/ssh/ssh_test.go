@@ -315,3 +315,94 @@ func TestCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestOptions(t *testing.T) {
+	testCases := []struct {
+		doc           string
+		options       Options
+		expectedArgs  []string
+		expectedError string
+	}{
+		{
+			doc: "all options",
+			options: Options{
+				IdentityFile:   "/home/me/.docker/contexts/tls/abc/docker/ssh_identity",
+				KnownHostsFile: "/home/my user/known_hosts",
+				ProxyJump:      "bastion.example.com,me@jump:2222",
+				Config:         []string{"ServerAliveInterval=30", "Compression=yes", "StrictHostKeyChecking=accept-new"},
+			},
+			expectedArgs: []string{
+				"-l", "me",
+				"-i", "/home/me/.docker/contexts/tls/abc/docker/ssh_identity",
+				"-o", `UserKnownHostsFile="/home/my user/known_hosts"`,
+				"-J", "bastion.example.com,me@jump:2222",
+				"-o", "ServerAliveInterval=30",
+				"-o", "Compression=yes",
+				"-o", "StrictHostKeyChecking=accept-new",
+				"--", "example.com",
+				"docker system dial-stdio",
+			},
+		},
+		{
+			doc:           "command option",
+			options:       Options{Config: []string{"proxycommand=nc %h %p"}},
+			expectedError: `invalid ssh option "proxycommand=nc %h %p": proxycommand is not allowed`,
+		},
+		{
+			doc:           "library option",
+			options:       Options{Config: []string{"PKCS11Provider=/tmp/evil.so"}},
+			expectedError: `invalid ssh option "PKCS11Provider=/tmp/evil.so": PKCS11Provider is not allowed`,
+		},
+		{
+			doc:           "security key provider",
+			options:       Options{Config: []string{"SecurityKeyProvider=/tmp/evil.so"}},
+			expectedError: `invalid ssh option "SecurityKeyProvider=/tmp/evil.so": SecurityKeyProvider is not allowed`,
+		},
+		{
+			doc:           "forwarding option",
+			options:       Options{Config: []string{"LocalForward=2375 localhost:2375"}},
+			expectedError: `invalid ssh option "LocalForward=2375 localhost:2375": LocalForward is not allowed`,
+		},
+		{
+			doc:           "known hosts file option",
+			options:       Options{Config: []string{"UserKnownHostsFile=/dev/null"}},
+			expectedError: `invalid ssh option "UserKnownHostsFile=/dev/null": UserKnownHostsFile is not allowed`,
+		},
+		{
+			doc:           "host key checking disabled",
+			options:       Options{Config: []string{"StrictHostKeyChecking=no"}},
+			expectedError: `invalid ssh option "StrictHostKeyChecking=no": value must be one of yes, accept-new`,
+		},
+		{
+			doc:           "invalid option name",
+			options:       Options{Config: []string{"-oProxyCommand=nc=1"}},
+			expectedError: `invalid ssh option "-oProxyCommand=nc=1": invalid option name`,
+		},
+		{
+			doc:           "newline in option",
+			options:       Options{Config: []string{"User=me\nProxyCommand=nc"}},
+			expectedError: "value must not contain newlines or NUL characters",
+		},
+		{
+			doc:           "flag as jump host",
+			options:       Options{ProxyJump: "-oProxyCommand=nc"},
+			expectedError: `invalid jump host: "-oProxyCommand=nc" must not start with '-'`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			sp, err := ParseURL("ssh://example.com")
+			assert.NilError(t, err)
+			sp.User = "me"
+			sp.Options = &tc.options
+			actualArgs, err := sp.Command(nil, "docker", "system", "dial-stdio")
+			if tc.expectedError != "" {
+				assert.Check(t, is.Error(err, tc.expectedError))
+				assert.Check(t, is.Nil(sp.Args("docker")))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(actualArgs, tc.expectedArgs))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli/context/store"
+	"github.com/moby/sys/atomicwriter"
+)
+
+// failoverStateFile is the name of the file that holds the [FailoverState]
+// of a context. It's stored next to the context's metadata, so that it's
+// removed with the context, but it's not exported.
+const failoverStateFile = "failover.json"
+
+// FailoverState is the state of a context that has failover hosts.
+type FailoverState struct {
+	// Host is the last host of the endpoint that was healthy.
+	Host string
+	// CheckedAt is the time at which the host was selected.
+	CheckedAt time.Time
+}
+
+// WithHost returns a copy of the endpoint that connects to the given host.
+func (ep *Endpoint) WithHost(host string) Endpoint {
+	c := *ep
+	c.Host = host
+	return c
+}
+
+// LoadFailoverState loads the failover state of the context. It returns an
+// empty state if no state was saved.
+func LoadFailoverState(s store.Reader, contextName string) (FailoverState, error) {
+	var state FailoverState
+	p, ok := failoverStatePath(s, contextName)
+	if !ok {
+		return state, nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return state, err
+	}
+	return state, json.Unmarshal(data, &state)
+}
+
+// SaveFailoverState saves the failover state of the context.
+func SaveFailoverState(s store.Reader, contextName string, state FailoverState) error {
+	p, ok := failoverStatePath(s, contextName)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return atomicwriter.WriteFile(p, data, 0o644)
+}
+
+func failoverStatePath(s store.Reader, contextName string) (string, bool) {
+	p, ok := s.(store.StorageInfoProvider)
+	if !ok {
+		return "", false
+	}
+	metadataPath := p.GetStorageInfo(contextName).MetadataPath
+	if !filepath.IsAbs(metadataPath) {
+		// contexts that are not stored on disk, such as the default context.
+		return "", false
+	}
+	return filepath.Join(metadataPath, failoverStateFile), true
+}
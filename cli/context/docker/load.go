@@ -8,10 +8,12 @@
 	"fmt"
 	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/cli/cli/connhelper/ssh"
 	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/go-connections/tlsconfig"
@@ -27,6 +29,11 @@
 type Endpoint struct {
 	EndpointMeta
 	TLSData *context.TLSData
+
+	// tlsDir is the directory in the context store that holds the
+	// endpoint's TLS material. The ssh identity and known_hosts files
+	// are passed to ssh from this directory.
+	tlsDir string
 }
 
 // WithTLSData loads TLS materials for the endpoint
@@ -35,12 +42,53 @@ func WithTLSData(s store.Reader, contextName string, m EndpointMeta) (Endpoint,
 	if err != nil {
 		return Endpoint{}, err
 	}
+	var tlsDir string
+	if p, ok := s.(store.StorageInfoProvider); ok {
+		tlsDir = filepath.Join(p.GetStorageInfo(contextName).TLSPath, DockerEndpoint)
+	}
 	return Endpoint{
 		EndpointMeta: m,
 		TLSData:      tlsData,
+		tlsDir:       tlsDir,
 	}, nil
 }
 
+// sshOptions returns the options for endpoints that connect over SSH.
+func (ep *Endpoint) sshOptions() ssh.Options {
+	var opts ssh.Options
+	if ep.SSH != nil {
+		opts.ProxyJump = ep.SSH.ProxyJump
+		opts.Config = ep.SSH.Options
+	}
+	if ep.TLSData != nil && ep.tlsDir != "" {
+		if ep.TLSData.SSHIdentity != nil {
+			opts.IdentityFile = filepath.Join(ep.tlsDir, context.SSHIdentityFile)
+		}
+		if ep.TLSData.SSHKnownHosts != nil {
+			opts.KnownHostsFile = filepath.Join(ep.tlsDir, context.SSHKnownHostsFile)
+		}
+	}
+	return opts
+}
+
+// connectionHelper returns the connection helper for the endpoint's host, or
+// nil if the host does not need a connection helper.
+func (ep *Endpoint) connectionHelper() (*connhelper.ConnectionHelper, error) {
+	opts := ep.sshOptions()
+	if ep.SSH != nil && ep.SSH.Transport == context.SSHTransportNative && strings.HasPrefix(ep.Host, "ssh://") {
+		helper, err := connhelper.GetNativeSSHConnectionHelper(ep.Host, opts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ssh options: %w", err)
+		}
+		return helper, nil
+	}
+	sshFlags, err := opts.Flags()
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh options: %w", err)
+	}
+	return connhelper.GetConnectionHelperWithSSHOpts(ep.Host, sshFlags)
+}
+
 // tlsConfig extracts a context docker endpoint TLS config
 func (ep *Endpoint) tlsConfig() (*tls.Config, error) {
 	if ep.TLSData == nil && !ep.SkipTLSVerify {
@@ -87,7 +135,7 @@ func (ep *Endpoint) tlsConfig() (*tls.Config, error) {
 func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
 	var result []client.Opt
 	if ep.Host != "" {
-		helper, err := connhelper.GetConnectionHelper(ep.Host)
+		helper, err := ep.connectionHelper()
 		if err != nil {
 			return nil, err
 		}
@@ -4,4 +4,51 @@
 type EndpointMetaBase struct {
 	Host          string `json:",omitempty"`
 	SkipTLSVerify bool
+	SSH           *SSHOptions `json:",omitempty"`
+
+	// FailoverHosts are hosts to connect to if Host is not reachable. They
+	// use the same TLS and SSH options as Host.
+	FailoverHosts []string `json:",omitempty"`
+	// FailoverStrategy is the strategy to select a host if the endpoint has
+	// failover hosts; either [FailoverOrder] (the default), or [FailoverLatency].
+	FailoverStrategy string `json:",omitempty"`
+}
+
+// Hosts returns the host of the endpoint, followed by its failover hosts.
+func (ep EndpointMetaBase) Hosts() []string {
+	hosts := make([]string, 0, 1+len(ep.FailoverHosts))
+	if ep.Host != "" {
+		hosts = append(hosts, ep.Host)
+	}
+	return append(hosts, ep.FailoverHosts...)
 }
+
+// SSHOptions contains options for endpoints that connect over SSH ("ssh://"
+// hosts). The identity file and known_hosts file are stored with the
+// endpoint's TLS material (see [TLSData]).
+type SSHOptions struct {
+	// ProxyJump is a comma-separated list of jump hosts to connect through.
+	ProxyJump string `json:",omitempty"`
+	// Options are ssh options in "Key=Value" format, as used in ssh_config(5).
+	Options []string `json:",omitempty"`
+	// Transport is the ssh client that's used to connect; either
+	// [SSHTransportExec] (the default), or [SSHTransportNative].
+	Transport string `json:",omitempty"`
+}
+
+const (
+	// SSHTransportExec connects using the ssh binary.
+	SSHTransportExec = "exec"
+	// SSHTransportNative connects using the built-in ssh client, which does
+	// not require the ssh binary, but only supports a subset of the ssh
+	// options, and ignores ~/.ssh/config.
+	SSHTransportNative = "native"
+)
+
+const (
+	// FailoverOrder connects to the first reachable host, starting with the
+	// last host that was healthy.
+	FailoverOrder = "order"
+	// FailoverLatency connects to the reachable host with the lowest latency.
+	FailoverLatency = "latency"
+)
@@ -21,6 +21,9 @@
 //	    <context id>/endpoint1/: directory containing TLS data for the endpoint1
 //	                             in the corresponding context.
 //
+// TLS files that hold private keys can be encrypted at rest with a key that's
+// provided by a [KeyStore], such as a credential helper; see [WithTLSEncryption].
+//
 // The context store itself has absolutely no knowledge about what a docker
 // endpoint should contain in term of metadata or TLS config. Client code is
 // responsible for generating and parsing endpoint metadata and TLS files. The
@@ -0,0 +1,176 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted exports start with encryptedMagic, followed by a JSON-encoded
+// [encryptionHeader] on a single line, and the encrypted archive. The magic
+// and the header are authenticated as additional data, so that the
+// parameters cannot be changed without detection.
+const (
+	encryptedMagic = "docker-context-encrypted/v1\n"
+	encryptedType  = "application/vnd.docker.context.encrypted"
+
+	kdfScrypt       = "scrypt"
+	cipherAES256GCM = "aes-256-gcm"
+
+	// scrypt parameters for new exports; N=2^15, r=8 uses 32 MiB of memory.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	// maxScryptN, maxScryptR, and maxScryptCost limit the parameters that
+	// are accepted when importing, to limit memory and CPU use for untrusted
+	// files. scrypt uses 128*N*r bytes of memory, and its CPU use is
+	// proportional to 128*N*r*p, which is limited to 256 MiB.
+	maxScryptN    = 1 << 18
+	maxScryptR    = 8
+	maxScryptCost = 256 << 20
+
+	maxEncryptionHeaderSize = 4096
+	saltSize                = 16
+	keySize                 = 32
+)
+
+// ErrEncrypted is returned by [Import] when importing an encrypted export.
+// Encrypted exports must be decrypted with [Decrypt] before importing.
+var ErrEncrypted = errors.New("context is encrypted: a passphrase is required to import it")
+
+type encryptionHeader struct {
+	KDF    string `json:"kdf"`
+	N      int    `json:"n"`
+	R      int    `json:"r"`
+	P      int    `json:"p"`
+	Salt   []byte `json:"salt"`
+	Cipher string `json:"cipher"`
+	Nonce  []byte `json:"nonce"`
+}
+
+// Encrypt encrypts an exported context (see [Export]) with a key that's
+// derived from the passphrase, using scrypt and AES-256-GCM. The encrypted
+// export can be imported with [Import] after decrypting it with [Decrypt].
+func Encrypt(r io.Reader, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	plaintext, err := io.ReadAll(&limitedReader{R: r, N: maxAllowedFileSizeToImport})
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := encryptionHeader{
+		KDF:    kdfScrypt,
+		N:      scryptN,
+		R:      scryptR,
+		P:      scryptP,
+		Salt:   make([]byte, saltSize),
+		Cipher: cipherAES256GCM,
+	}
+	if _, err := rand.Read(hdr.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(hdr, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	hdr.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(hdr.Nonce); err != nil {
+		return nil, err
+	}
+	hdrBytes, err := json.Marshal(hdr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := bytes.NewBufferString(encryptedMagic)
+	out.Write(hdrBytes)
+	out.WriteByte('\n')
+	return aead.Seal(out.Bytes(), hdr.Nonce, plaintext, out.Bytes()), nil
+}
+
+// Decrypt decrypts an encrypted export (see [Encrypt]), and returns the
+// exported context, which can be imported with [Import].
+func Decrypt(r io.Reader, passphrase []byte) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, maxEncryptionHeaderSize)
+	if !IsEncrypted(br) {
+		return nil, invalidParameter(errors.New("context is not encrypted"))
+	}
+	magic := make([]byte, len(encryptedMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	hdrBytes, err := br.ReadSlice('\n')
+	if err != nil {
+		return nil, invalidParameter(fmt.Errorf("invalid encrypted context: %w", err))
+	}
+	// hdrBytes refers to the reader's buffer, which is reused by later reads.
+	additionalData := append(magic, hdrBytes...)
+
+	var hdr encryptionHeader
+	if err := json.Unmarshal(additionalData[len(magic):], &hdr); err != nil {
+		return nil, invalidParameter(fmt.Errorf("invalid encrypted context: %w", err))
+	}
+	aead, err := newAEAD(hdr, passphrase)
+	if err != nil {
+		return nil, invalidParameter(fmt.Errorf("invalid encrypted context: %w", err))
+	}
+	if len(hdr.Nonce) != aead.NonceSize() {
+		return nil, invalidParameter(errors.New("invalid encrypted context: invalid nonce"))
+	}
+	ciphertext, err := io.ReadAll(&limitedReader{R: br, N: maxAllowedFileSizeToImport + int64(aead.Overhead())})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, hdr.Nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, invalidParameter(errors.New("failed to decrypt context: incorrect passphrase, or the file is corrupted"))
+	}
+	return bytes.NewReader(plaintext), nil
+}
+
+// IsEncrypted returns whether the reader starts with an encrypted export
+// (see [Encrypt]). It does not advance the reader.
+func IsEncrypted(r *bufio.Reader) bool {
+	head, _ := r.Peek(len(encryptedMagic))
+	return string(head) == encryptedMagic
+}
+
+// newAEAD returns the cipher for the header's parameters, using a key
+// that's derived from the passphrase.
+func newAEAD(hdr encryptionHeader, passphrase []byte) (cipher.AEAD, error) {
+	if hdr.KDF != kdfScrypt {
+		return nil, fmt.Errorf("unsupported key derivation function %q", hdr.KDF)
+	}
+	if hdr.Cipher != cipherAES256GCM {
+		return nil, fmt.Errorf("unsupported cipher %q", hdr.Cipher)
+	}
+	if hdr.N <= 1 || hdr.N > maxScryptN || hdr.R <= 0 || hdr.R > maxScryptR || hdr.P <= 0 || hdr.P > maxScryptCost/(128*hdr.N*hdr.R) {
+		return nil, errors.New("unsupported key derivation parameters")
+	}
+	if len(hdr.Salt) < saltSize {
+		return nil, errors.New("invalid salt")
+	}
+	key, err := scrypt.Key(passphrase, hdr.Salt, hdr.N, hdr.R, hdr.P, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
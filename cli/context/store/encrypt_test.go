@@ -0,0 +1,152 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/containerd/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func encryptedExport(t *testing.T, s *ContextStore, passphrase string) []byte {
+	t.Helper()
+	assert.NilError(t, s.CreateOrUpdate(Metadata{
+		Endpoints: map[string]any{"ep1": endpoint{Foo: "bar"}},
+		Metadata:  context{Bar: "baz"},
+		Name:      "source",
+	}))
+	assert.NilError(t, s.ResetEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"key.pem": []byte("private key")},
+	}))
+	r := Export("source", s)
+	defer r.Close()
+	encrypted, err := Encrypt(r, []byte(passphrase))
+	assert.NilError(t, err)
+	return encrypted
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	s := New(t.TempDir(), testCfg)
+	encrypted := encryptedExport(t, s, "correct horse")
+	assert.Check(t, bytes.HasPrefix(encrypted, []byte(encryptedMagic)))
+	assert.Check(t, !bytes.Contains(encrypted, []byte("private key")))
+
+	ct, err := getImportContentType(bufio.NewReader(bytes.NewReader(encrypted)))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ct, encryptedType))
+
+	r, err := Decrypt(bytes.NewReader(encrypted), []byte("correct horse"))
+	assert.NilError(t, err)
+	assert.NilError(t, Import("dest", s, r))
+
+	srcMeta, err := s.GetMetadata("source")
+	assert.NilError(t, err)
+	destMeta, err := s.GetMetadata("dest")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(destMeta.Endpoints, srcMeta.Endpoints))
+	data, err := s.GetTLSData("dest", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "private key"))
+}
+
+func TestImportEncrypted(t *testing.T) {
+	s := New(t.TempDir(), testCfg)
+	encrypted := encryptedExport(t, s, "correct horse")
+
+	err := Import("dest", s, bytes.NewReader(encrypted))
+	assert.Check(t, is.ErrorIs(err, ErrEncrypted))
+	assert.Check(t, is.ErrorType(err, errdefs.IsInvalidArgument))
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	s := New(t.TempDir(), testCfg)
+	encrypted := encryptedExport(t, s, "correct horse")
+	header, _, _ := strings.Cut(strings.TrimPrefix(string(encrypted), encryptedMagic), "\n")
+
+	tests := []struct {
+		doc         string
+		data        []byte
+		passphrase  string
+		expectedErr string
+	}{
+		{
+			doc:         "incorrect passphrase",
+			data:        encrypted,
+			passphrase:  "battery staple",
+			expectedErr: "failed to decrypt context: incorrect passphrase, or the file is corrupted",
+		},
+		{
+			doc:         "corrupted content",
+			data:        append(bytes.Clone(encrypted[:len(encrypted)-1]), encrypted[len(encrypted)-1]^1),
+			passphrase:  "correct horse",
+			expectedErr: "failed to decrypt context: incorrect passphrase, or the file is corrupted",
+		},
+		{
+			doc:         "modified parameters",
+			data:        bytes.Replace(encrypted, []byte(`"p":1`), []byte(`"p":2`), 1),
+			passphrase:  "correct horse",
+			expectedErr: "failed to decrypt context: incorrect passphrase, or the file is corrupted",
+		},
+		{
+			doc:         "excessive parameters",
+			data:        bytes.Replace(encrypted, []byte(`"n":32768`), []byte(`"n":1073741824`), 1),
+			passphrase:  "correct horse",
+			expectedErr: "invalid encrypted context: unsupported key derivation parameters",
+		},
+		{
+			doc:         "excessive memory",
+			data:        bytes.Replace(encrypted, []byte(`"r":8`), []byte(`"r":32`), 1),
+			passphrase:  "correct horse",
+			expectedErr: "invalid encrypted context: unsupported key derivation parameters",
+		},
+		{
+			doc:         "excessive cost",
+			data:        bytes.Replace(bytes.Replace(encrypted, []byte(`"n":32768`), []byte(`"n":262144`), 1), []byte(`"p":1`), []byte(`"p":16`), 1),
+			passphrase:  "correct horse",
+			expectedErr: "invalid encrypted context: unsupported key derivation parameters",
+		},
+		{
+			doc:         "overflowing cost",
+			data:        bytes.Replace(encrypted, []byte(`"p":1`), []byte(`"p":4611686018427387904`), 1),
+			passphrase:  "correct horse",
+			expectedErr: "invalid encrypted context: unsupported key derivation parameters",
+		},
+		{
+			doc:         "unsupported cipher",
+			data:        []byte(encryptedMagic + strings.Replace(header, "aes-256-gcm", "rot13", 1) + "\n"),
+			passphrase:  "correct horse",
+			expectedErr: `invalid encrypted context: unsupported cipher "rot13"`,
+		},
+		{
+			doc:         "missing header",
+			data:        []byte(encryptedMagic),
+			passphrase:  "correct horse",
+			expectedErr: "invalid encrypted context: EOF",
+		},
+		{
+			doc:         "not encrypted",
+			data:        []byte("plain tar"),
+			passphrase:  "correct horse",
+			expectedErr: "context is not encrypted",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			_, err := Decrypt(bytes.NewReader(tc.data), []byte(tc.passphrase))
+			assert.Check(t, is.Error(err, tc.expectedErr))
+			assert.Check(t, is.ErrorType(err, errdefs.IsInvalidArgument))
+		})
+	}
+}
+
+func TestEncryptEmptyPassphrase(t *testing.T) {
+	_, err := Encrypt(io.LimitReader(nil, 0), nil)
+	assert.Check(t, is.Error(err, "passphrase must not be empty"))
+}
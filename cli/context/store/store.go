@@ -18,6 +18,7 @@
 	"path/filepath"
 	"strings"
 
+	"github.com/docker/cli/internal/contextname"
 	"github.com/opencontainers/go-digest"
 )
 
@@ -89,13 +90,33 @@ type ContextTLSData struct {
 	Endpoints map[string]EndpointTLSData
 }
 
+// Option configures a [ContextStore].
+type Option func(*ContextStore)
+
+// WithTLSEncryption enables encryption at rest for the TLS files with the
+// given names, using the key that's provided by the key store. Files are
+// stored unencrypted if the key store does not return a key.
+//
+// Encrypted files are decrypted on demand by [ContextStore.GetTLSData]. Files
+// that are not encrypted, for example, because they were stored before
+// encryption was enabled, are returned as-is.
+func WithTLSEncryption(keyStore KeyStore, fileNames ...string) Option {
+	return func(s *ContextStore) {
+		s.tls.keyStore = keyStore
+		s.tls.encryptedFiles = make(map[string]struct{}, len(fileNames))
+		for _, f := range fileNames {
+			s.tls.encryptedFiles[f] = struct{}{}
+		}
+	}
+}
+
 // New creates a store from a given directory.
 // If the directory does not exist or is empty, initialize it
-func New(dir string, cfg Config) *ContextStore {
+func New(dir string, cfg Config, opts ...Option) *ContextStore {
 	metaRoot := filepath.Join(dir, metadataDir)
 	tlsRoot := filepath.Join(dir, tlsDir)
 
-	return &ContextStore{
+	s := &ContextStore{
 		meta: &metadataStore{
 			root:   metaRoot,
 			config: cfg,
@@ -104,6 +125,10 @@ func New(dir string, cfg Config) *ContextStore {
 			root: tlsRoot,
 		},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ContextStore implements Store.
@@ -198,7 +223,8 @@ func (s *ContextStore) ListTLSFiles(name string) (map[string]EndpointFiles, erro
 }
 
 // GetTLSData reads, and returns the content of the given fileName for an endpoint.
-// It returns an errdefs.ErrNotFound if the file was not found.
+// It returns an errdefs.ErrNotFound if the file was not found. Files that are
+// encrypted at rest (see [WithTLSEncryption]) are decrypted.
 func (s *ContextStore) GetTLSData(contextName, endpointName, fileName string) ([]byte, error) {
 	return s.tls.getData(contextName, endpointName, fileName)
 }
@@ -214,47 +240,7 @@ func (s *ContextStore) GetStorageInfo(contextName string) StorageInfo {
 
 // ValidateContextName checks a context name is valid.
 func ValidateContextName(name string) error {
-	if name == "" {
-		return errors.New("context name cannot be empty")
-	}
-	if name == "default" {
-		return errors.New(`"default" is a reserved context name`)
-	}
-	if !isValidName(name) {
-		return fmt.Errorf("context name %q is invalid, names are validated against regexp %q", name, validNameFormat)
-	}
-	return nil
-}
-
-// validNameFormat is used as part of errors for invalid context-names.
-// We should consider making this less technical ("must start with "a-z",
-// and only consist of alphanumeric characters and separators").
-const validNameFormat = `^[a-zA-Z0-9][a-zA-Z0-9_.+-]+$`
-
-// isValidName checks if the context-name is valid ("^[a-zA-Z0-9][a-zA-Z0-9_.+-]+$").
-//
-// Names must start with an alphanumeric character (a-zA-Z0-9), followed by
-// alphanumeric or separators ("_", ".", "+", "-").
-func isValidName(s string) bool {
-	if len(s) < 2 || !isAlphaNum(s[0]) {
-		return false
-	}
-
-	for i := 1; i < len(s); i++ {
-		c := s[i]
-		if isAlphaNum(c) || c == '_' || c == '.' || c == '+' || c == '-' {
-			continue
-		}
-		return false
-	}
-
-	return true
-}
-
-func isAlphaNum(c byte) bool {
-	return (c >= 'a' && c <= 'z') ||
-		(c >= 'A' && c <= 'Z') ||
-		(c >= '0' && c <= '9')
+	return contextname.Validate(name)
 }
 
 // Export exports an existing namespace into an opaque data stream
@@ -342,6 +328,9 @@ func Export(name string, s Reader) io.ReadCloser {
 )
 
 func getImportContentType(r *bufio.Reader) (string, error) {
+	if IsEncrypted(r) {
+		return encryptedType, nil
+	}
 	head, err := r.Peek(512)
 	if err != nil && err != io.EOF {
 		return "", err
@@ -362,6 +351,8 @@ func Import(name string, s Writer, reader io.Reader) error {
 	switch importContentType {
 	case zipType:
 		return importZip(name, s, r)
+	case encryptedType:
+		return invalidParameter(ErrEncrypted)
 	default:
 		// Assume it's a TAR (TAR does not have a "magic number")
 		return importTar(name, s, r)
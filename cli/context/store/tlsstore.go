@@ -1,6 +1,11 @@
 package store
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,10 +13,29 @@
 	"github.com/moby/sys/atomicwriter"
 )
 
-const tlsDir = "tls"
+const (
+	tlsDir = "tls"
+
+	// Encrypted TLS files start with encryptedTLSMagic, followed by the
+	// nonce, and the data, encrypted with AES-256-GCM. The magic is
+	// authenticated as additional data.
+	encryptedTLSMagic = "docker-context-tls-encrypted/v1\n"
+)
+
+// KeyStore provides the key that's used to encrypt TLS data at rest.
+type KeyStore interface {
+	// GetKey returns the 32-byte key to encrypt and decrypt TLS data with.
+	// It returns a nil key if TLS data must be stored unencrypted.
+	GetKey() ([]byte, error)
+}
 
 type tlsStore struct {
 	root string
+
+	// keyStore and encryptedFiles are set if TLS data is encrypted at rest;
+	// see [WithTLSEncryption].
+	keyStore       KeyStore
+	encryptedFiles map[string]struct{}
 }
 
 func (s *tlsStore) contextDir(name string) string {
@@ -31,6 +55,12 @@ func (s *tlsStore) createOrUpdate(name, endpointName, filename string, data []by
 	if err := os.MkdirAll(endpointDir, 0o700); err != nil {
 		return err
 	}
+	if _, ok := s.encryptedFiles[filename]; ok {
+		var err error
+		if data, err = s.encrypt(data); err != nil {
+			return fmt.Errorf("failed to encrypt TLS data for endpoint %s: %w", endpointName, err)
+		}
+	}
 	return atomicwriter.WriteFile(filepath.Join(endpointDir, filename), data, 0o600)
 }
 
@@ -42,9 +72,81 @@ func (s *tlsStore) getData(name, endpointName, filename string) ([]byte, error)
 		}
 		return nil, fmt.Errorf("failed to read TLS data for endpoint %s: %w", endpointName, err)
 	}
+	if !isEncryptedTLSData(data) {
+		// TLS data that was stored before encryption was enabled is read as-is.
+		return data, nil
+	}
+	data, err = s.decrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt TLS data for %s/%s/%s: %w", name, endpointName, filename, err)
+	}
 	return data, nil
 }
 
+// encrypt encrypts the data with the key store's key, or returns it as-is if
+// the key store does not return a key.
+func (s *tlsStore) encrypt(data []byte) ([]byte, error) {
+	if s.keyStore == nil {
+		return data, nil
+	}
+	key, err := s.keyStore.GetKey()
+	if err != nil || key == nil {
+		return data, err
+	}
+	aead, err := newTLSAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(encryptedTLSMagic)+aead.NonceSize(), len(encryptedTLSMagic)+aead.NonceSize()+len(data)+aead.Overhead())
+	copy(out, encryptedTLSMagic)
+	nonce := out[len(encryptedTLSMagic):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, nonce, data, []byte(encryptedTLSMagic)), nil
+}
+
+func (s *tlsStore) decrypt(data []byte) ([]byte, error) {
+	var key []byte
+	if s.keyStore != nil {
+		var err error
+		if key, err = s.keyStore.GetKey(); err != nil {
+			return nil, err
+		}
+	}
+	if key == nil {
+		return nil, errors.New("the data is encrypted, but no encryption key is configured")
+	}
+	aead, err := newTLSAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(encryptedTLSMagic):]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("invalid encrypted data")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(encryptedTLSMagic))
+	if err != nil {
+		return nil, errors.New("incorrect encryption key, or the data is corrupted")
+	}
+	return plaintext, nil
+}
+
+func isEncryptedTLSData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedTLSMagic))
+}
+
+func newTLSAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("invalid encryption key: must be %d bytes", keySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
 // remove deletes all TLS data for the given context.
 func (s *tlsStore) remove(name string) error {
 	if err := os.RemoveAll(s.contextDir(name)); err != nil {
@@ -1,10 +1,14 @@
 package store
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/containerd/errdefs"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestTlsCreateUpdateGetRemove(t *testing.T) {
@@ -70,3 +74,75 @@ func TestTlsListAndBatchRemove(t *testing.T) {
 	assert.NilError(t, err)
 	assert.DeepEqual(t, resEmpty, map[string]EndpointFiles{})
 }
+
+type fakeKeyStore struct {
+	key []byte
+}
+
+func (ks *fakeKeyStore) GetKey() ([]byte, error) {
+	return ks.key, nil
+}
+
+func TestTlsEncryption(t *testing.T) {
+	keyStore := &fakeKeyStore{key: bytes.Repeat([]byte{'k'}, keySize)}
+	testee := tlsStore{root: t.TempDir(), keyStore: keyStore, encryptedFiles: map[string]struct{}{"key.pem": {}}}
+
+	const contextName = "test-ctx"
+	assert.NilError(t, testee.createOrUpdate(contextName, "test-ep", "key.pem", []byte("private key")))
+	assert.NilError(t, testee.createOrUpdate(contextName, "test-ep", "ca.pem", []byte("ca")))
+
+	raw, err := os.ReadFile(filepath.Join(testee.endpointDir(contextName, "test-ep"), "key.pem"))
+	assert.NilError(t, err)
+	assert.Check(t, isEncryptedTLSData(raw))
+	assert.Check(t, !bytes.Contains(raw, []byte("private key")))
+	raw, err = os.ReadFile(filepath.Join(testee.endpointDir(contextName, "test-ep"), "ca.pem"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(raw), "ca"))
+
+	data, err := testee.getData(contextName, "test-ep", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "private key"))
+
+	// files that were written before encryption was enabled are read as-is.
+	keyStore.key = nil
+	assert.NilError(t, testee.createOrUpdate(contextName, "plain-ep", "key.pem", []byte("plain key")))
+	keyStore.key = bytes.Repeat([]byte{'k'}, keySize)
+	data, err = testee.getData(contextName, "plain-ep", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "plain key"))
+
+	keyStore.key = bytes.Repeat([]byte{'x'}, keySize)
+	_, err = testee.getData(contextName, "test-ep", "key.pem")
+	assert.Check(t, is.Error(err, "failed to decrypt TLS data for test-ctx/test-ep/key.pem: incorrect encryption key, or the data is corrupted"))
+
+	keyStore.key = nil
+	_, err = testee.getData(contextName, "test-ep", "key.pem")
+	assert.Check(t, is.Error(err, "failed to decrypt TLS data for test-ctx/test-ep/key.pem: the data is encrypted, but no encryption key is configured"))
+
+	unconfigured := tlsStore{root: testee.root}
+	_, err = unconfigured.getData(contextName, "test-ep", "key.pem")
+	assert.Check(t, is.ErrorContains(err, "no encryption key is configured"))
+}
+
+func TestWithTLSEncryptionExport(t *testing.T) {
+	keyStore := &fakeKeyStore{key: bytes.Repeat([]byte{'k'}, keySize)}
+	s := New(t.TempDir(), testCfg, WithTLSEncryption(keyStore, "key.pem"))
+	assert.NilError(t, s.CreateOrUpdate(Metadata{
+		Endpoints: map[string]any{"ep1": endpoint{Foo: "bar"}},
+		Metadata:  context{Bar: "baz"},
+		Name:      "source",
+	}))
+	assert.NilError(t, s.ResetEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"key.pem": []byte("private key")},
+	}))
+
+	// exported contexts hold the decrypted TLS data, so that they can be
+	// imported in other context stores.
+	other := New(t.TempDir(), testCfg)
+	r := Export("source", s)
+	defer r.Close()
+	assert.NilError(t, Import("dest", other, r))
+	data, err := other.GetTLSData("dest", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "private key"))
+}
@@ -11,14 +11,28 @@
 const (
 	caKey   = "ca.pem"
 	certKey = "cert.pem"
-	keyKey  = "key.pem"
+
+	// TLSKeyFile is the name of the file that holds the private key of
+	// endpoints that connect over TLS.
+	TLSKeyFile = "key.pem"
+
+	// SSHIdentityFile is the name of the file that holds the private key
+	// of endpoints that connect over SSH.
+	SSHIdentityFile = "ssh_identity"
+	// SSHKnownHostsFile is the name of the file that holds the known_hosts
+	// of endpoints that connect over SSH.
+	SSHKnownHostsFile = "ssh_known_hosts"
 )
 
-// TLSData holds ca/cert/key raw data
+// TLSData holds ca/cert/key raw data, and the identity and known_hosts
+// files of endpoints that connect over SSH.
 type TLSData struct {
 	CA   []byte
 	Key  []byte
 	Cert []byte
+
+	SSHIdentity   []byte
+	SSHKnownHosts []byte
 }
 
 // ToStoreTLSData converts TLSData to the store representation
@@ -36,7 +50,13 @@ func (data *TLSData) ToStoreTLSData() *store.EndpointTLSData {
 		result.Files[certKey] = data.Cert
 	}
 	if data.Key != nil {
-		result.Files[keyKey] = data.Key
+		result.Files[TLSKeyFile] = data.Key
+	}
+	if data.SSHIdentity != nil {
+		result.Files[SSHIdentityFile] = data.SSHIdentity
+	}
+	if data.SSHKnownHosts != nil {
+		result.Files[SSHKnownHostsFile] = data.SSHKnownHosts
 	}
 	return &result
 }
@@ -59,8 +79,12 @@ func LoadTLSData(s store.Reader, contextName, endpointName string) (*TLSData, er
 				tlsData.CA = data
 			case certKey:
 				tlsData.Cert = data
-			case keyKey:
+			case TLSKeyFile:
 				tlsData.Key = data
+			case SSHIdentityFile:
+				tlsData.SSHIdentity = data
+			case SSHKnownHostsFile:
+				tlsData.SSHKnownHosts = data
 			default:
 				logrus.Warnf("unknown file in context %s TLS bundle: %s", contextName, f)
 			}
@@ -6,6 +6,7 @@
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/distribution/reference"
@@ -13,6 +14,7 @@
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
 )
 
 // Store manages local storage of image distribution manifests
@@ -21,8 +23,45 @@ type Store interface {
 	Get(listRef reference.Reference, manifest reference.Reference) (types.ImageManifest, error)
 	GetList(listRef reference.Reference) ([]types.ImageManifest, error)
 	Save(listRef reference.Reference, manifest reference.Reference, image types.ImageManifest) error
+	List() ([]ManifestList, error)
+	RemoveAll() error
+	GetMetadata(listRef reference.Reference) (ListMetadata, error)
+	SaveMetadata(listRef reference.Reference, meta ListMetadata) error
 }
 
+// ManifestList describes a manifest list in local storage.
+type ManifestList struct {
+	ListMetadata
+
+	// Name is the name of the directory holding the manifest list.
+	Name string
+	// Manifests are the image manifests in the list.
+	Manifests []types.ImageManifest
+}
+
+// ListMetadata holds the properties of a manifest list that are not part of
+// its image manifests.
+type ListMetadata struct {
+	// Ref is the reference of the manifest list. It is empty for manifest
+	// lists created by older versions of the CLI, which did not record it.
+	Ref string
+	// Created is the time the manifest list was created.
+	Created time.Time
+	// OCI indicates that the manifest list is pushed as an OCI image index
+	// instead of a Docker manifest list.
+	OCI bool `json:",omitempty"`
+	// ArtifactType is the artifact type of the OCI image index.
+	ArtifactType string `json:",omitempty"`
+	// Annotations are the annotations of the OCI image index.
+	Annotations map[string]string `json:",omitempty"`
+}
+
+// metadataDir is the directory holding the [ListMetadata] of the manifest
+// lists, in a file per list. It is kept outside the directories of the lists,
+// as older versions of the CLI read all files in those as manifests. It is
+// prefixed with a dot to separate it from the manifest lists.
+const metadataDir = ".metadata"
+
 // fsStore manages manifest files stored on the local filesystem
 type fsStore struct {
 	root string
@@ -35,8 +74,31 @@ func NewStore(root string) Store {
 
 // Remove a manifest list from local storage
 func (s *fsStore) Remove(listRef reference.Reference) error {
-	path := filepath.Join(s.root, makeFilesafeName(listRef.String()))
-	return os.RemoveAll(path)
+	name := makeFilesafeName(listRef.String())
+	if err := os.Remove(s.metadataFilename(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(s.root, name))
+}
+
+// RemoveAll removes all manifest lists from local storage
+func (s *fsStore) RemoveAll() error {
+	entries, err := os.ReadDir(s.root)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.root, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Get returns the local manifest
@@ -108,9 +170,130 @@ func (s *fsStore) GetList(listRef reference.Reference) ([]types.ImageManifest, e
 	return manifests, nil
 }
 
+// List returns all manifest lists in local storage
+func (s *fsStore) List() ([]ManifestList, error) {
+	entries, err := os.ReadDir(s.root)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	lists := make([]ManifestList, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == metadataDir {
+			continue
+		}
+		list, err := s.getListFromDir(entry.Name())
+		if err != nil {
+			// Don't fail on a single corrupt manifest list, so that the
+			// other lists can still be listed.
+			logrus.Warnf("skipping invalid manifest list %s: %v", entry.Name(), err)
+			continue
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+func (s *fsStore) getListFromDir(name string) (ManifestList, error) {
+	listDir := filepath.Join(s.root, name)
+	meta, err := s.getMetadata(name)
+	if err != nil {
+		return ManifestList{}, err
+	}
+	filenames, err := s.listManifestsInDir(listDir)
+	if err != nil {
+		return ManifestList{}, err
+	}
+	list := ManifestList{
+		ListMetadata: meta,
+		Name:         name,
+		Manifests:    make([]types.ImageManifest, 0, len(filenames)),
+	}
+	for _, filename := range filenames {
+		manifest, err := s.getFromFilename(fsRef(filename), filepath.Join(listDir, filename))
+		if err != nil {
+			return ManifestList{}, err
+		}
+		list.Manifests = append(list.Manifests, manifest)
+	}
+	return list, nil
+}
+
+// metadataFilename returns the name of the file holding the metadata of the
+// manifest list that's stored in the directory with the given name.
+func (s *fsStore) metadataFilename(name string) string {
+	return filepath.Join(s.root, metadataDir, name+".json")
+}
+
+// getMetadata returns the metadata of the manifest list that's stored in the
+// directory with the given name. Manifest lists created by older versions of
+// the CLI have no metadata, in which case the modification time of the
+// directory is used as creation time.
+func (s *fsStore) getMetadata(name string) (ListMetadata, error) {
+	// The metadata of a manifest list that was removed by an older version
+	// of the CLI may be left behind, so check that the list exists.
+	fi, err := os.Stat(filepath.Join(s.root, name))
+	if err != nil {
+		return ListMetadata{}, err
+	}
+	filename := s.metadataFilename(name)
+	bytes, err := os.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err):
+		return ListMetadata{Created: fi.ModTime()}, nil
+	case err != nil:
+		return ListMetadata{}, err
+	}
+	var meta ListMetadata
+	if err := json.Unmarshal(bytes, &meta); err != nil {
+		return ListMetadata{}, fmt.Errorf("invalid manifest list metadata in %s: %w", filename, err)
+	}
+	return meta, nil
+}
+
+// GetMetadata returns the metadata of a local manifest list
+func (s *fsStore) GetMetadata(listRef reference.Reference) (ListMetadata, error) {
+	meta, err := s.getMetadata(makeFilesafeName(listRef.String()))
+	if os.IsNotExist(err) {
+		return ListMetadata{}, newNotFoundError(listRef.String())
+	}
+	return meta, err
+}
+
+// SaveMetadata saves the metadata of a local manifest list
+func (s *fsStore) SaveMetadata(listRef reference.Reference, meta ListMetadata) error {
+	if err := s.createManifestListDirectory(listRef.String()); err != nil {
+		return err
+	}
+	if meta.Ref == "" {
+		meta.Ref = listRef.String()
+	}
+	if meta.Created.IsZero() {
+		meta.Created = time.Now().UTC()
+	}
+	return s.writeMetadata(makeFilesafeName(listRef.String()), meta)
+}
+
+func (s *fsStore) writeMetadata(name string, meta ListMetadata) error {
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(s.root, metadataDir), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.metadataFilename(name), bytes, 0o644)
+}
+
 // listManifests stored in a transaction
 func (s *fsStore) listManifests(transaction string) ([]string, error) {
-	transactionDir := filepath.Join(s.root, makeFilesafeName(transaction))
+	return s.listManifestsInDir(filepath.Join(s.root, makeFilesafeName(transaction)))
+}
+
+func (*fsStore) listManifestsInDir(transactionDir string) ([]string, error) {
 	fileInfos, err := os.ReadDir(transactionDir)
 	switch {
 	case os.IsNotExist(err):
@@ -140,8 +323,17 @@ func (s *fsStore) Save(listRef reference.Reference, manifest reference.Reference
 }
 
 func (s *fsStore) createManifestListDirectory(transaction string) error {
-	path := filepath.Join(s.root, makeFilesafeName(transaction))
-	return os.MkdirAll(path, 0o755)
+	name := makeFilesafeName(transaction)
+	path := filepath.Join(s.root, name)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+	// This replaces the metadata of a manifest list with the same name that
+	// was removed by an older version of the CLI, if any.
+	return s.writeMetadata(name, ListMetadata{Ref: transaction, Created: time.Now().UTC()})
 }
 
 func manifestToFilename(root, manifestList, manifest string) string {
@@ -153,6 +345,14 @@ func makeFilesafeName(ref string) string {
 	return strings.ReplaceAll(fileName, "/", "_")
 }
 
+// fsRef is a [reference.Reference] for a manifest that is only known by its
+// filename.
+type fsRef string
+
+func (r fsRef) String() string {
+	return string(r)
+}
+
 func newNotFoundError(ref string) error {
 	return errdefs.ErrNotFound.WithMessage("No such manifest: " + ref)
 }
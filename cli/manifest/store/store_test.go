@@ -2,6 +2,7 @@
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/containerd/errdefs"
@@ -44,10 +45,12 @@ func TestStoreRemove(t *testing.T) {
 
 	files, err := os.ReadDir(tmpDir)
 	assert.NilError(t, err)
-	assert.Assert(t, is.Len(files, 1))
+	assert.Assert(t, is.Len(files, 2)) // the list and its metadata
 
 	assert.Check(t, store.Remove(listRef))
-	files, err = os.ReadDir(tmpDir)
+	_, err = os.Stat(filepath.Join(tmpDir, "list"))
+	assert.Check(t, os.IsNotExist(err))
+	files, err = os.ReadDir(filepath.Join(tmpDir, metadataDir))
 	assert.NilError(t, err)
 	assert.Check(t, is.Len(files, 0))
 }
@@ -120,3 +123,110 @@ func TestStoreGetListDoesNotExist(t *testing.T) {
 	assert.Error(t, err, "No such manifest: list")
 	assert.Check(t, errdefs.IsNotFound(err))
 }
+
+func TestStoreList(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+	assert.NilError(t, store.Save(ref("example.com/list:1"), ref("first"), types.ImageManifest{Ref: sref(t, "first")}))
+	assert.NilError(t, store.Save(ref("example.com/list:1"), ref("second"), types.ImageManifest{Ref: sref(t, "second")}))
+	assert.NilError(t, store.Save(ref("example.com/list:2"), ref("third"), types.ImageManifest{Ref: sref(t, "third")}))
+
+	// manifest list created by an older version of the CLI, without metadata
+	legacy := types.ImageManifest{Ref: sref(t, "legacy")}
+	assert.NilError(t, store.Save(ref("legacy"), ref("legacy"), legacy))
+	assert.NilError(t, os.Remove(filepath.Join(tmpDir, metadataDir, "legacy.json")))
+
+	// a corrupt manifest list is skipped.
+	assert.NilError(t, os.MkdirAll(filepath.Join(tmpDir, "corrupt"), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(tmpDir, "corrupt", "manifest"), []byte("{invalid"), 0o644))
+
+	lists, err := store.List()
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(lists, 3))
+
+	assert.Check(t, is.Equal(lists[0].Name, "example.com_list-1"))
+	assert.Check(t, is.Equal(lists[0].Ref, "example.com/list:1"))
+	assert.Check(t, !lists[0].Created.IsZero())
+	assert.Check(t, is.Len(lists[0].Manifests, 2))
+
+	assert.Check(t, is.Equal(lists[1].Ref, "example.com/list:2"))
+	assert.Check(t, is.Len(lists[1].Manifests, 1))
+
+	assert.Check(t, is.Equal(lists[2].Name, "legacy"))
+	assert.Check(t, is.Equal(lists[2].Ref, ""))
+	assert.Check(t, !lists[2].Created.IsZero())
+	assert.Check(t, is.DeepEqual(lists[2].Manifests, []types.ImageManifest{legacy}, cmpReferenceNamed))
+}
+
+func TestStoreListEmpty(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "does-not-exist"))
+	lists, err := store.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(lists, 0))
+}
+
+func TestStoreRemoveAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+	assert.NilError(t, store.Save(ref("list1"), ref("manifest"), types.ImageManifest{Ref: sref(t, "abcdef")}))
+	assert.NilError(t, store.Save(ref("list2"), ref("manifest"), types.ImageManifest{Ref: sref(t, "abcdef")}))
+
+	assert.NilError(t, store.RemoveAll())
+	lists, err := store.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(lists, 0))
+}
+
+func TestStoreMetadata(t *testing.T) {
+	store := NewStore(t.TempDir())
+	listRef := ref("example.com/list:1")
+
+	_, err := store.GetMetadata(listRef)
+	assert.Check(t, errdefs.IsNotFound(err))
+
+	assert.NilError(t, store.Save(listRef, ref("first"), types.ImageManifest{Ref: sref(t, "first")}))
+	meta, err := store.GetMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(meta.Ref, "example.com/list:1"))
+	assert.Check(t, !meta.OCI)
+
+	meta.OCI = true
+	meta.ArtifactType = "application/vnd.example.artifact"
+	meta.Annotations = map[string]string{"org.opencontainers.image.version": "1.0"}
+	assert.NilError(t, store.SaveMetadata(listRef, meta))
+
+	actual, err := store.GetMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(actual, meta))
+
+	// saving a manifest must not reset the metadata
+	assert.NilError(t, store.Save(listRef, ref("second"), types.ImageManifest{Ref: sref(t, "second")}))
+	actual, err = store.GetMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(actual, meta))
+}
+
+// Older versions of the CLI read all files in the directory of a manifest
+// list as manifests, and remove the directory without the metadata.
+func TestStoreMetadataCompatibility(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+	listRef := ref("example.com/list:1")
+	assert.NilError(t, store.Save(listRef, ref("first"), types.ImageManifest{Ref: sref(t, "first")}))
+	assert.NilError(t, store.SaveMetadata(listRef, ListMetadata{OCI: true}))
+
+	entries, err := os.ReadDir(filepath.Join(tmpDir, "example.com_list-1"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(entries, 1))
+	assert.Check(t, is.Equal(entries[0].Name(), "first"))
+
+	// remove the list without its metadata.
+	assert.NilError(t, os.RemoveAll(filepath.Join(tmpDir, "example.com_list-1")))
+	_, err = store.GetMetadata(listRef)
+	assert.Check(t, errdefs.IsNotFound(err))
+
+	assert.NilError(t, store.Save(listRef, ref("second"), types.ImageManifest{Ref: sref(t, "second")}))
+	meta, err := store.GetMetadata(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, !meta.OCI)
+}
@@ -1,54 +1,237 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
 	"strings"
 
+	"github.com/docker/cli/cli"
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
+	"github.com/google/shlex"
+	"github.com/moby/moby/client"
 	"github.com/spf13/cobra"
 )
 
 const (
 	keyBuilderAlias = "builder"
+
+	// shellAliasPrefix is the prefix of aliases that run a shell command
+	// instead of a docker command.
+	shellAliasPrefix = "!"
 )
 
-var allowedAliases = map[string]struct{}{
-	keyBuilderAlias: {},
+// reservedAliases are names that cannot be used for user-defined aliases,
+// in addition to the names of built-in commands. These commands are added
+// by cobra when executing the root command, so are not found by cmd.Find().
+var reservedAliases = []string{
+	keyBuilderAlias,
+	"help",
+	"completion",
+	cobra.ShellCompRequestCmd,
+	cobra.ShellCompNoDescRequestCmd,
 }
 
+// aliasDescriptionEscaper escapes tabs and newlines in the description of
+// aliases, as they would break the formatting of the help output and of
+// completion results.
+var aliasDescriptionEscaper = strings.NewReplacer("\t", `\t`, "\n", `\n`)
+
+// shellArgsRe matches references to positional parameters in a shell alias
+// ("$1", "${2}", "$@", "$*", "$#").
+var shellArgsRe = regexp.MustCompile(`\$(\{?[0-9@*#])`)
+
 func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []string) ([]string, []string, []string, error) {
 	var err error
 	var envs []string
 	aliasMap := dockerCli.ConfigFile().Aliases
-	aliases := make([][2][]string, 0, len(aliasMap))
 
-	for k, v := range aliasMap {
-		if _, ok := allowedAliases[k]; !ok {
-			return args, osArgs, envs, fmt.Errorf("not allowed to alias %q (allowed: %#v)", k, allowedAliases)
-		}
+	var builderAlias []string
+	if v, ok := aliasMap[keyBuilderAlias]; ok {
 		if c, _, err := cmd.Find(strings.Split(v, " ")); err == nil {
 			if !pluginmanager.IsPluginCommand(c) {
 				return args, osArgs, envs, fmt.Errorf("not allowed to alias with builtin %q as target", v)
 			}
 		}
-		aliases = append(aliases, [2][]string{{k}, {v}})
+		builderAlias = []string{v}
+	}
+
+	// During completion, the command to complete follows the "__complete"
+	// argument. The last argument is the one being completed, so it's not
+	// expanded.
+	offset := 0
+	if len(args) > 2 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		offset = 1
+	}
+	expanded, script, err := expandAlias(cmd, aliasMap, args[offset:])
+	if err != nil {
+		return args, osArgs, envs, err
+	}
+	if script == "" && len(args) > offset {
+		// The alias' arguments remain at the end of the expanded arguments.
+		replace := expanded[:len(expanded)-len(args)+offset+1]
+		// Global flags precede the command, so args are at the end of
+		// osArgs. Replace the alias by its position, as a flag value can
+		// be equal to the alias' name.
+		if i := len(osArgs) - len(args) + offset; i >= 0 && osArgs[i] == args[offset] {
+			osArgs = slices.Concat(osArgs[:i], replace, osArgs[i+1:])
+		}
+		args = append(slices.Clone(args[:offset]), expanded...)
 	}
 
-	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, os.Args)
+	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, osArgs)
 	if err != nil {
-		return args, os.Args, envs, err
+		return args, osArgs, envs, err
 	}
 
-	for _, al := range aliases {
-		var didChange bool
-		args, didChange = stringSliceReplaceAt(args, al[0], al[1], 0)
-		if didChange {
-			osArgs, _ = stringSliceReplaceAt(osArgs, al[0], al[1], -1)
-			break
+	if builderAlias != nil {
+		if fwargs, didChange := stringSliceReplaceAt(args, []string{keyBuilderAlias}, builderAlias, 0); didChange {
+			args = fwargs
+			osArgs, _ = stringSliceReplaceAt(osArgs, []string{keyBuilderAlias}, builderAlias, -1)
 		}
 	}
 
 	return args, osArgs, envs, nil
 }
+
+// expandAlias expands the user-defined alias in args[0], if any, including
+// aliases that expand to other aliases. The alias is split into arguments
+// using shell quoting rules. Aliases that conflict with built-in commands are
+// ignored. If args[0] refers to a shell alias, it returns the shell command to
+// run, and args[0] is the name of the shell alias.
+func expandAlias(rootCmd *cobra.Command, aliases map[string]string, args []string) ([]string, string, error) {
+	var seen []string
+	for len(args) > 0 {
+		name := args[0]
+		value, ok := aliases[name]
+		if !ok || !isAliasAllowed(rootCmd, name) {
+			return args, "", nil
+		}
+		if slices.Contains(seen, name) {
+			return nil, "", fmt.Errorf("alias loop detected: %s", strings.Join(append(seen, name), " -> "))
+		}
+		seen = append(seen, name)
+
+		if script, ok := strings.CutPrefix(value, shellAliasPrefix); ok {
+			if strings.TrimSpace(script) == "" {
+				return nil, "", fmt.Errorf("invalid alias %q: command cannot be empty", name)
+			}
+			return args, script, nil
+		}
+		fields, err := shlex.Split(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid alias %q: %w", name, err)
+		}
+		if len(fields) == 0 {
+			return nil, "", fmt.Errorf("invalid alias %q: command cannot be empty", name)
+		}
+		args = append(fields, args[1:]...)
+		if fields[0] == name {
+			// An alias that expands to its own name refers to the CLI plugin
+			// with that name; for example, to set default options.
+			return args, "", nil
+		}
+	}
+	return args, "", nil
+}
+
+// isAliasAllowed returns whether name can be used as a user-defined alias.
+// Aliases cannot override built-in commands, but take precedence over CLI
+// plugins with the same name.
+func isAliasAllowed(rootCmd *cobra.Command, name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\r\n") || slices.Contains(reservedAliases, name) {
+		return false
+	}
+	c, _, err := rootCmd.Find([]string{name})
+	return err != nil || c == rootCmd || pluginmanager.IsPluginCommand(c) || cli.IsAliasCommand(c)
+}
+
+// runShellAlias runs args[0] if it's a shell alias ("!command"). It returns
+// false if args[0] is not a shell alias.
+func runShellAlias(dockerCli command.Cli, rootCmd *cobra.Command, args []string) (bool, error) {
+	args, script, err := expandAlias(rootCmd, dockerCli.ConfigFile().Aliases, args)
+	if err != nil {
+		return true, err
+	}
+	if script == "" {
+		return false, nil
+	}
+
+	c := shellAliasCommand(script, args)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), shellAliasEnv(dockerCli)...)
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return true, cli.StatusError{StatusCode: exitErr.ExitCode()}
+		}
+		return true, fmt.Errorf("failed to run alias %q: %w", args[0], err)
+	}
+	return true, nil
+}
+
+// shellAliasCommand returns the command to run the shell alias args[0].
+//
+// The shell command is run using "sh -c", with the alias' arguments as
+// positional parameters. The arguments are appended to the command, unless it
+// refers to them ("$1", "$@"). On Windows, it's run using "cmd /C", which has
+// no positional parameters, so the arguments are always appended.
+func shellAliasCommand(script string, args []string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", append([]string{"/C", script}, args[1:]...)...)
+	}
+	if !shellArgsRe.MatchString(script) {
+		script += ` "$@"`
+	}
+	return exec.Command("sh", append([]string{"-c", script}, args...)...)
+}
+
+// shellAliasEnv returns the environment variables to connect docker commands
+// in a shell alias to the same daemon as the docker command that invoked it.
+func shellAliasEnv(dockerCli command.Cli) []string {
+	if os.Getenv(client.EnvOverrideHost) != "" {
+		return nil
+	}
+	if name := dockerCli.CurrentContext(); name != command.DefaultContextName {
+		return []string{command.EnvOverrideContext + "=" + name}
+	}
+	if host := dockerCli.DockerEndpoint().Host; host != "" {
+		return []string{client.EnvOverrideHost + "=" + host}
+	}
+	return nil
+}
+
+// addAliasCommandStubs adds a command to the root command for each
+// user-defined alias, to include them in "docker --help" and in shell
+// completion.
+func addAliasCommandStubs(dockerCli command.Cli, rootCmd *cobra.Command) {
+	for name, value := range dockerCli.ConfigFile().Aliases {
+		if !isAliasAllowed(rootCmd, name) {
+			continue
+		}
+		if c, _, err := rootCmd.Find([]string{name}); err == nil && c != rootCmd {
+			if cli.IsAliasCommand(c) {
+				continue
+			}
+			// aliases take precedence over plugins
+			rootCmd.RemoveCommand(c)
+		}
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              aliasDescriptionEscaper.Replace(value),
+			Run:                func(*cobra.Command, []string) {},
+			Annotations:        map[string]string{cli.CommandAnnotationAlias: value},
+			DisableFlagParsing: true,
+		})
+	}
+}
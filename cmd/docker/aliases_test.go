@@ -0,0 +1,205 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.25
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/metadata"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newAliasTestRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "docker"}
+	rootCmd.AddCommand(
+		&cobra.Command{Use: "ps", Run: func(*cobra.Command, []string) {}},
+		&cobra.Command{Use: "image", Aliases: []string{"images"}, Run: func(*cobra.Command, []string) {}},
+		&cobra.Command{Use: "compose", Annotations: map[string]string{metadata.CommandAnnotationPlugin: "true"}, Run: func(*cobra.Command, []string) {}},
+	)
+	return rootCmd
+}
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"lsa":      `ps -a --format 'table {{.Names}}\t{{.Status}}'`,
+		"lsa2":     "lsa --no-trunc",
+		"ps":       "ps -a",
+		"images":   "image ls",
+		"compose":  "compose --progress=plain",
+		"greet":    "!echo hello $1",
+		"greet2":   "greet world",
+		"loop1":    "loop2 x",
+		"loop2":    "loop1",
+		"self":     "self -a",
+		"indirect": "self2",
+		"self2":    "self2 -a",
+		"empty":    "",
+		"invalid":  `ps --format "{{.ID}}`,
+	}
+	for _, tc := range []struct {
+		args           []string
+		expected       []string
+		expectedScript string
+		expectedErr    string
+	}{
+		{args: []string{}, expected: []string{}},
+		{args: []string{"lsa", "-q"}, expected: []string{"ps", "-a", "--format", `table {{.Names}}\t{{.Status}}`, "-q"}},
+		{args: []string{"lsa2"}, expected: []string{"ps", "-a", "--format", `table {{.Names}}\t{{.Status}}`, "--no-trunc"}},
+		{args: []string{"run", "lsa"}, expected: []string{"run", "lsa"}},
+		{args: []string{"ps"}, expected: []string{"ps"}},
+		{args: []string{"images"}, expected: []string{"images"}},
+		{args: []string{"compose", "up"}, expected: []string{"compose", "--progress=plain", "up"}},
+		{args: []string{"greet", "a"}, expected: []string{"greet", "a"}, expectedScript: "echo hello $1"},
+		{args: []string{"greet2", "a"}, expected: []string{"greet", "world", "a"}, expectedScript: "echo hello $1"},
+		{args: []string{"loop1"}, expectedErr: "alias loop detected: loop1 -> loop2 -> loop1"},
+		{args: []string{"self"}, expected: []string{"self", "-a"}},
+		{args: []string{"indirect", "x"}, expected: []string{"self2", "-a", "x"}},
+		{args: []string{"empty"}, expectedErr: `invalid alias "empty": command cannot be empty`},
+		{args: []string{"invalid"}, expectedErr: `invalid alias "invalid": EOF found when expecting closing quote`},
+	} {
+		args, script, err := expandAlias(newAliasTestRootCmd(), aliases, tc.args)
+		if tc.expectedErr != "" {
+			assert.Check(t, is.Error(err, tc.expectedErr))
+			continue
+		}
+		assert.Check(t, is.Nil(err))
+		assert.Check(t, is.DeepEqual(args, tc.expected))
+		assert.Check(t, is.Equal(script, tc.expectedScript))
+	}
+}
+
+func TestProcessAliases(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		args           []string
+		osArgs         []string
+		expectedArgs   []string
+		expectedOSArgs []string
+	}{
+		{
+			name:           "alias",
+			args:           []string{"lsa", "-q"},
+			osArgs:         []string{"docker", "--context", "lsa", "lsa", "-q"},
+			expectedArgs:   []string{"ps", "-a", "-q"},
+			expectedOSArgs: []string{"docker", "--context", "lsa", "ps", "-a", "-q"},
+		},
+		{
+			name:           "no alias",
+			args:           []string{"ps", "lsa"},
+			osArgs:         []string{"docker", "ps", "lsa"},
+			expectedArgs:   []string{"ps", "lsa"},
+			expectedOSArgs: []string{"docker", "ps", "lsa"},
+		},
+		{
+			name:           "completion",
+			args:           []string{cobra.ShellCompRequestCmd, "lsa", ""},
+			osArgs:         []string{"docker", cobra.ShellCompRequestCmd, "lsa", ""},
+			expectedArgs:   []string{cobra.ShellCompRequestCmd, "ps", "-a", ""},
+			expectedOSArgs: []string{"docker", cobra.ShellCompRequestCmd, "ps", "-a", ""},
+		},
+		{
+			name:           "completion of alias name",
+			args:           []string{cobra.ShellCompRequestCmd, "lsa"},
+			osArgs:         []string{"docker", cobra.ShellCompRequestCmd, "lsa"},
+			expectedArgs:   []string{cobra.ShellCompRequestCmd, "lsa"},
+			expectedOSArgs: []string{"docker", cobra.ShellCompRequestCmd, "lsa"},
+		},
+		{
+			name:           "shell alias",
+			args:           []string{"greet"},
+			osArgs:         []string{"docker", "greet"},
+			expectedArgs:   []string{"greet"},
+			expectedOSArgs: []string{"docker", "greet"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dockerCli := test.NewFakeCli(nil)
+			dockerCli.ConfigFile().Aliases = map[string]string{
+				"lsa":   "ps -a",
+				"greet": "!echo hello",
+			}
+			args, osArgs, envs, err := processAliases(dockerCli, newAliasTestRootCmd(), tc.args, tc.osArgs)
+			assert.NilError(t, err)
+			assert.Check(t, is.DeepEqual(args, tc.expectedArgs))
+			assert.Check(t, is.DeepEqual(osArgs, tc.expectedOSArgs))
+			assert.Check(t, is.Len(envs, 0))
+		})
+	}
+}
+
+func TestRunShellAlias(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	dockerCli := test.NewFakeCli(nil)
+	dockerCli.ConfigFile().Aliases = map[string]string{
+		"positional": `!echo "$1-$2 ($#)" > ` + out,
+		"appended":   `!printf "%s," >> ` + out,
+		"fail":       "!exit 3",
+		"lsa":        "ps -a",
+	}
+
+	ok, err := runShellAlias(dockerCli, newAliasTestRootCmd(), []string{"positional", "a", "b c"})
+	assert.Check(t, ok)
+	assert.NilError(t, err)
+	ok, err = runShellAlias(dockerCli, newAliasTestRootCmd(), []string{"appended", "a", "b c"})
+	assert.Check(t, ok)
+	assert.NilError(t, err)
+	data, err := os.ReadFile(out)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "a-b c (2)\na,b c,"))
+
+	ok, err = runShellAlias(dockerCli, newAliasTestRootCmd(), []string{"fail"})
+	assert.Check(t, ok)
+	assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: 3}))
+
+	ok, err = runShellAlias(dockerCli, newAliasTestRootCmd(), []string{"lsa"})
+	assert.Check(t, !ok)
+	assert.NilError(t, err)
+}
+
+func TestShellAliasCommand(t *testing.T) {
+	c := shellAliasCommand("echo $1", []string{"greet", "a"})
+	if runtime.GOOS == "windows" {
+		assert.Check(t, is.DeepEqual(c.Args, []string{"cmd", "/C", "echo $1", "a"}))
+		return
+	}
+	assert.Check(t, is.DeepEqual(c.Args, []string{"sh", "-c", "echo $1", "greet", "a"}))
+
+	c = shellAliasCommand("echo", []string{"greet", "a"})
+	assert.Check(t, is.DeepEqual(c.Args, []string{"sh", "-c", `echo "$@"`, "greet", "a"}))
+}
+
+func TestAddAliasCommandStubs(t *testing.T) {
+	dockerCli := test.NewFakeCli(nil)
+	dockerCli.ConfigFile().Aliases = map[string]string{
+		"lsa":     "ps -a --format \"table {{.Names}}\t{{.Status}}\"",
+		"ps":      "ps -a",
+		"builder": "buildx",
+		"compose": "compose --progress=plain",
+		"-x":      "ps",
+	}
+	rootCmd := newAliasTestRootCmd()
+	addAliasCommandStubs(dockerCli, rootCmd)
+	addAliasCommandStubs(dockerCli, rootCmd)
+
+	var aliases []string
+	for _, c := range rootCmd.Commands() {
+		if cli.IsAliasCommand(c) {
+			aliases = append(aliases, c.Name()+": "+c.Short)
+		}
+	}
+	assert.Check(t, is.DeepEqual(aliases, []string{
+		"compose: compose --progress=plain",
+		`lsa: ps -a --format "table {{.Names}}\t{{.Status}}"`,
+	}))
+}
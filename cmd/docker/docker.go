@@ -24,6 +24,7 @@
 	cliflags "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/cli/version"
 	platformsignals "github.com/docker/cli/cmd/docker/internal/signals"
+	"github.com/moby/moby/client"
 	"github.com/moby/moby/client/pkg/versions"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -152,6 +153,9 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 			return fmt.Errorf("docker: unknown command: docker %s\n\nRun 'docker --help' for more information", args[0])
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyProjectDefaults(cmd, dockerCli.ProjectContext()); err != nil {
+				return err
+			}
 			return isSupported(cmd, dockerCli)
 		},
 		Version:               fmt.Sprintf("%s, build %s", version.Version, version.GitCommit),
@@ -250,6 +254,7 @@ func setHelpFunc(dockerCli command.Cli, cmd *cobra.Command) {
 			ccmd.Println(err)
 			return
 		}
+		addAliasCommandStubs(dockerCli, ccmd.Root())
 
 		if len(args) >= 1 {
 			err := tryRunPluginHelp(dockerCli, ccmd, args)
@@ -483,6 +488,12 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 	if err := tcmd.Initialize(command.WithEnableGlobalMeterProvider(), command.WithEnableGlobalTracerProvider()); err != nil {
 		return err
 	}
+	if pc := dockerCli.ProjectContext(); pc != nil && pc.Platform != "" && os.Getenv(command.EnvDefaultPlatform) == "" {
+		// Set the project's default platform in the environment, so that
+		// it's used as default for "--platform" flags, and inherited by
+		// CLI plugins.
+		_ = os.Setenv(command.EnvDefaultPlatform, pc.Platform)
+	}
 
 	mp := dockerCli.MeterProvider()
 	if mp, ok := mp.(command.MeterProvider); ok {
@@ -497,6 +508,10 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 
 	dockerCli.InstrumentCobraCommands(ctx, cmd)
 
+	if ok, err := runShellAlias(dockerCli, cmd, args); ok {
+		return err
+	}
+
 	var envs []string
 	args, os.Args, envs, err = processAliases(dockerCli, cmd, args, os.Args)
 	if err != nil {
@@ -510,6 +525,7 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 		if err := pluginmanager.AddPluginCommandStubs(dockerCli, cmd); err != nil {
 			return err
 		}
+		addAliasCommandStubs(dockerCli, cmd)
 	}
 
 	var subCommand *cobra.Command
@@ -729,6 +745,41 @@ func areSubcommandsSupported(cmd *cobra.Command, details versionDetails) error {
 	return nil
 }
 
+// applyProjectDefaults applies the defaults of the project's .docker-context
+// file and of the current context to the command's flags that are not set on
+// the command-line.
+//
+// The project's labels are added as "label" filters to filter options of
+// commands that list objects, unless "label" filters are set on the
+// command-line.
+func applyProjectDefaults(cmd *cobra.Command, pc *command.ProjectContext) error {
+	var errs []error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[command.FlagAnnotationDefaultPlatform]; ok && !f.Changed && f.Value.String() == "" {
+			// The flag's default is set from DOCKER_DEFAULT_PLATFORM when the
+			// flag is defined, which is before the default platform of the
+			// project or the context is set in the environment.
+			if platform := os.Getenv(command.EnvDefaultPlatform); platform != "" {
+				errs = append(errs, f.Value.Set(platform))
+			}
+		}
+		if _, ok := f.Annotations[command.FlagAnnotationLabelFilter]; ok && pc != nil && !hasLabelFilter(f) {
+			for _, l := range pc.Labels {
+				errs = append(errs, f.Value.Set("label="+l))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func hasLabelFilter(f *pflag.Flag) bool {
+	if v, ok := f.Value.(interface{ Value() client.Filters }); ok {
+		_, found := v.Value()["label"]
+		return found
+	}
+	return false
+}
+
 func getFlagAnnotation(f *pflag.Flag, annotation string) string {
 	if value, ok := f.Annotations[annotation]; ok && len(value) == 1 {
 		return value[0]
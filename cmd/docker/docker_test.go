@@ -16,6 +16,7 @@
 	"github.com/docker/cli/cli/command/commands"
 	"github.com/docker/cli/cli/debug"
 	platformsignals "github.com/docker/cli/cmd/docker/internal/signals"
+	"github.com/docker/cli/opts"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
@@ -205,3 +206,41 @@ func TestVisitAll(t *testing.T) {
 	expected := []string{"sub1sub1", "sub1sub2", "sub1", "sub2", "root"}
 	assert.DeepEqual(t, expected, visited)
 }
+
+func TestApplyProjectDefaults(t *testing.T) {
+	t.Setenv(command.EnvDefaultPlatform, "linux/arm64")
+	newCmd := func() (*cobra.Command, *opts.FilterOpt) {
+		filter := opts.NewFilterOpt()
+		cmd := &cobra.Command{Use: "ls", Run: func(*cobra.Command, []string) {}}
+		flags := cmd.Flags()
+		flags.String("platform", "", "")
+		_ = flags.SetAnnotation("platform", command.FlagAnnotationDefaultPlatform, nil)
+		flags.Var(&filter, "filter", "")
+		_ = flags.SetAnnotation("filter", command.FlagAnnotationLabelFilter, nil)
+		return cmd, &filter
+	}
+	pc := &command.ProjectContext{Platform: "linux/arm64", Labels: []string{"com.example.project=web"}}
+
+	cmd, filter := newCmd()
+	assert.NilError(t, cmd.ParseFlags([]string{"--filter", "status=running"}))
+	assert.NilError(t, applyProjectDefaults(cmd, pc))
+	platform, _ := cmd.Flags().GetString("platform")
+	assert.Check(t, is.Equal(platform, "linux/arm64"))
+	assert.Check(t, is.DeepEqual(filter.Value()["label"], map[string]bool{"com.example.project=web": true}))
+
+	// options on the command-line take precedence
+	cmd, filter = newCmd()
+	assert.NilError(t, cmd.ParseFlags([]string{"--platform", "linux/amd64", "--filter", "label=other"}))
+	assert.NilError(t, applyProjectDefaults(cmd, pc))
+	platform, _ = cmd.Flags().GetString("platform")
+	assert.Check(t, is.Equal(platform, "linux/amd64"))
+	assert.Check(t, is.DeepEqual(filter.Value()["label"], map[string]bool{"other": true}))
+
+	// the default platform of the context is applied without a project
+	cmd, filter = newCmd()
+	assert.NilError(t, cmd.ParseFlags(nil))
+	assert.NilError(t, applyProjectDefaults(cmd, nil))
+	platform, _ = cmd.Flags().GetString("platform")
+	assert.Check(t, is.Equal(platform, "linux/arm64"))
+	assert.Check(t, is.Len(filter.Value(), 0))
+}
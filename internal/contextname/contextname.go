@@ -0,0 +1,54 @@
+// Package contextname validates the names of docker contexts. It has no
+// dependencies, so that it can be used by packages that must not depend on
+// the context store, such as the configuration file.
+package contextname
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validNameFormat is used as part of errors for invalid context-names.
+// We should consider making this less technical ("must start with "a-z",
+// and only consist of alphanumeric characters and separators").
+const validNameFormat = `^[a-zA-Z0-9][a-zA-Z0-9_.+-]+$`
+
+// Validate checks a context name is valid.
+func Validate(name string) error {
+	if name == "" {
+		return errors.New("context name cannot be empty")
+	}
+	if name == "default" {
+		return errors.New(`"default" is a reserved context name`)
+	}
+	if !isValidName(name) {
+		return fmt.Errorf("context name %q is invalid, names are validated against regexp %q", name, validNameFormat)
+	}
+	return nil
+}
+
+// isValidName checks if the context-name is valid ("^[a-zA-Z0-9][a-zA-Z0-9_.+-]+$").
+//
+// Names must start with an alphanumeric character (a-zA-Z0-9), followed by
+// alphanumeric or separators ("_", ".", "+", "-").
+func isValidName(s string) bool {
+	if len(s) < 2 || !isAlphaNum(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if isAlphaNum(c) || c == '_' || c == '.' || c == '+' || c == '-' {
+			continue
+		}
+		return false
+	}
+
+	return true
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
@@ -0,0 +1,29 @@
+package contextname
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	names := map[string]string{
+		"validname":            "",
+		"valid-name_1.0+x":     "",
+		"":                     "context name cannot be empty",
+		"default":              `"default" is a reserved context name`,
+		"a":                    "is invalid",
+		"-invalid":             "is invalid",
+		"../../invalid/escape": "is invalid",
+		`\invalid\windows`:     "is invalid",
+	}
+	for name, expectedErr := range names {
+		err := Validate(name)
+		if expectedErr == "" {
+			assert.Check(t, err, "%q should be valid", name)
+		} else {
+			assert.Check(t, is.ErrorContains(err, expectedErr), "%q should be invalid", name)
+		}
+	}
+}
@@ -21,6 +21,7 @@
 type OAuthAPI interface {
 	GetDeviceCode(ctx context.Context, audience string) (State, error)
 	WaitForDeviceToken(ctx context.Context, state State) (TokenResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (TokenResponse, error)
 	RevokeToken(ctx context.Context, refreshToken string) error
 	GetAutoPAT(ctx context.Context, audience string, res TokenResponse) (string, error)
 }
@@ -187,6 +188,41 @@ func (a API) getDeviceToken(ctx context.Context, state State) (TokenResponse, er
 	return res, nil
 }
 
+// Refresh exchanges a refresh token with the tenant for a new access token.
+// Depending on the tenant configuration, the response may also contain a new
+// (rotated) refresh token.
+func (a API) Refresh(ctx context.Context, refreshToken string) (TokenResponse, error) {
+	data := url.Values{
+		"client_id":     {a.ClientID},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	oauthTokenURL := a.TenantURL + "/oauth/token"
+
+	resp, err := postForm(ctx, oauthTokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to refresh tokens: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return TokenResponse{}, tryDecodeOAuthError(resp)
+	}
+
+	var res TokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return res, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if res.AccessToken == "" {
+		return res, errors.New("failed to refresh tokens: no access token in response")
+	}
+
+	return res, nil
+}
+
 // RevokeToken revokes a refresh token with the tenant so that it can no longer
 // be used to get new tokens.
 func (a API) RevokeToken(ctx context.Context, refreshToken string) error {
@@ -241,6 +241,77 @@ func TestWaitForDeviceToken(t *testing.T) {
 	})
 }
 
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/oauth/token", r.URL.Path)
+			assert.Equal(t, r.FormValue("client_id"), "aClientID")
+			assert.Equal(t, r.FormValue("grant_type"), "refresh_token")
+			assert.Equal(t, r.FormValue("refresh_token"), "v1.a-refresh-token")
+
+			jsonState, err := json.Marshal(TokenResponse{
+				AccessToken:  "a-new-access-token",
+				RefreshToken: "v1.a-new-refresh-token",
+				Scope:        "openid offline_access",
+				ExpiresIn:    3600,
+				TokenType:    "Bearer",
+			})
+			assert.NilError(t, err)
+			_, _ = w.Write(jsonState)
+		}))
+		defer ts.Close()
+		api := API{
+			TenantURL: ts.URL,
+			ClientID:  "aClientID",
+		}
+
+		res, err := api.Refresh(context.Background(), "v1.a-refresh-token")
+		assert.NilError(t, err)
+		assert.Equal(t, res.AccessToken, "a-new-access-token")
+		assert.Equal(t, res.RefreshToken, "v1.a-new-refresh-token")
+	})
+
+	t.Run("error w/ description", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			jsonState, err := json.Marshal(TokenResponse{
+				ErrorDescription: "Unknown or invalid refresh token.",
+			})
+			assert.NilError(t, err)
+
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write(jsonState)
+		}))
+		defer ts.Close()
+		api := API{
+			TenantURL: ts.URL,
+			ClientID:  "aClientID",
+		}
+
+		_, err := api.Refresh(context.Background(), "v1.a-refresh-token")
+		assert.ErrorContains(t, err, "Unknown or invalid refresh token.")
+	})
+
+	t.Run("missing access token", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer ts.Close()
+		api := API{
+			TenantURL: ts.URL,
+			ClientID:  "aClientID",
+		}
+
+		_, err := api.Refresh(context.Background(), "v1.a-refresh-token")
+		assert.ErrorContains(t, err, "no access token in response")
+	})
+}
+
 func TestRevoke(t *testing.T) {
 	t.Parallel()
 
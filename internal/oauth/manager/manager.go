@@ -8,6 +8,7 @@
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/types"
@@ -44,6 +45,8 @@ type OAuthManagerOptions struct {
 	OpenBrowser func(string) error
 }
 
+// New creates a new OAuthManager. The tenant is connected to over https,
+// unless options.Tenant is a URL that includes a scheme.
 func New(options OAuthManagerOptions) *OAuthManager {
 	scopes := []string{"openid", "offline_access"}
 	if len(options.Scopes) > 0 {
@@ -59,13 +62,18 @@ func New(options OAuthManagerOptions) *OAuthManager {
 		openBrowser = browser.OpenURL
 	}
 
+	tenantURL := options.Tenant
+	if !strings.Contains(tenantURL, "://") {
+		tenantURL = "https://" + tenantURL
+	}
+
 	return &OAuthManager{
 		clientID: options.ClientID,
 		audience: options.Audience,
 		tenant:   options.Tenant,
 		store:    options.Store,
 		api: api.API{
-			TenantURL: "https://" + options.Tenant,
+			TenantURL: tenantURL,
 			ClientID:  options.ClientID,
 			Scopes:    scopes,
 		},
@@ -186,11 +194,134 @@ func (m *OAuthManager) Logout(ctx context.Context) error {
 	return nil
 }
 
+// refreshThreshold is the remaining lifetime of the access token below which
+// RefreshTokens refreshes the stored tokens.
+const refreshThreshold = 5 * time.Minute
+
+// ErrNoTokens is returned by Status if no tokens obtained through the device
+// authentication flow are stored.
+var ErrNoTokens = errors.New("no OAuth tokens found")
+
+// TokenStatus describes the access token that is stored in the credentials
+// store.
+type TokenStatus struct {
+	Username string
+	Email    string
+	Expiry   time.Time
+	Scopes   []string
+}
+
+// Status returns information about the access token that is stored in the
+// credentials store. It returns ErrNoTokens if no access token is stored.
+func (m *OAuthManager) Status() (TokenStatus, error) {
+	accessConfig, err := m.store.Get(accessTokenKey)
+	if err != nil {
+		return TokenStatus{}, err
+	}
+	if accessConfig.Password == "" {
+		return TokenStatus{}, ErrNoTokens
+	}
+	claims, err := oauth.GetClaims(accessConfig.Password)
+	if err != nil {
+		return TokenStatus{}, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+	status := TokenStatus{
+		Username: claims.Domain.Username,
+		Email:    claims.Domain.Email,
+		Scopes:   strings.Fields(claims.Scope),
+	}
+	if claims.Expiry != nil {
+		status.Expiry = claims.Expiry.Time()
+	}
+	return status, nil
+}
+
+// RefreshTokens uses the stored refresh token to obtain new tokens from the
+// tenant if the stored access token expires within the refresh threshold.
+// The new tokens are stored in the credentials store. The PAT that was created
+// at login is kept as Hub credentials; a new PAT is only created if no Hub
+// credentials are stored.
+//
+// RefreshTokens returns false without an error if there is nothing to refresh;
+// no tokens are stored, the tokens weren't stored by the CLI, the access token
+// is still valid, or the Hub credentials belong to a different account.
+func (m *OAuthManager) RefreshTokens(ctx context.Context) (bool, error) {
+	status, err := m.Status()
+	if err != nil {
+		if errors.Is(err, ErrNoTokens) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !status.Expiry.IsZero() && time.Until(status.Expiry) > refreshThreshold {
+		return false, nil
+	}
+
+	refreshConfig, err := m.store.Get(refreshTokenKey)
+	if err != nil {
+		return false, err
+	}
+	refreshToken, _, ok := strings.Cut(refreshConfig.Password, "..")
+	if !ok || refreshToken == "" {
+		// the token wasn't stored by the CLI, so don't use it
+		return false, nil
+	}
+
+	hubConfig, err := m.store.Get(registry.IndexServer)
+	if err != nil {
+		return false, err
+	}
+	if hubConfig.Username != "" && hubConfig.Username != status.Username {
+		logrus.Debugf("not refreshing tokens: credentials for %s belong to a different account", registry.IndexServer)
+		return false, nil
+	}
+
+	tokenRes, err := m.api.Refresh(ctx, refreshToken)
+	if err != nil {
+		return false, fmt.Errorf("failed to refresh tokens: %w", err)
+	}
+	if tokenRes.RefreshToken == "" {
+		// the tenant does not rotate refresh tokens; keep using the current one.
+		tokenRes.RefreshToken = refreshToken
+	}
+
+	claims, err := oauth.GetClaims(tokenRes.AccessToken)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+	if err := m.storeTokensInStore(tokenRes, claims.Domain.Username); err != nil {
+		return false, fmt.Errorf("failed to store tokens: %w", err)
+	}
+	if hubConfig.Password != "" {
+		return true, nil
+	}
+
+	pat, err := m.api.GetAutoPAT(ctx, m.audience, tokenRes)
+	if err != nil {
+		return false, err
+	}
+	if err := m.store.Store(types.AuthConfig{
+		Username:      claims.Domain.Username,
+		Password:      pat,
+		ServerAddress: registry.IndexServer,
+	}); err != nil {
+		return false, fmt.Errorf("failed to store credentials: %w", err)
+	}
+	return true, nil
+}
+
 const (
 	accessTokenKey  = registry.IndexServer + "access-token"
 	refreshTokenKey = registry.IndexServer + "refresh-token"
 )
 
+// IsTokenKey returns whether serverAddress is one of the keys under which
+// the OAuth tokens of Docker Hub are stored in the credential store. These
+// are not registries.
+func IsTokenKey(serverAddress string) bool {
+	return serverAddress == accessTokenKey || serverAddress == refreshTokenKey
+}
+
 func (m *OAuthManager) storeTokensInStore(tokens api.TokenResponse, username string) error {
 	return errors.Join(
 		m.store.Store(types.AuthConfig{
@@ -9,8 +9,12 @@
 
 	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/oauth"
 	"github.com/docker/cli/internal/oauth/api"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 const (
@@ -297,6 +301,229 @@ func TestLogout(t *testing.T) {
 	})
 }
 
+func TestStatus(t *testing.T) {
+	t.Run("no tokens", func(t *testing.T) {
+		manager := OAuthManager{
+			store: credentials.NewFileStore(newStore(map[string]types.AuthConfig{})),
+		}
+		_, err := manager.Status()
+		assert.Check(t, is.ErrorIs(err, ErrNoTokens))
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		manager := OAuthManager{
+			store: credentials.NewFileStore(newStore(map[string]types.AuthConfig{
+				"https://index.docker.io/v1/access-token": {
+					Password: validToken,
+				},
+			})),
+		}
+		status, err := manager.Status()
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(status, TokenStatus{
+			Username: "bork!",
+			Email:    "bork@docker.com",
+			Expiry:   time.Unix(1719506539, 0),
+			Scopes:   []string{"openid", "offline_access"},
+		}))
+	})
+}
+
+func TestRefreshTokens(t *testing.T) {
+	t.Run("expiring token", func(t *testing.T) {
+		newToken := newTestToken(t, "bork!", time.Now().Add(time.Hour))
+		var receivedRefreshToken, receivedAccessToken string
+		a := &testAPI{
+			refresh: func(token string) (api.TokenResponse, error) {
+				receivedRefreshToken = token
+				return api.TokenResponse{
+					AccessToken:  newToken,
+					RefreshToken: "a-new-refresh-token",
+				}, nil
+			},
+			getAutoPAT: func(_ string, res api.TokenResponse) (string, error) {
+				receivedAccessToken = res.AccessToken
+				return "a-new-pat", nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"https://index.docker.io/v1/": {
+				Username: "bork!",
+				Password: "an-old-pat",
+			},
+			"https://index.docker.io/v1/access-token": {
+				Username: "bork!",
+				Password: newTestToken(t, "bork!", time.Now().Add(time.Minute)),
+			},
+			"https://index.docker.io/v1/refresh-token": {
+				Username: "bork!",
+				Password: "a-refresh-token..client-id",
+			},
+		})
+		manager := OAuthManager{
+			store:    credentials.NewFileStore(store),
+			clientID: "client-id",
+			api:      a,
+		}
+
+		refreshed, err := manager.RefreshTokens(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, refreshed)
+		assert.Check(t, is.Equal(receivedRefreshToken, "a-refresh-token"))
+		// the PAT that was created at login is kept.
+		assert.Check(t, is.Equal(receivedAccessToken, ""))
+		assert.Check(t, is.Equal(store.configs["https://index.docker.io/v1/"].Password, "an-old-pat"))
+		assert.Check(t, is.Equal(store.configs["https://index.docker.io/v1/access-token"].Password, newToken))
+		assert.Check(t, is.Equal(store.configs["https://index.docker.io/v1/refresh-token"].Password, "a-new-refresh-token..client-id"))
+	})
+
+	t.Run("no stored PAT", func(t *testing.T) {
+		newToken := newTestToken(t, "bork!", time.Now().Add(time.Hour))
+		var receivedAccessToken string
+		a := &testAPI{
+			refresh: func(string) (api.TokenResponse, error) {
+				return api.TokenResponse{AccessToken: newToken}, nil
+			},
+			getAutoPAT: func(_ string, res api.TokenResponse) (string, error) {
+				receivedAccessToken = res.AccessToken
+				return "a-new-pat", nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"https://index.docker.io/v1/access-token": {
+				Username: "bork!",
+				Password: newTestToken(t, "bork!", time.Now().Add(-time.Minute)),
+			},
+			"https://index.docker.io/v1/refresh-token": {
+				Username: "bork!",
+				Password: "a-refresh-token..client-id",
+			},
+		})
+		manager := OAuthManager{
+			store:    credentials.NewFileStore(store),
+			clientID: "client-id",
+			api:      a,
+		}
+
+		refreshed, err := manager.RefreshTokens(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, refreshed)
+		assert.Check(t, is.Equal(receivedAccessToken, newToken))
+		assert.Check(t, is.Equal(store.configs["https://index.docker.io/v1/"].Password, "a-new-pat"))
+		assert.Check(t, is.Equal(store.configs["https://index.docker.io/v1/refresh-token"].Password, "a-refresh-token..client-id"))
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		a := &testAPI{
+			refresh: func(string) (api.TokenResponse, error) {
+				t.Fatal("refresh should not be called")
+				return api.TokenResponse{}, nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"https://index.docker.io/v1/access-token": {
+				Password: newTestToken(t, "bork!", time.Now().Add(time.Hour)),
+			},
+			"https://index.docker.io/v1/refresh-token": {
+				Password: "a-refresh-token..client-id",
+			},
+		})
+		manager := OAuthManager{
+			store: credentials.NewFileStore(store),
+			api:   a,
+		}
+
+		refreshed, err := manager.RefreshTokens(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, !refreshed)
+	})
+
+	t.Run("no tokens", func(t *testing.T) {
+		manager := OAuthManager{
+			store: credentials.NewFileStore(newStore(map[string]types.AuthConfig{})),
+			api:   &testAPI{},
+		}
+
+		refreshed, err := manager.RefreshTokens(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, !refreshed)
+	})
+
+	t.Run("credentials for different account", func(t *testing.T) {
+		a := &testAPI{
+			refresh: func(string) (api.TokenResponse, error) {
+				t.Fatal("refresh should not be called")
+				return api.TokenResponse{}, nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"https://index.docker.io/v1/": {
+				Username: "someone-else",
+				Password: "a-password",
+			},
+			"https://index.docker.io/v1/access-token": {
+				Password: newTestToken(t, "bork!", time.Now().Add(-time.Minute)),
+			},
+			"https://index.docker.io/v1/refresh-token": {
+				Password: "a-refresh-token..client-id",
+			},
+		})
+		manager := OAuthManager{
+			store: credentials.NewFileStore(store),
+			api:   a,
+		}
+
+		refreshed, err := manager.RefreshTokens(context.Background())
+		assert.NilError(t, err)
+		assert.Check(t, !refreshed)
+		assert.Check(t, is.Equal(store.configs["https://index.docker.io/v1/"].Password, "a-password"))
+	})
+
+	t.Run("refresh fails", func(t *testing.T) {
+		a := &testAPI{
+			refresh: func(string) (api.TokenResponse, error) {
+				return api.TokenResponse{}, errors.New("Unknown or invalid refresh token.")
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"https://index.docker.io/v1/access-token": {
+				Password: newTestToken(t, "bork!", time.Now().Add(-time.Minute)),
+			},
+			"https://index.docker.io/v1/refresh-token": {
+				Password: "a-refresh-token..client-id",
+			},
+		})
+		manager := OAuthManager{
+			store: credentials.NewFileStore(store),
+			api:   a,
+		}
+
+		refreshed, err := manager.RefreshTokens(context.Background())
+		assert.Check(t, is.ErrorContains(err, "failed to refresh tokens: Unknown or invalid refresh token."))
+		assert.Check(t, !refreshed)
+	})
+}
+
+// newTestToken returns a signed access token for the given user that
+// expires at the given time.
+func newTestToken(t *testing.T, username string, expiry time.Time) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("a-signing-key-of-sufficient-length")}, nil)
+	assert.NilError(t, err)
+	token, err := jwt.Signed(signer).Claims(oauth.Claims{
+		Claims: jwt.Claims{
+			Expiry: jwt.NewNumericDate(expiry),
+		},
+		Domain: oauth.DomainClaims{
+			Username: username,
+			Email:    username + "@docker.com",
+		},
+		Scope: "openid offline_access",
+	}).Serialize()
+	assert.NilError(t, err)
+	return token
+}
+
 var _ api.OAuthAPI = &testAPI{}
 
 type testAPI struct {
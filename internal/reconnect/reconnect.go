@@ -0,0 +1,152 @@
+// Package reconnect contains tools for reconnecting long-running streams,
+// such as "docker events" and "docker logs --follow", when the connection to
+// the daemon is lost.
+package reconnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/containerd/errdefs"
+)
+
+const (
+	minDelay = 500 * time.Millisecond
+	maxDelay = 30 * time.Second
+)
+
+// Backoff computes the delay between attempts to reconnect. The delay starts
+// at 500ms, and is doubled for each attempt up to a maximum of 30 seconds.
+// The zero value is ready to use.
+type Backoff struct {
+	attempt int
+}
+
+// Delay returns the delay before the next attempt.
+func (b *Backoff) Delay() time.Duration {
+	d := minDelay << b.attempt
+	if d <= 0 || d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
+
+// Next returns the delay before the next attempt, and increases the delay
+// for the attempt after it.
+func (b *Backoff) Next() time.Duration {
+	d := b.Delay()
+	if d < maxDelay {
+		b.attempt++
+	}
+	return d
+}
+
+// Wait waits for the delay of the next attempt. It returns the context's
+// error if the context is cancelled before the delay expires.
+func (b *Backoff) Wait(ctx context.Context) error {
+	t := time.NewTimer(b.Next())
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Reset resets the delay after the connection was re-established.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// IsDisconnect returns whether err indicates that the connection to the
+// daemon was lost, and the stream can be resumed by reconnecting. The end of
+// the stream ([io.EOF]), context errors, and errors that are returned by the
+// daemon for invalid requests are not considered disconnects.
+func IsDisconnect(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, io.EOF),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded),
+		errdefs.IsInvalidArgument(err),
+		errdefs.IsNotFound(err),
+		errdefs.IsUnauthorized(err),
+		errdefs.IsPermissionDenied(err),
+		errdefs.IsConflict(err),
+		errdefs.IsNotImplemented(err):
+		return false
+	default:
+		return true
+	}
+}
+
+// WriteLost writes a marker to w to indicate that the connection was lost,
+// and when the next attempt to reconnect is made.
+func WriteLost(w io.Writer, err error, delay time.Duration) {
+	_, _ = fmt.Fprintf(w, "--- connection to the daemon lost: %v; reconnecting in %s\n", err, delay)
+}
+
+// WriteResumed writes a marker to w to indicate that the connection was
+// re-established, and that output is resumed from the given time. Output
+// between the last marker written by [WriteLost] and this marker may be
+// incomplete.
+func WriteResumed(w io.Writer, since time.Time) {
+	if since.IsZero() {
+		_, _ = fmt.Fprintln(w, "--- reconnected")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "--- reconnected; resuming from %s\n", since.UTC().Format(time.RFC3339Nano))
+}
+
+// Since formats t for the "since" option of the API.
+func Since(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Resume tracks the position in a stream of timestamped messages, so that
+// messages that are sent again after resuming the stream from the timestamp
+// of the last message can be skipped. The zero value is ready to use.
+type Resume struct {
+	last time.Time
+	// n is the number of messages that were seen with the last timestamp.
+	n int
+	// skip is the number of messages with the last timestamp to skip after
+	// resuming.
+	skip int
+}
+
+// Seen records a message with timestamp t, and returns whether the message
+// was seen before resuming the stream, and should be skipped.
+func (r *Resume) Seen(t time.Time) bool {
+	switch {
+	case t.IsZero():
+		return false
+	case t.Before(r.last):
+		return true
+	case t.Equal(r.last):
+		if r.skip > 0 {
+			r.skip--
+			return true
+		}
+		r.n++
+		return false
+	default:
+		r.last, r.n, r.skip = t, 1, 0
+		return false
+	}
+}
+
+// Last returns the timestamp of the last message, and prepares to skip the
+// messages with that timestamp that were already seen once the stream is
+// resumed from it.
+func (r *Resume) Last() time.Time {
+	r.skip = r.n
+	return r.last
+}
@@ -0,0 +1,77 @@
+package reconnect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBackoff(t *testing.T) {
+	var b Backoff
+	var delays []time.Duration
+	for i := 0; i < 9; i++ {
+		delays = append(delays, b.Next())
+	}
+	assert.Check(t, is.DeepEqual(delays, []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		30 * time.Second,
+		30 * time.Second,
+		30 * time.Second,
+	}))
+
+	b.Reset()
+	assert.Check(t, is.Equal(b.Delay(), 500*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	assert.Check(t, is.ErrorIs(b.Wait(ctx), context.Canceled))
+}
+
+func TestIsDisconnect(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected bool
+	}{
+		{err: nil},
+		{err: io.EOF},
+		{err: context.Canceled},
+		{err: errdefs.ErrNotFound.WithMessage("no such container")},
+		{err: errdefs.ErrInvalidArgument.WithMessage("invalid filter")},
+		{err: io.ErrUnexpectedEOF, expected: true},
+		{err: errors.New("error during connect: connection refused"), expected: true},
+		{err: errdefs.ErrUnavailable.WithMessage("daemon is shutting down"), expected: true},
+	} {
+		assert.Check(t, is.Equal(IsDisconnect(tc.err), tc.expected), "%v", tc.err)
+	}
+}
+
+func TestResume(t *testing.T) {
+	ts := func(sec int64) time.Time { return time.Unix(sec, 0) }
+
+	var r Resume
+	assert.Check(t, is.Equal(r.Last(), time.Time{}))
+	assert.Check(t, !r.Seen(ts(1)))
+	assert.Check(t, !r.Seen(ts(2)))
+	assert.Check(t, !r.Seen(ts(2)))
+	assert.Check(t, !r.Seen(time.Time{}), "messages without timestamp are never skipped")
+	assert.Check(t, is.Equal(r.Last(), ts(2)))
+
+	// after resuming from the last timestamp, the messages are sent again.
+	assert.Check(t, r.Seen(ts(1)))
+	assert.Check(t, r.Seen(ts(2)))
+	assert.Check(t, r.Seen(ts(2)))
+	assert.Check(t, !r.Seen(ts(2)), "expected new message with the same timestamp")
+	assert.Check(t, !r.Seen(ts(3)))
+	assert.Check(t, is.Equal(r.Last(), ts(3)))
+}
@@ -256,7 +256,14 @@ func validateHostPort(s string) error {
 // repository-name, and detects whether the registry is considered
 // "secure" (non-localhost).
 func NewIndexInfo(reposName reference.Named) *registry.IndexInfo {
-	indexName := normalizeIndexName(reference.Domain(reposName))
+	return NewIndexInfoForDomain(reference.Domain(reposName))
+}
+
+// NewIndexInfoForDomain creates a new [registry.IndexInfo] for the given
+// registry domain, and detects whether the registry is considered "secure"
+// (non-localhost).
+func NewIndexInfoForDomain(domain string) *registry.IndexInfo {
+	indexName := normalizeIndexName(domain)
 	if indexName == IndexName {
 		return &registry.IndexInfo{
 			Name:     IndexName,
@@ -0,0 +1,72 @@
+package registryclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/distribution/reference"
+	distributionclient "github.com/docker/distribution/registry/client"
+)
+
+// catalogPageSize is the number of repositories to request per page from
+// the catalog API.
+const catalogPageSize = 100
+
+// ListTags returns all tags of the repository of ref. It follows the
+// pagination links that are returned by the registry.
+func (c *client) ListTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.Tags(ctx).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", reference.FamiliarName(ref), err)
+	}
+	return tags, nil
+}
+
+// ListRepositories returns the names of all repositories in the catalog of
+// the registry at domain. Most registries restrict access to the catalog,
+// or don't implement it at all.
+func (c *client) ListRepositories(ctx context.Context, domain string) ([]string, error) {
+	repoEndpoint, err := newDefaultRegistryEndpoint(domain, c.insecureRegistry)
+	if err != nil {
+		return nil, err
+	}
+	httpTransport, err := c.getTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := distributionclient.NewRegistry(repoEndpoint.BaseURL(), httpTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		repos []string
+		last  string
+	)
+	for {
+		entries := make([]string, catalogPageSize)
+		n, err := reg.Repositories(ctx, entries, last)
+		repos = append(repos, entries[:n]...)
+		switch {
+		case errors.Is(err, io.EOF):
+			return repos, nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", domain, err)
+		case n == 0:
+			// Guard against registries that keep returning a "next" link
+			// without returning results.
+			return repos, nil
+		}
+		last = entries[n-1]
+	}
+}
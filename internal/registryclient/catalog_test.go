@@ -0,0 +1,99 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// paginate returns the page of items following last, and the value of the
+// Link header pointing to the next page, if any.
+func paginate(r *http.Request, items []string, defaultPageSize int) ([]string, string) {
+	n := defaultPageSize
+	if v := r.URL.Query().Get("n"); v != "" {
+		n, _ = strconv.Atoi(v)
+	}
+	start := sort.SearchStrings(items, r.URL.Query().Get("last"))
+	if last := r.URL.Query().Get("last"); last != "" && start < len(items) && items[start] == last {
+		start++
+	}
+	end := min(start+n, len(items))
+	page := items[start:end]
+	if end == len(items) {
+		return page, ""
+	}
+	return page, fmt.Sprintf(`<%s?n=%d&last=%s>; rel="next"`, r.URL.Path, n, page[len(page)-1])
+}
+
+func newTestCatalogRegistry(t *testing.T, repos []string, tags []string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/_catalog":
+			page, link := paginate(r, repos, 1000)
+			if link != "" {
+				w.Header().Set("Link", link)
+			}
+			_ = json.NewEncoder(w).Encode(map[string][]string{"repositories": page})
+		case "/v2/myrepo/tags/list":
+			page, link := paginate(r, tags, 2)
+			if link != "" {
+				w.Header().Set("Link", link)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "myrepo", "tags": page})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestListTags(t *testing.T) {
+	tags := []string{"1.0", "1.1", "2.0", "2.1", "latest"}
+	srv := newTestCatalogRegistry(t, nil, tags)
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	actual, err := c.ListTags(context.Background(), ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(actual, tags))
+}
+
+func TestListTagsNotFound(t *testing.T) {
+	srv := newTestCatalogRegistry(t, nil, nil)
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/no-such-repo")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	_, err = c.ListTags(context.Background(), ref)
+	assert.Check(t, is.ErrorContains(err, "failed to list tags of"))
+}
+
+func TestListRepositories(t *testing.T) {
+	repos := make([]string, 0, 250)
+	for i := range 250 {
+		repos = append(repos, fmt.Sprintf("repo%03d", i))
+	}
+	srv := newTestCatalogRegistry(t, repos, nil)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	actual, err := c.ListRepositories(context.Background(), strings.TrimPrefix(srv.URL, "http://"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(actual, repos))
+}
@@ -5,6 +5,7 @@
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/distribution/reference"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
@@ -12,6 +13,7 @@
 	distributionclient "github.com/docker/distribution/registry/client"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,8 +22,26 @@
 type RegistryClient interface {
 	GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	GetManifestListWithMetadata(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, ListMetadata, error)
 	MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
 	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
+	GetReferrers(ctx context.Context, ref reference.Named, artifactType string) ([]ocispec.Descriptor, error)
+	ListTags(ctx context.Context, ref reference.Named) ([]string, error)
+	ListRepositories(ctx context.Context, domain string) ([]string, error)
+	GetManifestDigest(ctx context.Context, ref reference.Named) (digest.Digest, error)
+	GetImageCreated(ctx context.Context, ref reference.Named) (time.Time, error)
+	DeleteManifest(ctx context.Context, ref reference.Canonical) error
+}
+
+// ListMetadata holds the properties of a manifest list that are not part of
+// its image manifests.
+type ListMetadata struct {
+	// OCI indicates that the manifest list is an OCI image index.
+	OCI bool
+	// ArtifactType is the artifact type of the OCI image index.
+	ArtifactType string
+	// Annotations are the annotations of the OCI image index.
+	Annotations map[string]string
 }
 
 // NewRegistryClient returns a new RegistryClient with a resolver
@@ -124,6 +144,17 @@ func (c *client) getRepositoryForReference(ctx context.Context, ref reference.Na
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse repo name from %s: %w", ref, err)
 	}
+	httpTransport, err := c.getTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return distributionclient.NewRepository(repoName, repoEndpoint.BaseURL(), httpTransport)
+}
+
+// getTransportForRepoEndpoint returns an authenticated transport for the
+// endpoint. If the endpoint doesn't use TLS, and --insecure was set, the
+// URL of the endpoint is updated to use plain HTTP.
+func (c *client) getTransportForRepoEndpoint(ctx context.Context, repoEndpoint repositoryEndpoint) (http.RoundTripper, error) {
 	httpTransport, err := c.getHTTPTransportForRepoEndpoint(ctx, repoEndpoint)
 	if err != nil {
 		if !strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") {
@@ -141,16 +172,15 @@ func (c *client) getRepositoryForReference(ctx context.Context, ref reference.Na
 			}
 		}
 	}
-	return distributionclient.NewRepository(repoName, repoEndpoint.BaseURL(), httpTransport)
+	return httpTransport, nil
 }
 
 func (c *client) getHTTPTransportForRepoEndpoint(ctx context.Context, repoEndpoint repositoryEndpoint) (http.RoundTripper, error) {
 	httpTransport, err := getHTTPTransport(
 		c.authConfigResolver(ctx, repoEndpoint.indexInfo.Name),
 		repoEndpoint.endpoint,
-		repoEndpoint.repoName,
+		repoEndpoint.scope(),
 		c.userAgent,
-		repoEndpoint.actions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure transport: %w", err)
@@ -173,15 +203,24 @@ func (c *client) GetManifest(ctx context.Context, ref reference.Named) (manifest
 
 // GetManifestList returns a list of ImageManifest for the reference
 func (c *client) GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+	result, _, err := c.GetManifestListWithMetadata(ctx, ref)
+	return result, err
+}
+
+// GetManifestListWithMetadata returns a list of ImageManifest for the
+// reference, and the properties of the list that are not part of its
+// manifests, such as the artifact type and annotations of an OCI image index.
+func (c *client) GetManifestListWithMetadata(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, ListMetadata, error) {
 	result := []manifesttypes.ImageManifest{}
+	var meta ListMetadata
 	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
 		var err error
-		result, err = fetchList(ctx, repo, ref)
+		result, meta, err = fetchList(ctx, repo, ref)
 		return len(result) > 0, err
 	}
 
 	err := c.iterateEndpoints(ctx, ref, fetch)
-	return result, err
+	return result, meta, err
 }
 
 func getManifestOptionsFromReference(ref reference.Named) (digest.Digest, []distribution.ManifestServiceOption, error) {
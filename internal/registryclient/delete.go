@@ -0,0 +1,150 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+)
+
+// GetManifestDigest resolves ref to the digest of the manifest, or manifest
+// list, it refers to. If ref is a digested reference, its digest is returned
+// as-is.
+func (c *client) GetManifestDigest(ctx context.Context, ref reference.Named) (digest.Digest, error) {
+	if digested, ok := ref.(reference.Canonical); ok {
+		return digested.Digest(), nil
+	}
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return "", err
+	}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return "", err
+	}
+	return resolveDigest(ctx, repo, ref)
+}
+
+// resolveDigest resolves the tag of ref to a digest, without fetching the
+// manifest.
+func resolveDigest(ctx context.Context, repo distribution.Repository, ref reference.Named) (digest.Digest, error) {
+	dgst, _, err := getManifestOptionsFromReference(ref)
+	if err != nil {
+		return "", err
+	}
+	if dgst != "" {
+		return dgst, nil
+	}
+	tagged, ok := ref.(reference.NamedTagged)
+	if !ok {
+		return "", fmt.Errorf("%s no tag or digest", ref)
+	}
+	desc, err := repo.Tags(ctx).Get(ctx, tagged.Tag())
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return desc.Digest, nil
+}
+
+// GetImageCreated returns the creation time recorded in the image config of
+// ref. For manifest lists, the most recent creation time of the images in
+// the list is returned. A zero time is returned if the image config has no
+// creation time.
+func (c *client) GetImageCreated(ctx context.Context, ref reference.Named) (time.Time, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return time.Time{}, err
+	}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return time.Time{}, err
+	}
+	manifest, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return getCreated(ctx, repo, manifest)
+}
+
+func getCreated(ctx context.Context, repo distribution.Repository, manifest distribution.Manifest) (time.Time, error) {
+	switch v := manifest.(type) {
+	case *schema2.DeserializedManifest:
+		return getConfigCreated(ctx, repo, v.Target().Digest)
+	case *ocischema.DeserializedManifest:
+		return getConfigCreated(ctx, repo, v.Target().Digest)
+	case *manifestlist.DeserializedManifestList:
+		manSvc, err := repo.Manifests(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		var created time.Time
+		for _, desc := range v.Manifests {
+			if desc.Platform.OS == "unknown" {
+				// Skip attestation manifests, which have no image config.
+				continue
+			}
+			m, err := manSvc.Get(ctx, desc.Digest)
+			if err != nil {
+				return time.Time{}, err
+			}
+			t, err := getCreated(ctx, repo, m)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if t.After(created) {
+				created = t
+			}
+		}
+		return created, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported manifest format: %T", manifest)
+	}
+}
+
+func getConfigCreated(ctx context.Context, repo distribution.Repository, dgst digest.Digest) (time.Time, error) {
+	configJSON, err := pullManifestSchemaV2ImageConfig(ctx, dgst, repo)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var config struct {
+		Created *time.Time `json:"created,omitempty"`
+	}
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		return time.Time{}, fmt.Errorf("invalid image config %s: %w", dgst, err)
+	}
+	if config.Created == nil {
+		return time.Time{}, nil
+	}
+	return *config.Created, nil
+}
+
+// DeleteManifest deletes the manifest with the digest of ref from the
+// registry. Deleting a manifest also removes all tags that refer to it.
+// Registries may not support deleting manifests, or require additional
+// permissions to do so.
+func (c *client) DeleteManifest(ctx context.Context, ref reference.Canonical) error {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return err
+	}
+	repoEndpoint.actions = []string{"pull", "delete"}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return err
+	}
+	manifestService, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	if err := manifestService.Delete(ctx, ref.Digest()); err != nil {
+		return fmt.Errorf("failed to delete manifest %s: %w", ref, err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type testBlob struct {
+	mediaType string
+	content   []byte
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	assert.NilError(t, err)
+	return b
+}
+
+// newTestImageRegistry returns a registry serving an image with the given
+// creation time as "myrepo:image", and a manifest list containing that image
+// and an older one as "myrepo:list". Deleted manifests are recorded in deleted.
+func newTestImageRegistry(t *testing.T, created time.Time, deleted *[]digest.Digest) (*httptest.Server, map[string]digest.Digest) {
+	t.Helper()
+	blobs := map[digest.Digest]testBlob{}
+	add := func(mediaType string, content []byte) digest.Digest {
+		dgst := digest.FromBytes(content)
+		blobs[dgst] = testBlob{mediaType: mediaType, content: content}
+		return dgst
+	}
+	addImage := func(created time.Time) (digest.Digest, int) {
+		config := mustJSON(t, map[string]any{"created": created, "os": "linux", "architecture": "amd64"})
+		configDigest := add(schema2.MediaTypeImageConfig, config)
+		m := mustJSON(t, map[string]any{
+			"schemaVersion": 2,
+			"mediaType":     schema2.MediaTypeManifest,
+			"config":        map[string]any{"mediaType": schema2.MediaTypeImageConfig, "digest": configDigest, "size": len(config)},
+			"layers":        []any{},
+		})
+		return add(schema2.MediaTypeManifest, m), len(m)
+	}
+
+	imageDigest, imageSize := addImage(created)
+	olderDigest, olderSize := addImage(created.Add(-24 * time.Hour))
+	list := mustJSON(t, map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     manifestlist.MediaTypeManifestList,
+		"manifests": []any{
+			map[string]any{"mediaType": schema2.MediaTypeManifest, "digest": olderDigest, "size": olderSize, "platform": map[string]any{"os": "linux", "architecture": "arm64"}},
+			map[string]any{"mediaType": schema2.MediaTypeManifest, "digest": imageDigest, "size": imageSize, "platform": map[string]any{"os": "linux", "architecture": "amd64"}},
+		},
+	})
+	tags := map[string]digest.Digest{
+		"image": imageDigest,
+		"list":  add(manifestlist.MediaTypeManifestList, list),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		rest, ok := strings.CutPrefix(r.URL.Path, "/v2/myrepo/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		kind, ref, _ := strings.Cut(rest, "/")
+		dgst := digest.Digest(ref)
+		if d, ok := tags[ref]; ok && kind == "manifests" {
+			dgst = d
+		}
+		blob, ok := blobs[dgst]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			*deleted = append(*deleted, dgst)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", blob.mediaType)
+		w.Header().Set("Docker-Content-Digest", dgst.String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(blob.content)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(blob.content)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, tags
+}
+
+func TestGetManifestDigest(t *testing.T) {
+	srv, tags := newTestImageRegistry(t, time.Now(), nil)
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo:list")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	dgst, err := c.GetManifestDigest(context.Background(), ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(dgst, tags["list"]))
+}
+
+func TestGetImageCreated(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv, _ := newTestImageRegistry(t, created, nil)
+
+	for _, tag := range []string{"image", "list"} {
+		t.Run(tag, func(t *testing.T) {
+			ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo:" + tag)
+			assert.NilError(t, err)
+
+			c := NewRegistryClient(noAuth, "test", true)
+			actual, err := c.GetImageCreated(context.Background(), ref)
+			assert.NilError(t, err)
+			assert.Check(t, actual.Equal(created), "expected %s, got %s", created, actual)
+		})
+	}
+}
+
+func TestDeleteManifest(t *testing.T) {
+	var deleted []digest.Digest
+	srv, tags := newTestImageRegistry(t, time.Now(), &deleted)
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo")
+	assert.NilError(t, err)
+	ref, err := reference.WithDigest(named, tags["image"])
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	assert.NilError(t, c.DeleteManifest(context.Background(), ref))
+	assert.Check(t, is.DeepEqual(deleted, []digest.Digest{tags["image"]}))
+
+	ref, err = reference.WithDigest(named, digest.FromString("missing"))
+	assert.NilError(t, err)
+	assert.Check(t, is.ErrorContains(c.DeleteManifest(context.Background(), ref), "failed to delete manifest"))
+}
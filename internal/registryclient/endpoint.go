@@ -27,9 +27,33 @@ func (r repositoryEndpoint) BaseURL() string {
 	return r.endpoint.URL.String()
 }
 
+// scope returns the scope to request when authenticating to the endpoint.
+// Endpoints without a repository name are used for the registry's catalog.
+func (r repositoryEndpoint) scope() auth.Scope {
+	if r.repoName == "" {
+		return auth.RegistryScope{Name: "catalog", Actions: []string{"*"}}
+	}
+	actions := r.actions
+	if len(actions) == 0 {
+		actions = []string{"pull"}
+	}
+	return auth.RepositoryScope{Repository: r.repoName, Actions: actions}
+}
+
 func newDefaultRepositoryEndpoint(ref reference.Named, insecure bool) (repositoryEndpoint, error) {
-	indexInfo := registry.NewIndexInfo(ref)
-	endpoint, err := getDefaultEndpoint(ref, !indexInfo.Secure)
+	repoEndpoint, err := newDefaultRegistryEndpoint(reference.Domain(ref), insecure)
+	if err != nil {
+		return repositoryEndpoint{}, err
+	}
+	repoEndpoint.repoName = reference.Path(reference.TrimNamed(ref))
+	return repoEndpoint, nil
+}
+
+// newDefaultRegistryEndpoint returns an endpoint for the registry at domain,
+// which is not bound to a repository.
+func newDefaultRegistryEndpoint(domain string, insecure bool) (repositoryEndpoint, error) {
+	indexInfo := registry.NewIndexInfoForDomain(domain)
+	endpoint, err := getDefaultEndpoint(domain, !indexInfo.Secure)
 	if err != nil {
 		return repositoryEndpoint{}, err
 	}
@@ -37,18 +61,17 @@ func newDefaultRepositoryEndpoint(ref reference.Named, insecure bool) (repositor
 		endpoint.TLSConfig.InsecureSkipVerify = true
 	}
 	return repositoryEndpoint{
-		repoName:  reference.Path(reference.TrimNamed(ref)),
 		indexInfo: indexInfo,
 		endpoint:  endpoint,
 	}, nil
 }
 
-func getDefaultEndpoint(repoName reference.Named, insecure bool) (registry.APIEndpoint, error) {
+func getDefaultEndpoint(domain string, insecure bool) (registry.APIEndpoint, error) {
 	registryService, err := registry.NewService(registry.ServiceOptions{})
 	if err != nil {
 		return registry.APIEndpoint{}, err
 	}
-	endpoints, err := registryService.Endpoints(context.TODO(), reference.Domain(repoName))
+	endpoints, err := registryService.Endpoints(context.TODO(), domain)
 	if err != nil {
 		return registry.APIEndpoint{}, err
 	}
@@ -65,7 +88,7 @@ func getDefaultEndpoint(repoName reference.Named, insecure bool) (registry.APIEn
 }
 
 // getHTTPTransport builds a transport for use in communicating with a registry
-func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.APIEndpoint, repoName, userAgent string, actions []string) (http.RoundTripper, error) {
+func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.APIEndpoint, scope auth.Scope, userAgent string) (http.RoundTripper, error) {
 	// get the http transport, this will be used in a client to upload manifest
 	base := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
@@ -88,11 +111,12 @@ func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.API
 		passThruTokenHandler := &existingTokenHandler{token: authConfig.RegistryToken}
 		modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, passThruTokenHandler))
 	} else {
-		if len(actions) == 0 {
-			actions = []string{"pull"}
-		}
 		creds := &staticCredentialStore{authConfig: &authConfig}
-		tokenHandler := auth.NewTokenHandler(authTransport, creds, repoName, actions...)
+		tokenHandler := auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+			Transport:   authTransport,
+			Credentials: creds,
+			Scopes:      []auth.Scope{scope},
+		})
 		basicHandler := auth.NewBasicHandler(creds)
 		modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler))
 	}
@@ -41,17 +41,35 @@ func fetchManifest(ctx context.Context, repo distribution.Repository, ref refere
 	return types.ImageManifest{}, fmt.Errorf("%s is not a manifest", ref)
 }
 
-func fetchList(ctx context.Context, repo distribution.Repository, ref reference.Named) ([]types.ImageManifest, error) {
+func fetchList(ctx context.Context, repo distribution.Repository, ref reference.Named) ([]types.ImageManifest, ListMetadata, error) {
 	manifest, err := getManifest(ctx, repo, ref)
 	if err != nil {
-		return nil, err
+		return nil, ListMetadata{}, err
 	}
 
 	switch v := manifest.(type) {
 	case *manifestlist.DeserializedManifestList:
-		return pullManifestList(ctx, ref, repo, *v)
+		var idx *ocispec.Index
+		meta := ListMetadata{}
+		if v.MediaType == ocispec.MediaTypeImageIndex {
+			// The artifact types and the annotations of the index are not
+			// part of a manifest list, so they're taken from the payload.
+			_, payload, err := v.Payload()
+			if err != nil {
+				return nil, ListMetadata{}, err
+			}
+			idx = &ocispec.Index{}
+			if err := json.Unmarshal(payload, idx); err != nil {
+				return nil, ListMetadata{}, err
+			}
+			meta.OCI = true
+			meta.ArtifactType = idx.ArtifactType
+			meta.Annotations = idx.Annotations
+		}
+		infos, err := pullManifestList(ctx, ref, repo, *v, idx)
+		return infos, meta, err
 	default:
-		return nil, fmt.Errorf("unsupported manifest format: %v", v)
+		return nil, ListMetadata{}, fmt.Errorf("unsupported manifest format: %v", v)
 	}
 }
 
@@ -152,13 +170,13 @@ func validateManifestDigest(ref reference.Named, mfst distribution.Manifest) (oc
 
 // pullManifestList handles "manifest lists" which point to various
 // platform-specific manifests.
-func pullManifestList(ctx context.Context, ref reference.Named, repo distribution.Repository, mfstList manifestlist.DeserializedManifestList) ([]types.ImageManifest, error) {
+func pullManifestList(ctx context.Context, ref reference.Named, repo distribution.Repository, mfstList manifestlist.DeserializedManifestList, idx *ocispec.Index) ([]types.ImageManifest, error) {
 	if _, err := validateManifestDigest(ref, mfstList); err != nil {
 		return nil, err
 	}
 
 	infos := make([]types.ImageManifest, 0, len(mfstList.Manifests))
-	for _, manifestDescriptor := range mfstList.Manifests {
+	for i, manifestDescriptor := range mfstList.Manifests {
 		manSvc, err := repo.Manifests(ctx)
 		if err != nil {
 			return nil, err
@@ -189,6 +207,10 @@ func pullManifestList(ctx context.Context, ref reference.Named, repo distributio
 		// Replace platform from config
 		p := manifestDescriptor.Platform
 		imageManifest.Descriptor.Platform = types.OCIPlatform(&p)
+		imageManifest.Descriptor.Annotations = manifestDescriptor.Annotations
+		if idx != nil && i < len(idx.Manifests) {
+			imageManifest.Descriptor.ArtifactType = idx.Manifests[i].ArtifactType
+		}
 
 		infos = append(infos, imageManifest)
 	}
@@ -0,0 +1,81 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestGetManifestListWithMetadata(t *testing.T) {
+	config := []byte(`{"architecture":"arm64","os":"linux"}`)
+	configDigest := digest.FromBytes(config)
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: configDigest, Size: int64(len(config))},
+		Layers:    []ocispec.Descriptor{},
+	})
+	assert.NilError(t, err)
+	manifestDigest := digest.FromBytes(manifest)
+	index, err := json.Marshal(ocispec.Index{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageIndex,
+		ArtifactType: "application/vnd.example.index",
+		Manifests: []ocispec.Descriptor{{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: "application/vnd.example.artifact",
+			Digest:       manifestDigest,
+			Size:         int64(len(manifest)),
+			Annotations:  map[string]string{"org.opencontainers.image.title": "arm64"},
+			Platform:     &ocispec.Platform{OS: "linux", Architecture: "arm64"},
+		}},
+		Annotations: map[string]string{"org.opencontainers.image.version": "1.0"},
+	})
+	assert.NilError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/myrepo/manifests/latest":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(index).String())
+			_, _ = w.Write(index)
+		case "/v2/myrepo/manifests/" + manifestDigest.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+			_, _ = w.Write(manifest)
+		case "/v2/myrepo/blobs/" + configDigest.String():
+			_, _ = w.Write(config)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo:latest")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	list, meta, err := c.GetManifestListWithMetadata(context.Background(), ref)
+	assert.NilError(t, err)
+	assert.Check(t, meta.OCI)
+	assert.Check(t, is.Equal(meta.ArtifactType, "application/vnd.example.index"))
+	assert.Check(t, is.DeepEqual(meta.Annotations, map[string]string{"org.opencontainers.image.version": "1.0"}))
+	assert.Assert(t, is.Len(list, 1))
+	assert.Check(t, is.Equal(list[0].Descriptor.ArtifactType, "application/vnd.example.artifact"))
+	assert.Check(t, is.DeepEqual(list[0].Descriptor.Annotations, map[string]string{"org.opencontainers.image.title": "arm64"}))
+	assert.Check(t, is.Equal(list[0].Descriptor.Platform.Architecture, "arm64"))
+}
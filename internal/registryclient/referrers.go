@@ -0,0 +1,221 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/distribution/reference"
+	v2 "github.com/docker/distribution/registry/api/v2"
+	distributionclient "github.com/docker/distribution/registry/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxIndexSize is the maximum size of an image index to read from the
+	// registry.
+	maxIndexSize = 4 * 1024 * 1024
+
+	// maxReferrersPages is the maximum number of pages of referrers to
+	// fetch, to guard against registries that keep returning a "next" link.
+	maxReferrersPages = 100
+)
+
+// GetReferrers returns the descriptors of the manifests that refer to the
+// manifest of ref, such as signatures, SBOMs and attestations. If artifactType
+// is not empty, only referrers with that artifact type are returned.
+//
+// It uses the referrers API of the registry (OCI distribution-spec v1.1), and
+// falls back to the referrers tag schema for registries that don't implement
+// the referrers API.
+func (c *client) GetReferrers(ctx context.Context, ref reference.Named, artifactType string) ([]ocispec.Descriptor, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return nil, err
+	}
+	httpTransport, err := c.getTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	repoName, err := reference.WithName(repoEndpoint.repoName)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := distributionclient.NewRepository(repoName, repoEndpoint.BaseURL(), httpTransport)
+	if err != nil {
+		return nil, err
+	}
+	dgst, err := resolveDigest(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &referrersClient{
+		client:   &http.Client{Transport: httpTransport},
+		baseURL:  repoEndpoint.BaseURL(),
+		repoName: repoEndpoint.repoName,
+	}
+	return rc.getReferrers(ctx, dgst, artifactType)
+}
+
+type referrersClient struct {
+	client   *http.Client
+	baseURL  string
+	repoName string
+}
+
+func (rc *referrersClient) getReferrers(ctx context.Context, dgst digest.Digest, artifactType string) ([]ocispec.Descriptor, error) {
+	ub, err := v2.NewURLBuilderFromString(rc.baseURL, false)
+	if err != nil {
+		return nil, err
+	}
+	base, err := ub.BuildBaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	u := base + rc.repoName + "/referrers/" + dgst.String()
+	if artifactType != "" {
+		u += "?" + url.Values{"artifactType": {artifactType}}.Encode()
+	}
+	idx, resp, err := rc.getIndex(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		referrers := filterReferrers(idx.Manifests, artifactType, resp)
+		return rc.getNextPages(ctx, referrers, artifactType, resp)
+	case http.StatusNotFound:
+		// The registry doesn't support the referrers API; fall back to
+		// the referrers tag schema ("<alg>-<ref>").
+		logrus.Debugf("referrers API not supported for %s, falling back to tag schema", rc.repoName)
+		tag := dgst.Algorithm().String() + "-" + dgst.Encoded()
+		idx, resp, err = rc.getIndex(ctx, base+rc.repoName+"/manifests/"+tag)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("failed to get referrers tag %s for %s: unexpected status: %s", tag, rc.repoName, resp.Status)
+		}
+	default:
+		return nil, fmt.Errorf("failed to get referrers for %s@%s: unexpected status: %s", rc.repoName, dgst, resp.Status)
+	}
+
+	return filterReferrers(idx.Manifests, artifactType, resp), nil
+}
+
+// filterReferrers returns the referrers with the given artifact type, unless
+// the registry already filtered them, as indicated by the OCI-Filters-Applied
+// header of resp. Registries may only filter some pages, so the header must
+// be checked for each page.
+func filterReferrers(manifests []ocispec.Descriptor, artifactType string, resp *http.Response) []ocispec.Descriptor {
+	if artifactType == "" || strings.Contains(resp.Header.Get("OCI-Filters-Applied"), "artifactType") {
+		return manifests
+	}
+	referrers := make([]ocispec.Descriptor, 0, len(manifests))
+	for _, m := range manifests {
+		if m.ArtifactType == artifactType {
+			referrers = append(referrers, m)
+		}
+	}
+	return referrers
+}
+
+// getNextPages follows the "next" links in the Link header of the referrers
+// API, and appends the referrers of each page to manifests.
+func (rc *referrersClient) getNextPages(ctx context.Context, manifests []ocispec.Descriptor, artifactType string, resp *http.Response) ([]ocispec.Descriptor, error) {
+	for pages := 1; ; pages++ {
+		next, err := nextLink(resp)
+		if err != nil || next == "" {
+			return manifests, err
+		}
+		if pages >= maxReferrersPages {
+			return nil, fmt.Errorf("failed to get referrers for %s: more than %d pages", rc.repoName, maxReferrersPages)
+		}
+		var idx ocispec.Index
+		idx, resp, err = rc.getIndex(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to get referrers for %s: unexpected status: %s", rc.repoName, resp.Status)
+		}
+		manifests = append(manifests, filterReferrers(idx.Manifests, artifactType, resp)...)
+	}
+}
+
+// nextLink returns the URL of the next page from the Link header of resp
+// (RFC 8288), resolved against the URL of the request. It returns an empty
+// string if there's no next page.
+func nextLink(resp *http.Response) (string, error) {
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			target, params, _ := strings.Cut(strings.TrimSpace(link), ";")
+			if !isNextRel(params) {
+				continue
+			}
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				return "", fmt.Errorf("invalid Link header: %q", header)
+			}
+			u, err := resp.Request.URL.Parse(target[1 : len(target)-1])
+			if err != nil {
+				return "", fmt.Errorf("invalid Link header: %w", err)
+			}
+			return u.String(), nil
+		}
+	}
+	return "", nil
+}
+
+// isNextRel returns whether the parameters of a link contain rel="next".
+func isNextRel(params string) bool {
+	for _, p := range strings.Split(params, ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		if !strings.EqualFold(k, "rel") {
+			continue
+		}
+		for _, rel := range strings.Fields(strings.Trim(v, `"`)) {
+			if strings.EqualFold(rel, "next") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getIndex fetches the image index at u. The index is only decoded if the
+// registry responded with a 200 status.
+func (rc *referrersClient) getIndex(ctx context.Context, u string) (ocispec.Index, *http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return ocispec.Index{}, nil, err
+	}
+	req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return ocispec.Index{}, nil, err
+	}
+	defer resp.Body.Close()
+
+	var idx ocispec.Index
+	if resp.StatusCode != http.StatusOK {
+		return idx, resp, nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxIndexSize)).Decode(&idx); err != nil {
+		return ocispec.Index{}, nil, fmt.Errorf("failed to decode image index: %w", err)
+	}
+	return idx, resp, nil
+}
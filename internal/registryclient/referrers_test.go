@@ -0,0 +1,264 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	registrytypes "github.com/moby/moby/api/types/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const (
+	subjectDigest = digest.Digest("sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b")
+	sbomType      = "application/spdx+json"
+	signatureType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+)
+
+var testReferrers = ocispec.Index{
+	MediaType: ocispec.MediaTypeImageIndex,
+	Manifests: []ocispec.Descriptor{
+		{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: sbomType,
+			Digest:       "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+			Size:         1024,
+			Annotations:  map[string]string{"org.opencontainers.image.created": "2025-01-01T00:00:00Z"},
+		},
+		{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: signatureType,
+			Digest:       "sha256:3c5f5e9a4e1b2c1c0f7a4f0e1a5e6bd1c33f4ab2d5f1f9c6f1a1e3a2b4c5d6e7",
+			Size:         512,
+		},
+	},
+}
+
+// newTestRegistry returns a registry serving testReferrers for subjectDigest,
+// either through the referrers API, or through the referrers tag schema.
+func newTestRegistry(t *testing.T, referrersAPI bool, filter bool) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v2/myrepo/manifests/latest":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", subjectDigest.String())
+			w.Header().Set("Content-Length", "1024")
+			w.WriteHeader(http.StatusOK)
+		case referrersAPI && r.URL.Path == "/v2/myrepo/referrers/"+subjectDigest.String():
+			idx := testReferrers
+			if at := r.URL.Query().Get("artifactType"); at != "" && filter {
+				w.Header().Set("OCI-Filters-Applied", "artifactType")
+				idx.Manifests = nil
+				for _, m := range testReferrers.Manifests {
+					if m.ArtifactType == at {
+						idx.Manifests = append(idx.Manifests, m)
+					}
+				}
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			_ = json.NewEncoder(w).Encode(idx)
+		case !referrersAPI && r.URL.Path == "/v2/myrepo/manifests/sha256-"+subjectDigest.Encoded():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			_ = json.NewEncoder(w).Encode(testReferrers)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func noAuth(context.Context, string) registrytypes.AuthConfig {
+	return registrytypes.AuthConfig{}
+}
+
+func TestGetReferrers(t *testing.T) {
+	testCases := []struct {
+		doc          string
+		referrersAPI bool
+		filter       bool
+		ref          string
+		artifactType string
+		expected     []digest.Digest
+	}{
+		{
+			doc:          "referrers API by tag",
+			referrersAPI: true,
+			ref:          "myrepo:latest",
+			expected:     []digest.Digest{testReferrers.Manifests[0].Digest, testReferrers.Manifests[1].Digest},
+		},
+		{
+			doc:          "referrers API by digest",
+			referrersAPI: true,
+			ref:          "myrepo@" + subjectDigest.String(),
+			expected:     []digest.Digest{testReferrers.Manifests[0].Digest, testReferrers.Manifests[1].Digest},
+		},
+		{
+			doc:          "referrers API filtered by registry",
+			referrersAPI: true,
+			filter:       true,
+			ref:          "myrepo:latest",
+			artifactType: sbomType,
+			expected:     []digest.Digest{testReferrers.Manifests[0].Digest},
+		},
+		{
+			doc:          "referrers API filtered by client",
+			referrersAPI: true,
+			ref:          "myrepo:latest",
+			artifactType: signatureType,
+			expected:     []digest.Digest{testReferrers.Manifests[1].Digest},
+		},
+		{
+			doc:      "tag schema fallback",
+			ref:      "myrepo:latest",
+			expected: []digest.Digest{testReferrers.Manifests[0].Digest, testReferrers.Manifests[1].Digest},
+		},
+		{
+			doc:          "tag schema fallback filtered",
+			ref:          "myrepo:latest",
+			artifactType: sbomType,
+			expected:     []digest.Digest{testReferrers.Manifests[0].Digest},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			srv := newTestRegistry(t, tc.referrersAPI, tc.filter)
+			ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/" + tc.ref)
+			assert.NilError(t, err)
+
+			c := NewRegistryClient(noAuth, "test", true)
+			referrers, err := c.GetReferrers(context.Background(), ref, tc.artifactType)
+			assert.NilError(t, err)
+
+			actual := make([]digest.Digest, 0, len(referrers))
+			for _, r := range referrers {
+				actual = append(actual, r.Digest)
+			}
+			assert.Check(t, is.DeepEqual(actual, tc.expected))
+		})
+	}
+}
+
+func TestGetReferrersNone(t *testing.T) {
+	srv := newTestRegistry(t, false, false)
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	referrers, err := c.GetReferrers(context.Background(), ref, "")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(referrers, 0))
+}
+
+// newPagedTestRegistry returns a registry serving testReferrers for
+// subjectDigest through the referrers API, one referrer per page. If loop is
+// set, the last page links back to the first page. The registry only applies
+// the artifactType filter to the first page.
+func newPagedTestRegistry(t *testing.T, loop bool) *httptest.Server {
+	t.Helper()
+	referrersPath := "/v2/myrepo/referrers/" + subjectDigest.String()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case referrersPath:
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page >= len(testReferrers.Manifests) {
+				http.NotFound(w, r)
+				return
+			}
+			switch {
+			case page+1 < len(testReferrers.Manifests):
+				w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, referrersPath, page+1))
+			case loop:
+				w.Header().Set("Link", fmt.Sprintf(`<%s?page=0>; rel="next"`, referrersPath))
+			}
+			manifests := testReferrers.Manifests[page : page+1]
+			if at := r.URL.Query().Get("artifactType"); at != "" && page == 0 {
+				w.Header().Set("OCI-Filters-Applied", "artifactType")
+				if manifests[0].ArtifactType != at {
+					manifests = nil
+				}
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			_ = json.NewEncoder(w).Encode(ocispec.Index{
+				MediaType: ocispec.MediaTypeImageIndex,
+				Manifests: manifests,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetReferrersPaginated(t *testing.T) {
+	srv := newPagedTestRegistry(t, false)
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo@" + subjectDigest.String())
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	referrers, err := c.GetReferrers(context.Background(), ref, "")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(referrers, testReferrers.Manifests))
+
+	// the referrers of all pages are filtered.
+	referrers, err = c.GetReferrers(context.Background(), ref, signatureType)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(referrers, testReferrers.Manifests[1:]))
+
+	// pages that were not filtered by the registry are filtered.
+	referrers, err = c.GetReferrers(context.Background(), ref, sbomType)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(referrers, testReferrers.Manifests[:1]))
+}
+
+func TestGetReferrersPaginatedLoop(t *testing.T) {
+	srv := newPagedTestRegistry(t, true)
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/myrepo@" + subjectDigest.String())
+	assert.NilError(t, err)
+
+	c := NewRegistryClient(noAuth, "test", true)
+	_, err = c.GetReferrers(context.Background(), ref, "")
+	assert.Check(t, is.ErrorContains(err, "more than 100 pages"))
+}
+
+func TestNextLink(t *testing.T) {
+	base, err := url.Parse("https://registry.example.com/v2/myrepo/referrers/sha256:abc")
+	assert.NilError(t, err)
+	for _, tc := range []struct {
+		header   []string
+		expected string
+	}{
+		{},
+		{header: []string{`</v2/myrepo/referrers/sha256:abc?last=x>; rel="next"`}, expected: "https://registry.example.com/v2/myrepo/referrers/sha256:abc?last=x"},
+		{header: []string{`<https://other.example.com/page2>; rel=next`}, expected: "https://other.example.com/page2"},
+		{header: []string{`</first>; rel="first", </page2>; rel="next"`}, expected: "https://registry.example.com/page2"},
+		{header: []string{`</first>; rel="first"`, `</page2>; type="x"; rel="prev next"`}, expected: "https://registry.example.com/page2"},
+		{header: []string{`</prev>; rel="prev"`}},
+	} {
+		resp := &http.Response{Header: http.Header{"Link": tc.header}, Request: &http.Request{URL: base}}
+		actual, err := nextLink(resp)
+		assert.Check(t, err)
+		assert.Check(t, is.Equal(actual, tc.expected))
+	}
+}